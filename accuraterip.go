@@ -0,0 +1,235 @@
+package audiocd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// accurateRipFrame450Sector is the sector within each track over which
+// the AccurateRip offset-finding checksum is computed.
+const accurateRipFrame450Sector = 450
+
+// AccurateRipFrame450 computes the AccurateRip offset-finding checksum
+// over one sector of PCM data (588 stereo samples) in host byte order,
+// as returned by [*AudioCD.ReadSectors] without a ByteOrder. The AccurateRip database stores this checksum for sector 450
+// of each track, which allows the read offset of a drive to be found by
+// sliding the window over a range of samples, as CUETools does.
+//
+// Only the first [BytesPerSector] bytes of data are used.
+func AccurateRipFrame450(data []byte) uint32 {
+	if len(data) < BytesPerSector {
+		return 0
+	}
+	if !nativeLittleEndian {
+		data = bytes.Clone(data[:BytesPerSector])
+		swapBytes(data)
+	}
+	var crc uint32
+	for i := range SamplesPerSector {
+		sample := binary.LittleEndian.Uint32(data[i*4:])
+		crc += sample * uint32(i+1)
+	}
+	return crc
+}
+
+// AccurateRipFrame450 reads sector 450 of the given track (starting at 1)
+// and computes its AccurateRip offset-finding checksum. See
+// [AccurateRipFrame450] for details. The sector is read with
+// [*AudioCD.ReadSectors], so SampleOffset applies but ByteOrder,
+// Deemphasis and a processor don't, and the read position is kept.
+func (cd *AudioCD) AccurateRipFrame450(track int) (uint32, error) {
+	if !cd.IsOpen() {
+		return 0, os.ErrClosed
+	}
+	toc := cd.TOC()
+	if track < 1 || track > len(toc) {
+		return 0, ErrInvalidTrackNumber
+	}
+	t := toc[track-1]
+	if t.LengthSectors <= accurateRipFrame450Sector {
+		return 0, fmt.Errorf("audiocd: track %d is too short for an offset-finding checksum", track)
+	}
+
+	data := make([]byte, BytesPerSector)
+	_, err := cd.ReadSectors(t.StartSector+accurateRipFrame450Sector, data)
+	if err != nil {
+		return 0, err
+	}
+	if cd.swapsBytes() {
+		// back to host byte order
+		swapBytes(data)
+	}
+	return AccurateRipFrame450(data), nil
+}
+
+// DefaultOffsetSearch is the range of read offsets, in samples either
+// side of 0, searched by [*AudioCD.DetectReadOffset] by default. It
+// covers the offsets of known drives.
+const DefaultOffsetSearch = 5 * SamplesPerSector
+
+// DetectReadOffset finds the drive's read offset, for use as
+// SampleOffset, from the AccurateRip offset-finding checksums of a
+// track of a disc in the database (see [AccurateRipFrame450]). It
+// reads the audio around sector 450 of the track without offset
+// correction, and slides the checksum window over it until it matches
+// one of the checksums, which come from the different pressings of
+// the disc. The offset nearest 0 which matches is returned, or
+// [ErrOffsetNotFound] if none do.
+//
+// Offsets up to maxOffset samples either side of 0 are tried. If
+// maxOffset is 0, [DefaultOffsetSearch] is used. The read position is
+// unaffected.
+func (cd *AudioCD) DetectReadOffset(track int, checksums []uint32, maxOffset int) (int, error) {
+	if !cd.IsOpen() {
+		return 0, os.ErrClosed
+	}
+	toc := cd.TOC()
+	if track < 1 || track > len(toc) {
+		return 0, ErrInvalidTrackNumber
+	}
+	if maxOffset <= 0 {
+		maxOffset = DefaultOffsetSearch
+	}
+	margin := (maxOffset + SamplesPerSector - 1) / SamplesPerSector
+	sector := toc[track-1].StartSector + accurateRipFrame450Sector
+	if sector-margin < 0 || sector+1+margin > cd.LengthSectors() {
+		return 0, fmt.Errorf("audiocd: track %d is too close to the edge of the disc to search offsets of %d samples", track, maxOffset)
+	}
+
+	data := make([]byte, (2*margin+1)*BytesPerSector)
+	offset := cd.SampleOffset
+	cd.SampleOffset = 0
+	_, err := cd.ReadSectors(sector-margin, data)
+	cd.SampleOffset = offset
+	if err != nil {
+		return 0, err
+	}
+	if cd.swapsBytes() {
+		// back to host byte order
+		swapBytes(data)
+	}
+	return matchFrame450(data, margin*SamplesPerSector, maxOffset, checksums)
+}
+
+// matchFrame450 slides the offset-finding checksum window over PCM
+// data in host byte order whose sample at start is the first of the
+// window at offset 0, and returns the offset nearest 0 with a
+// checksum in want.
+func matchFrame450(data []byte, start, maxOffset int, want []uint32) (int, error) {
+	const frame = Channels * BytesPerSample
+	if !nativeLittleEndian {
+		data = bytes.Clone(data)
+		swapBytes(data)
+	}
+	samples := make([]uint32, len(data)/frame)
+	for i := range samples {
+		// as AccurateRipFrame450 reads them
+		samples[i] = binary.LittleEndian.Uint32(data[i*frame:])
+	}
+
+	// the checksum of the window at j is the sum of sample*k for k
+	// from 1. Moving it along a sample takes off each sample once and
+	// adds the new last sample 588 times.
+	j := start - maxOffset
+	var crc, sum uint32
+	for k := range SamplesPerSector {
+		crc += samples[j+k] * uint32(k+1)
+		sum += samples[j+k]
+	}
+	best, found := 0, false
+	for o := -maxOffset; o <= maxOffset; o++ {
+		if slices.Contains(want, crc) && (!found || abs(o) < abs(best)) {
+			best, found = o, true
+		}
+		if o == maxOffset {
+			break
+		}
+		next := samples[j+SamplesPerSector]
+		crc += SamplesPerSector*next - sum
+		sum += next - samples[j]
+		j++
+	}
+	if !found {
+		return 0, ErrOffsetNotFound
+	}
+	return best, nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// accurateRipSkip is the number of samples at the start of the first
+// track and the end of the last track which the AccurateRip checksums
+// leave out, since drives can't read them with every offset.
+const accurateRipSkip = 5 * SamplesPerSector
+
+// AccurateRipChecksums computes the AccurateRip v1 and v2 checksums
+// of a whole track of PCM data in host byte order. Set first and last
+// for the first and last audio tracks of the disc, whose edges aren't
+// counted.
+func AccurateRipChecksums(data []byte, first, last bool) (v1, v2 uint32) {
+	ar := newAccurateRipSum(first, last, len(data)/(Channels*BytesPerSample))
+	if !nativeLittleEndian {
+		data = bytes.Clone(data)
+		swapBytes(data)
+	}
+	ar.Write(data)
+	return ar.v1, ar.v2
+}
+
+// accurateRipSum computes the AccurateRip checksums of a track
+// as it streams, from little-endian PCM data.
+type accurateRipSum struct {
+	start, end uint64 // the range of samples counted, 1-based
+	n          uint64 // the number of the next sample
+	v1, v2     uint32
+	partial    []byte // part of a sample split across writes
+}
+
+func newAccurateRipSum(first, last bool, samples int) *accurateRipSum {
+	ar := &accurateRipSum{start: 1, end: uint64(samples), n: 1}
+	if first {
+		ar.start = accurateRipSkip
+	}
+	if last {
+		ar.end = uint64(max(samples-accurateRipSkip, 0))
+	}
+	return ar
+}
+
+func (ar *accurateRipSum) Write(p []byte) (int, error) {
+	size := len(p)
+	const frame = Channels * BytesPerSample
+	if len(ar.partial) > 0 {
+		need := frame - len(ar.partial)
+		if len(p) < need {
+			ar.partial = append(ar.partial, p...)
+			return size, nil
+		}
+		ar.add(binary.LittleEndian.Uint32(append(ar.partial, p[:need]...)))
+		ar.partial = ar.partial[:0]
+		p = p[need:]
+	}
+	for ; len(p) >= frame; p = p[frame:] {
+		ar.add(binary.LittleEndian.Uint32(p))
+	}
+	ar.partial = append(ar.partial, p...)
+	return size, nil
+}
+
+func (ar *accurateRipSum) add(sample uint32) {
+	if ar.n >= ar.start && ar.n <= ar.end {
+		// v1 wraps the product, v2 folds in the high half
+		product := uint64(sample) * ar.n
+		ar.v1 += uint32(product)
+		ar.v2 += uint32(product) + uint32(product>>32)
+	}
+	ar.n++
+}
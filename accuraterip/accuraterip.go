@@ -0,0 +1,218 @@
+// Package accuraterip checks rips against the [AccurateRip] database
+// of checksums submitted by other users, to confirm they're exact.
+//
+// Example:
+//
+//	report, err := cd.Rip(opts)
+//	...
+//	db, err := client.Lookup(ctx, audiocd.NewAccurateRipDiscID(cd.TOC()))
+//	...
+//	db.Verify(report)
+//	err = db.VerifyOffsets(cd, report, 0)
+//
+// [AccurateRip]: http://www.accuraterip.com
+package accuraterip
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/rabidaudio/audiocd"
+)
+
+// DefaultBaseURL is the location of the AccurateRip database.
+const DefaultBaseURL = "http://www.accuraterip.com/accuraterip/"
+
+// ErrNotFound is returned when the database has no entry for the disc.
+var ErrNotFound = errors.New("accuraterip: disc not found")
+
+// Client fetches database entries. The zero value is ready to use.
+type Client struct {
+	HTTPClient *http.Client // the client to make requests with. If nil, http.DefaultClient is used
+	BaseURL    string       // the database root. If empty, DefaultBaseURL is used
+	UserAgent  string
+}
+
+// Database holds the database entries for a disc. Each entry is one
+// pressing of the disc, or one version of the checksums.
+type Database struct {
+	ID      audiocd.AccurateRipDiscID
+	Entries []Entry
+}
+
+// Entry holds the checksums of the tracks of one pressing.
+type Entry struct {
+	Tracks []Track
+}
+
+// Track holds the checksums of one track.
+type Track struct {
+	Confidence int    // the number of submissions with this checksum
+	CRC        uint32 // the v1 or v2 checksum
+	Frame450   uint32 // the offset-finding checksum, see [audiocd.AccurateRipFrame450]
+}
+
+// Lookup fetches the database entries for a disc.
+//
+// Returns [ErrNotFound] if the disc isn't in the database.
+func (c *Client) Lookup(ctx context.Context, id audiocd.AccurateRipDiscID) (*Database, error) {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(base, "/")+"/"+id.Path(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("accuraterip: unexpected response: %v", res.Status)
+	}
+	return Parse(res.Body, id)
+}
+
+// Parse reads a database file, e.g. as returned by
+// [audiocd.AccurateRipDiscID.URL]. Entries for other discs are skipped.
+func Parse(r io.Reader, id audiocd.AccurateRipDiscID) (*Database, error) {
+	br := bufio.NewReader(r)
+	db := &Database{ID: id}
+	for {
+		var header [13]byte
+		_, err := io.ReadFull(br, header[:])
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("accuraterip: truncated entry: %w", err)
+		}
+		entry := Entry{Tracks: make([]Track, header[0])}
+		for i := range entry.Tracks {
+			var t [9]byte
+			if _, err := io.ReadFull(br, t[:]); err != nil {
+				return nil, fmt.Errorf("accuraterip: truncated entry: %w", io.ErrUnexpectedEOF)
+			}
+			entry.Tracks[i] = Track{
+				Confidence: int(t[0]),
+				CRC:        binary.LittleEndian.Uint32(t[1:]),
+				Frame450:   binary.LittleEndian.Uint32(t[5:]),
+			}
+		}
+		if int(header[0]) != id.Tracks ||
+			binary.LittleEndian.Uint32(header[1:]) != id.ID1 ||
+			binary.LittleEndian.Uint32(header[5:]) != id.ID2 ||
+			binary.LittleEndian.Uint32(header[9:]) != id.CDDB {
+			continue
+		}
+		db.Entries = append(db.Entries, entry)
+	}
+	if len(db.Entries) == 0 {
+		return nil, ErrNotFound
+	}
+	return db, nil
+}
+
+// Match returns the number of submissions matching the v1 or v2
+// checksum of an audio track, counted from 1, and the number of
+// submissions for the track.
+func (db *Database) Match(track int, v1, v2 uint32) (confidence, total int) {
+	for _, e := range db.Entries {
+		if track < 1 || track > len(e.Tracks) {
+			continue
+		}
+		t := e.Tracks[track-1]
+		total += t.Confidence
+		if t.CRC == v1 || t.CRC == v2 {
+			confidence += t.Confidence
+		}
+	}
+	return confidence, total
+}
+
+// Verify matches the checksums of the tracks of a rip, filling in the
+// AccurateRip confidence of each track. The hidden track isn't in
+// the database.
+func (db *Database) Verify(report *audiocd.RipReport) {
+	for i := range report.Tracks {
+		tp := &report.Tracks[i]
+		if tp.TrackNum < 1 {
+			continue
+		}
+		tp.AccurateRipConfidence, tp.AccurateRipTotal = db.Match(tp.TrackNum, tp.AccurateRipV1, tp.AccurateRipV2)
+		tp.AccurateRipOffset = 0
+	}
+}
+
+// VerifyOffsets checks the tracks of a verified rip which didn't match
+// against pressings of the disc at other offsets. The offset of each
+// pressing is found with [*audiocd.AudioCD.DetectReadOffset], searching
+// maxOffset samples either side, and the track is read again from cd
+// at that offset to compare the checksums. Matches are recorded with
+// the offset of the pressing.
+//
+// The track numbers are the disc's, which is expected to start at
+// track 1 like the database.
+func (db *Database) VerifyOffsets(cd *audiocd.AudioCD, report *audiocd.RipReport, maxOffset int) error {
+	for i := range report.Tracks {
+		tp := &report.Tracks[i]
+		if tp.TrackNum < 1 || tp.AccurateRipConfidence > 0 {
+			continue
+		}
+		var frames []uint32
+		for _, e := range db.Entries {
+			if tp.TrackNum <= len(e.Tracks) && e.Tracks[tp.TrackNum-1].Frame450 != 0 {
+				frames = append(frames, e.Tracks[tp.TrackNum-1].Frame450)
+			}
+		}
+		if len(frames) == 0 {
+			continue
+		}
+		offset, err := cd.DetectReadOffset(tp.TrackNum, frames, maxOffset)
+		if errors.Is(err, audiocd.ErrOffsetNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if offset == cd.SampleOffset {
+			continue
+		}
+
+		ripped := cd.SampleOffset
+		cd.SampleOffset = offset
+		r, err := cd.Rip(audiocd.RipOptions{
+			Tracks: []int{tp.TrackNum},
+			Output: func(audiocd.TrackPosition) (io.Writer, error) { return io.Discard, nil },
+		})
+		cd.SampleOffset = ripped
+		if err != nil {
+			return err
+		}
+		confidence, total := db.Match(tp.TrackNum, r.Tracks[0].AccurateRipV1, r.Tracks[0].AccurateRipV2)
+		if confidence > 0 {
+			tp.AccurateRipConfidence, tp.AccurateRipTotal = confidence, total
+			tp.AccurateRipOffset = offset - ripped
+		}
+	}
+	return nil
+}
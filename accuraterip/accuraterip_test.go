@@ -0,0 +1,80 @@
+package accuraterip
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rabidaudio/audiocd"
+	"github.com/stretchr/testify/assert"
+)
+
+var testTOC = []audiocd.TrackPosition{
+	{TrackNum: 1, StartSector: 0, LengthSectors: 6290},
+	{TrackNum: 2, StartSector: 6290, LengthSectors: 17021},
+}
+
+func appendEntry(b []byte, id audiocd.AccurateRipDiscID, tracks ...Track) []byte {
+	b = append(b, byte(len(tracks)))
+	b = binary.LittleEndian.AppendUint32(b, id.ID1)
+	b = binary.LittleEndian.AppendUint32(b, id.ID2)
+	b = binary.LittleEndian.AppendUint32(b, id.CDDB)
+	for _, t := range tracks {
+		b = append(b, byte(t.Confidence))
+		b = binary.LittleEndian.AppendUint32(b, t.CRC)
+		b = binary.LittleEndian.AppendUint32(b, t.Frame450)
+	}
+	return b
+}
+
+func TestLookup(t *testing.T) {
+	id := audiocd.NewAccurateRipDiscID(testTOC)
+	other := id
+	other.CDDB++
+
+	var data []byte
+	data = appendEntry(data, id, Track{12, 0x11111111, 0xAAAA}, Track{10, 0x22222222, 0xBBBB})
+	data = appendEntry(data, other, Track{99, 0x33333333, 0}, Track{99, 0x44444444, 0})
+	data = appendEntry(data, id, Track{3, 0x55555555, 0xCCCC}, Track{2, 0x66666666, 0xDDDD})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/accuraterip/"+id.Path() {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	defer srv.Close()
+
+	client := Client{BaseURL: srv.URL + "/accuraterip", HTTPClient: srv.Client()}
+	db, err := client.Lookup(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, db.Entries, 2)
+	assert.Equal(t, Track{3, 0x55555555, 0xCCCC}, db.Entries[1].Tracks[0])
+
+	report := &audiocd.RipReport{Tracks: []audiocd.TrackReport{
+		{TrackNum: 1, AccurateRipV1: 0x11111111, AccurateRipV2: 0x77777777},
+		{TrackNum: 2, AccurateRipV1: 0x88888888, AccurateRipV2: 0x66666666},
+	}}
+	db.Verify(report)
+	assert.Equal(t, 12, report.Tracks[0].AccurateRipConfidence)
+	assert.Equal(t, 15, report.Tracks[0].AccurateRipTotal)
+	assert.Equal(t, 2, report.Tracks[1].AccurateRipConfidence)
+	assert.Equal(t, 12, report.Tracks[1].AccurateRipTotal)
+
+	_, err = client.Lookup(context.Background(), other)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestParseTruncated(t *testing.T) {
+	id := audiocd.NewAccurateRipDiscID(testTOC)
+	data := appendEntry(nil, id, Track{1, 1, 1}, Track{1, 2, 2})
+	_, err := Parse(bytes.NewReader(data[:len(data)-3]), id)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,97 @@
+package audiocd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccurateRipFrame450(t *testing.T) {
+	// samples are in host byte order, as returned by Read, and the
+	// left channel is the low half of each 32-bit sample
+	data := make([]byte, BytesPerSector)
+	for i := 0; i < len(data); i += 4 {
+		binary.NativeEndian.PutUint16(data[i:], 1)
+	}
+	sum := uint32(588 * 589 / 2)
+	assert.Equal(t, sum, AccurateRipFrame450(data))
+	assert.Equal(t, uint32(0), AccurateRipFrame450(data[:100]))
+
+	clear(data)
+	for i := 0; i < len(data); i += 4 {
+		binary.NativeEndian.PutUint16(data[i+2:], 1)
+	}
+	assert.Equal(t, sum<<16, AccurateRipFrame450(data))
+}
+
+func TestAccurateRipChecksums(t *testing.T) {
+	const n = 3 * accurateRipSkip
+	data := make([]byte, n*4)
+	var v1, v2, v1Inner, v2Inner uint32
+	for i := range n {
+		sample := uint32(i)*2654435761 + 12345
+		// the left and right samples, which AccurateRip reads as
+		// one little-endian 32-bit sample
+		binary.NativeEndian.PutUint16(data[i*4:], uint16(sample))
+		binary.NativeEndian.PutUint16(data[i*4+2:], uint16(sample>>16))
+		product := uint64(sample) * uint64(i+1)
+		v1 += uint32(product)
+		v2 += uint32(product) + uint32(product>>32)
+		if i+1 >= accurateRipSkip && i < n-accurateRipSkip {
+			v1Inner += uint32(product)
+			v2Inner += uint32(product) + uint32(product>>32)
+		}
+	}
+
+	gotV1, gotV2 := AccurateRipChecksums(data, false, false)
+	assert.Equal(t, v1, gotV1)
+	assert.Equal(t, v2, gotV2)
+	assert.NotEqual(t, gotV1, gotV2)
+
+	// the first and last tracks skip five sectors at their edges
+	gotV1, gotV2 = AccurateRipChecksums(data, true, true)
+	assert.Equal(t, v1Inner, gotV1)
+	assert.Equal(t, v2Inner, gotV2)
+
+	// streaming in odd pieces gives the same result
+	le := bytes.Clone(data)
+	if !nativeLittleEndian {
+		swapBytes(le)
+	}
+	ar := newAccurateRipSum(true, true, n)
+	for p := le; len(p) > 0; {
+		k := min(len(p), 7)
+		ar.Write(p[:k])
+		p = p[k:]
+	}
+	assert.Equal(t, v1Inner, ar.v1)
+	assert.Equal(t, v2Inner, ar.v2)
+}
+
+func TestMatchFrame450(t *testing.T) {
+	const margin = 2
+	data := make([]byte, (2*margin+1)*BytesPerSector)
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := range data {
+		data[i] = byte(rng.Uint32())
+	}
+	start := margin * SamplesPerSector
+	frame := Channels * BytesPerSample
+	at := func(o int) uint32 {
+		return AccurateRipFrame450(data[(start+o)*frame:])
+	}
+
+	offset, err := matchFrame450(data, start, 1000, []uint32{1, at(-667)})
+	failIfErr(t, err)
+	assert.Equal(t, -667, offset)
+
+	offset, err = matchFrame450(data, start, 1000, []uint32{at(1000), at(6)})
+	failIfErr(t, err)
+	assert.Equal(t, 6, offset)
+
+	_, err = matchFrame450(data, start, 1000, []uint32{at(1001)})
+	assert.ErrorIs(t, err, ErrOffsetNotFound)
+}
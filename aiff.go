@@ -0,0 +1,143 @@
+package audiocd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+)
+
+// aifcVersion is the timestamp of the AIFF-C version 1 spec,
+// stored in the FVER chunk.
+const aifcVersion = 0xA2805140
+
+// AIFFWriter writes PCM audio to an uncompressed AIFF-C file. The
+// header is written up front, so the size of the audio must be known,
+// but w doesn't need to be seekable.
+//
+// Data is expected in host byte order, as returned by [*AudioCD.Read]
+// when ByteOrder isn't set, and is converted to big-endian.
+type AIFFWriter struct {
+	w       io.Writer
+	size    int64 // the number of bytes of audio declared in the header
+	written int64
+	half    []byte // the first byte of a sample split across writes
+	buf     []byte
+}
+
+// AIFFChunk is an extra chunk to store in an AIFF file, e.g. an
+// "ID3 " chunk with tags.
+type AIFFChunk struct {
+	ID   string // the four character chunk id
+	Data []byte
+}
+
+// NewAIFFWriter writes an AIFF-C header for size bytes of
+// CD audio to w, followed by any extra chunks, and returns
+// a writer for the audio.
+func NewAIFFWriter(w io.Writer, size int64, chunks ...AIFFChunk) (*AIFFWriter, error) {
+	if size%(Channels*BytesPerSample) != 0 {
+		return nil, errors.New("audiocd: AIFF size must be a whole number of samples")
+	}
+	for _, c := range chunks {
+		if len(c.ID) != 4 {
+			return nil, fmt.Errorf("audiocd: invalid AIFF chunk id %q", c.ID)
+		}
+	}
+	_, err := w.Write(aiffHeader(size, chunks))
+	if err != nil {
+		return nil, err
+	}
+	return &AIFFWriter{w: w, size: size}, nil
+}
+
+func aiffHeader(size int64, chunks []AIFFChunk) []byte {
+	compression := []byte("\x0enot compressed\x00") // padded to an even length
+	commSize := 2 + 4 + 2 + 10 + 4 + len(compression)
+	extra := 0
+	for _, c := range chunks {
+		extra += 8 + len(c.Data) + len(c.Data)%2
+	}
+
+	var b []byte
+	b = append(b, "FORM"...)
+	b = binary.BigEndian.AppendUint32(b, uint32(int64(4+(8+4)+(8+commSize)+extra+(8+8))+size))
+	b = append(b, "AIFC"...)
+
+	b = append(b, "FVER"...)
+	b = binary.BigEndian.AppendUint32(b, 4)
+	b = binary.BigEndian.AppendUint32(b, aifcVersion)
+
+	b = append(b, "COMM"...)
+	b = binary.BigEndian.AppendUint32(b, uint32(commSize))
+	b = binary.BigEndian.AppendUint16(b, Channels)
+	b = binary.BigEndian.AppendUint32(b, uint32(size/(Channels*BytesPerSample)))
+	b = binary.BigEndian.AppendUint16(b, BitsPerSample)
+	b = appendExtended(b, SampleRate)
+	b = append(b, "NONE"...)
+	b = append(b, compression...)
+
+	for _, c := range chunks {
+		b = append(b, c.ID...)
+		b = binary.BigEndian.AppendUint32(b, uint32(len(c.Data)))
+		b = append(b, c.Data...)
+		if len(c.Data)%2 != 0 {
+			b = append(b, 0) // chunks are padded to an even length
+		}
+	}
+
+	b = append(b, "SSND"...)
+	b = binary.BigEndian.AppendUint32(b, uint32(8+size))
+	b = binary.BigEndian.AppendUint32(b, 0) // offset
+	b = binary.BigEndian.AppendUint32(b, 0) // block size
+	return b
+}
+
+// appendExtended appends v as an 80-bit IEEE 754 extended
+// precision float, which AIFF uses for the sample rate.
+func appendExtended(b []byte, v uint32) []byte {
+	if v == 0 {
+		return append(b, make([]byte, 10)...)
+	}
+	shift := bits.LeadingZeros32(v)
+	exp := 16383 + 31 - shift
+	b = binary.BigEndian.AppendUint16(b, uint16(exp))
+	return binary.BigEndian.AppendUint64(b, uint64(v)<<(32+shift))
+}
+
+// Write converts the PCM data in p to big-endian and writes it.
+// It is an error to write more than the size given to [NewAIFFWriter].
+func (aw *AIFFWriter) Write(p []byte) (int, error) {
+	if aw.written+int64(len(p)) > aw.size {
+		return 0, errors.New("audiocd: write past the end of the AIFF data")
+	}
+	aw.buf = append(append(aw.buf[:0], aw.half...), p...)
+	whole := len(aw.buf) &^ 1
+	if nativeLittleEndian {
+		swapBytes(aw.buf[:whole])
+	}
+	n, err := aw.w.Write(aw.buf[:whole])
+	// count the bytes of p which were written
+	n -= len(aw.half)
+	if n < 0 {
+		n = 0
+	}
+	if err != nil {
+		aw.half = nil
+		aw.written += int64(n)
+		return n, err
+	}
+	aw.half = append(aw.half[:0], aw.buf[whole:]...)
+	aw.written += int64(len(p))
+	return len(p), nil
+}
+
+// Close checks that all of the audio declared in the header was
+// written. It doesn't close the underlying writer.
+func (aw *AIFFWriter) Close() error {
+	if aw.written != aw.size {
+		return io.ErrShortWrite
+	}
+	return nil
+}
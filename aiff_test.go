@@ -0,0 +1,74 @@
+package audiocd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendExtended(t *testing.T) {
+	assert.Equal(t, []byte{0x40, 0x0E, 0xAC, 0x44, 0, 0, 0, 0, 0, 0}, appendExtended(nil, 44100))
+	assert.Equal(t, []byte{0x3F, 0xFF, 0x80, 0, 0, 0, 0, 0, 0, 0}, appendExtended(nil, 1))
+}
+
+func TestAIFFWriter(t *testing.T) {
+	data := make([]byte, 8)
+	for i, v := range []int16{1, -2, 300, -400} {
+		binary.NativeEndian.PutUint16(data[i*2:], uint16(v))
+	}
+
+	var buf bytes.Buffer
+	aw, err := NewAIFFWriter(&buf, int64(len(data)))
+	failIfErr(t, err)
+	// samples split across writes are put back together
+	for i := range data {
+		_, err := aw.Write(data[i : i+1])
+		failIfErr(t, err)
+	}
+	failIfErr(t, aw.Close())
+
+	b := buf.Bytes()
+	assert.Equal(t, "FORM", string(b[:4]))
+	assert.Equal(t, uint32(len(b)-8), binary.BigEndian.Uint32(b[4:]))
+	assert.Equal(t, "AIFC", string(b[8:12]))
+	assert.Equal(t, "COMM", string(b[24:28]))
+	assert.Equal(t, uint32(2), binary.BigEndian.Uint32(b[34:])) // frames
+
+	ssnd := bytes.Index(b, []byte("SSND"))
+	assert.Equal(t, len(b)-len(data)-16, ssnd)
+	samples := b[ssnd+16:]
+	assert.Equal(t, []byte{0, 1, 0xFF, 0xFE, 0x01, 0x2C, 0xFE, 0x70}, samples)
+
+	_, err = aw.Write(data)
+	assert.Error(t, err)
+}
+
+func TestAIFFWriterShort(t *testing.T) {
+	aw, err := NewAIFFWriter(&bytes.Buffer{}, 8)
+	failIfErr(t, err)
+	_, err = aw.Write(make([]byte, 4))
+	failIfErr(t, err)
+	assert.Error(t, aw.Close())
+
+	_, err = NewAIFFWriter(&bytes.Buffer{}, 3)
+	assert.Error(t, err)
+}
+
+func TestAIFFWriterChunks(t *testing.T) {
+	var buf bytes.Buffer
+	aw, err := NewAIFFWriter(&buf, 4, AIFFChunk{ID: "ID3 ", Data: []byte("abc")})
+	failIfErr(t, err)
+	_, err = aw.Write(make([]byte, 4))
+	failIfErr(t, err)
+	failIfErr(t, aw.Close())
+
+	b := buf.Bytes()
+	assert.Equal(t, uint32(len(b)-8), binary.BigEndian.Uint32(b[4:]))
+	id3 := bytes.Index(b, []byte("ID3 "))
+	assert.Equal(t, []byte{0, 0, 0, 3, 'a', 'b', 'c', 0, 'S'}, b[id3+4:id3+13])
+
+	_, err = NewAIFFWriter(&buf, 4, AIFFChunk{ID: "ID3"})
+	assert.Error(t, err)
+}
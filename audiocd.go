@@ -18,10 +18,15 @@ package audiocd
 
 import (
 	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"slices"
+	"time"
 	"unsafe"
 )
 
@@ -49,6 +54,83 @@ const (
 	ParanoiaNeverSkip ParanoiaFlags = (1 << 5)
 )
 
+// AccessMode selects how cdparanoia talks to the drive. Some drives
+// misbehave with one and work with the other.
+type AccessMode int
+
+const (
+	// AccessModeAuto uses the kernel's CD-ROM ioctls where they work,
+	// and SCSI commands otherwise. This is the default.
+	AccessModeAuto AccessMode = 0
+	// AccessModeIOCTL reads through the kernel's CD-ROM ioctls.
+	AccessModeIOCTL AccessMode = 1
+	// AccessModeSCSI sends SCSI read commands (READ CD, or READ 10 on
+	// older drives) to the drive directly.
+	AccessModeSCSI AccessMode = 2
+)
+
+func (m AccessMode) String() string {
+	switch m {
+	case AccessModeAuto:
+		return "Auto"
+	case AccessModeIOCTL:
+		return "IOCTL"
+	case AccessModeSCSI:
+		return "SCSI"
+	default:
+		return fmt.Sprintf("AccessMode(%d)", int(m))
+	}
+}
+
+// ReadMode selects how audio is read from the drive.
+type ReadMode int
+
+const (
+	// ReadModeParanoia verifies and repairs reads with the paranoia
+	// library, as configured by [*AudioCD.SetParanoiaMode]. This is
+	// the default.
+	ReadModeParanoia ReadMode = 0
+	// ReadModeSecure reads each sector several times with READ CD,
+	// defeating the drive's cache in between, and only accepts data
+	// which reads the same enough times. See [*AudioCD.SetSecureMode].
+	// It doesn't use the paranoia library. This requires a drive which
+	// supports MMC commands.
+	ReadModeSecure ReadMode = 1
+	// ReadModeStitch reads sectors straight from the drive with READ CD,
+	// overlapping each read with the previous sector and lines them up by matching samples,
+	// so drives without accurate stream (see [DriveFeatures]) still
+	// return every sample in place. Sectors which can't be lined up
+	// are reported by [*AudioCD.SuspiciousSectors]. This requires a
+	// drive which supports MMC commands.
+	ReadModeStitch ReadMode = 2
+	// ReadModeCdio verifies and repairs reads with libcdio-paranoia,
+	// the libcdio port of the paranoia library, as configured by
+	// [*AudioCD.SetParanoiaMode]. It opens its own handle on the drive
+	// on the first read. It needs the package built with the cdio
+	// build tag and libcdio-paranoia installed, for example:
+	//
+	//	sudo apt install libcdio-paranoia-dev
+	//	go build -tags cdio
+	//
+	// Otherwise reads fail with [ErrCdioUnsupported].
+	ReadModeCdio ReadMode = 3
+)
+
+func (m ReadMode) String() string {
+	switch m {
+	case ReadModeParanoia:
+		return "Paranoia"
+	case ReadModeSecure:
+		return "Secure"
+	case ReadModeStitch:
+		return "Stitch"
+	case ReadModeCdio:
+		return "Cdio"
+	default:
+		return fmt.Sprintf("ReadMode(%d)", int(m))
+	}
+}
+
 // InterfaceType represents the driver implementation used
 // to access the drive.
 type InterfaceType int
@@ -111,10 +193,11 @@ const BytesPerSector = SampleRate * Channels * BytesPerSample / SectorsPerSecond
 // TrackPosition reports the offset information for tracks
 // from the table of contents.
 type TrackPosition struct {
-	Flags         byte // bitflag parameters
-	TrackNum      int  // index of the track, starting at 1
-	StartSector   int  // address of the sector where the data starts
-	LengthSectors int  // total number of sectors the track covers
+	Flags         byte `json:"flags"`         // bitflag parameters
+	TrackNum      int  `json:"trackNum"`      // index of the track, starting at 1
+	StartSector   int  `json:"startSector"`   // address of the sector where the data starts (index 1)
+	LengthSectors int  `json:"lengthSectors"` // total number of sectors the track covers
+	PregapSectors int  `json:"pregapSectors"` // number of sectors of index 0 before StartSector, see [*AudioCD.ScanPregaps]
 }
 
 func (t TrackPosition) IsPreemphasisEnabled() bool {
@@ -131,6 +214,27 @@ func (t TrackPosition) IsAudio() bool {
 	return (t.Flags & 0x04) == 0
 }
 
+// Duration returns the playing time of the track.
+func (t TrackPosition) Duration() time.Duration {
+	return SectorsToDuration(t.LengthSectors)
+}
+
+// StartTime returns the time from the start of the disc
+// (sector 0) to the start of the track.
+func (t TrackPosition) StartTime() time.Duration {
+	return SectorsToDuration(t.StartSector)
+}
+
+// StartMSF returns the start of the track in minutes, seconds and frames.
+func (t TrackPosition) StartMSF() MSF {
+	return SectorsToMSF(t.StartSector)
+}
+
+// LengthMSF returns the length of the track in minutes, seconds and frames.
+func (t TrackPosition) LengthMSF() MSF {
+	return SectorsToMSF(t.LengthSectors)
+}
+
 // ContainsSector reports whether the given sector is within the track bounds
 func (t TrackPosition) ContainsSector(sector int) bool {
 	return sector >= t.StartSector && sector < (t.StartSector+t.LengthSectors)
@@ -146,23 +250,72 @@ func (t TrackPosition) ContainsSector(sector int) bool {
 //
 // Debug logging can be enabled by specifying LogMode. For [LogModeLogger],
 // supply a [log.Logger] instance to Logger.
+//
+// For playback, OnTrackChange can be set to be notified as the read
+// cursor crosses track boundaries.
+//
+// PCM data is returned in host byte order, unless ByteOrder is set,
+// e.g. to [binary.LittleEndian] for WAV files or [binary.BigEndian]
+// for AIFF files.
 type AudioCD struct {
-	Device     string      // the path to the cdrom device, e.g. /dev/cdrom
-	MaxRetries int         // number of repeated reads on failed sectors. Set to -1 to disable retries. If 0, the default of 20 will be used
-	LogMode    LogMode     // direct the library logs
-	Logger     *log.Logger // if LogMode == LogModeLogger, the log.Logger to use
+	Device        string            // the path to the cdrom device, e.g. /dev/cdrom
+	MaxRetries    int               // number of repeated reads on failed sectors. Set to -1 to disable retries. If 0, the default of 20 will be used
+	LogMode       LogMode           // direct the library logs
+	Logger        *log.Logger       // if LogMode == LogModeLogger, the log.Logger to use
+	OnTrackChange func(TrackChange) // if set, called from Read when the data returned enters a new track
+	ByteOrder     binary.ByteOrder  // if set, the byte order of PCM data from Read, WriteTo and ReadSectors
+	Deemphasis    bool              // if set, Read and WriteTo undo the pre-emphasis of tracks which have it
+	AccessMode    AccessMode        // how the drive is accessed, see [AccessMode]. It takes effect on Open
+	Exclusive     bool              // if set, Open fails with ErrDriveBusy while another program has the drive open with Exclusive, or mounted, and keeps others out while open
+	BusyTimeout   time.Duration     // how long Open retries while the drive is busy, e.g. being probed right after a disc is inserted. If 0, it fails with ErrDriveBusy straight away
+	ReadMode      ReadMode          // how audio is read from the drive, see [ReadMode]
+	SampleOffset  int               // the drive's read offset correction in samples, as listed by AccurateRip. Read, WriteTo and ReadSectors return audio shifted by it
+	OverreadEdges bool              // if set, SampleOffset correction reads past the edges of the disc when the drive can, see [*AudioCD.DetectOverread]
+	Substitute    Substitution      // if set, what replaces sectors which remain unreadable after retries, instead of failing the read
+	DataTracks    DataTrackPolicy   // what reads do on reaching a data track, see [DataTrackPolicy]
 
 	buf            bytes.Buffer
 	sbuf           []byte
+	tap            io.Writer    // if set, receives the disc PCM of the data read, see setTap
+	raw            bytes.Buffer // the disc PCM of buf, kept while tap is set
 	bufferedOffset int64
 	trueOffset     int64
+	playTrack      int    // the last track reported to OnTrackChange
+	sector         int    // the next sector read, after offset correction
+	driveSector    int    // the next sector the drive reads
+	carry          []byte // the last drive sector read with a SampleOffset
+	carrySector    int
+	toc            []TrackPosition // the table of contents, once read
+	overread       *Overread       // the edges the drive can read past, once detected
+	stitchTail     []byte          // the end of the last sector read in ReadModeStitch
+	stitchNext     int             // the sector following stitchTail
+	bigEndian      bool            // whether the drive returns big-endian samples
+	endianKnown    bool
+	secure         SecureOptions
+	checkpoint     *checkpoint                     // the record of confirmed sectors, if set
+	cacheDefeat    int                             // sectors to seek away between secure reads
+	suspicious     []int                           // sectors which failed the secure mode quorum
+	substituted    []int                           // unreadable sectors which were replaced
+	lastFrame      [Channels * BytesPerSample]byte // the last sample read, in host byte order
+	doorLocked     bool
+	keepAlive      *keepAlive    // set by SetKeepAlive
+	paranoiaMode   ParanoiaFlags // the last mode set, restored by Reset
+	speed          int           // the last speed set, restored by Reset
+	readTimeout    time.Duration
+	stalled        chan struct{} // closed when a timed out read finally returns
+	deemphasis     deemphasisState
+	processor      func([]int16)
+	levels         *levelMeter
 
+	claim    *os.File       // the exclusive handle on the drive, if Exclusive
+	cdio     *cdioReader    // the libcdio-paranoia handle, once used
 	drive    unsafe.Pointer // *C.cdrom_drive
 	paranoia unsafe.Pointer // *C.cdrom_paranoia
 }
 
 // ensure interface conformation
 var _ io.ReadSeekCloser = (*AudioCD)(nil)
+var _ io.WriterTo = (*AudioCD)(nil)
 
 // Open determines the properties of the drive and detects
 // the audio cd. This method must be called before information
@@ -176,14 +329,41 @@ var _ io.ReadSeekCloser = (*AudioCD)(nil)
 //
 // Open this does not refer to controlling the drive tray.
 func (cd *AudioCD) Open() error {
+	return cd.OpenContext(context.Background())
+}
+
+// OpenContext is like [*AudioCD.Open], but stops waiting for the
+// drive when ctx is done. Detecting the drive and reading the table
+// of contents can take a long time, e.g. while a disc spins up.
+// If ctx is done first, the drive is released once it responds
+// and ctx.Err() is returned.
+func (cd *AudioCD) OpenContext(ctx context.Context) error {
 	if cd.IsOpen() {
 		return nil
 	}
 
-	err := openDrive(cd)
+	// open a copy in the background, so it can be abandoned
+	tmp := &AudioCD{Device: cd.Device, AccessMode: cd.AccessMode, Exclusive: cd.Exclusive, LogMode: cd.LogMode, Logger: cd.Logger}
+	done := make(chan error, 1)
+	go func() {
+		done <- openWhenFree(ctx, tmp, cd.BusyTimeout)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		go func() {
+			if <-done == nil {
+				tmp.Close()
+			}
+		}()
+		return ctx.Err()
+	}
 	if err != nil {
 		return err
 	}
+	cd.drive, cd.paranoia, cd.claim = tmp.drive, tmp.paranoia, tmp.claim
+
 	err = cd.SetSpeed(FullSpeed)
 	if err != nil {
 		return err
@@ -191,9 +371,15 @@ func (cd *AudioCD) Open() error {
 
 	cd.buf.Truncate(0)
 	cd.buf.Grow(BytesPerSector)
+	cd.raw.Reset()
 	cd.bufferedOffset = 0
 	cd.trueOffset = 0
-	err = seekSector(cd, 0)
+	cd.playTrack = 0
+	cd.suspicious = nil
+	cd.substituted = nil
+	cd.overread = nil
+	cd.endianKnown = false
+	err = cd.seek(0)
 	if err != nil {
 		return err
 	}
@@ -202,7 +388,28 @@ func (cd *AudioCD) Open() error {
 	return nil
 }
 
+// busyRetryInterval is how often Open retries a busy drive.
+const busyRetryInterval = 100 * time.Millisecond
+
+// openWhenFree opens the drive, retrying for up to timeout while it's
+// busy.
+func openWhenFree(ctx context.Context, cd *AudioCD, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := openDrive(cd)
+		if !errors.Is(err, ErrDriveBusy) || time.Now().After(deadline) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(busyRetryInterval):
+		}
+	}
+}
+
 // Model returns information about the cd drive's manufacturer and model number.
+// See [*AudioCD.Identify] for them as separate fields.
 func (cd *AudioCD) Model() string {
 	if !cd.IsOpen() {
 		return ""
@@ -217,6 +424,8 @@ func (cd *AudioCD) DriveType() DriveType {
 	return driveType(cd.drive)
 }
 
+// InterfaceType returns the cdparanoia driver the drive is accessed
+// with, e.g. for debugging. Set AccessMode to choose one.
 func (cd *AudioCD) InterfaceType() InterfaceType {
 	if !cd.IsOpen() {
 		return -1
@@ -246,11 +455,26 @@ func (cd *AudioCD) FirstAudioSector() int {
 // The table of contents lists the tracks on the disk
 // and the sector offsets they can be found at.
 // It will have length of [*AudioCD.TrackCount].
+//
+// The table of contents doesn't record pregaps, so PregapSectors
+// is only set for the first track, which starts after its pregap
+// at sector 0. Use [*AudioCD.ScanPregaps] to find the others.
 func (cd *AudioCD) TOC() []TrackPosition {
-	if !cd.IsOpen() {
-		return nil
+	return slices.Clone(cd.tracks())
+}
+
+// tracks returns the table of contents without copying it. It's read
+// from the drive once after opening, for use on every read.
+func (cd *AudioCD) tracks() []TrackPosition {
+	if cd.toc != nil || !cd.IsOpen() {
+		return cd.toc
 	}
-	return toc(cd.drive, cd.TrackCount())
+	toc := toc(cd.drive, cd.TrackCount())
+	if len(toc) > 0 && toc[0].IsAudio() {
+		toc[0].PregapSectors = toc[0].StartSector
+	}
+	cd.toc = toc
+	return toc
 }
 
 // LengthSectors returns the total number of sectors on the disk
@@ -262,6 +486,14 @@ func (cd *AudioCD) LengthSectors() int {
 	return lengthSectors(cd.drive)
 }
 
+// LeadOut returns the address of the first sector of the lead-out,
+// the area after the last track. This is the same as
+// [*AudioCD.LengthSectors]. On multi-session discs it's the lead-out
+// of the last session; see [*AudioCD.Sessions] for the others.
+func (cd *AudioCD) LeadOut() int {
+	return cd.LengthSectors()
+}
+
 // TrackAtSector returns the number of the track that
 // contains the given sector, if any. Track numbers
 // start at 1.
@@ -273,7 +505,10 @@ func (cd *AudioCD) TrackAtSector(sector int) int {
 		return -1
 	}
 
-	toc := cd.TOC()
+	return trackAtSector(cd.tracks(), sector)
+}
+
+func trackAtSector(toc []TrackPosition, sector int) int {
 	for _, t := range toc {
 		if t.ContainsSector(sector) {
 			return t.TrackNum
@@ -299,8 +534,12 @@ func (cd *AudioCD) IsOpen() bool {
 // disables all checks. Individual checks can be enabled, e.g.
 //
 //	cd.SetParanoiaMode(audiocd.ParanoiaRepair|audiocd.ParanoiaNeverSkip)
+//
+// The mode applies to both [ReadModeParanoia] and [ReadModeCdio].
 func (cd *AudioCD) SetParanoiaMode(flags ParanoiaFlags) {
+	cd.waitStalled(context.Background())
 	setParanoia(cd, flags)
+	cd.paranoiaMode = flags
 }
 
 // ForceSearchOverlap sets the minimum number of sectors to search
@@ -313,6 +552,10 @@ func (cd *AudioCD) ForceSearchOverlap(sectors int) error {
 		return fmt.Errorf("audiocd: search overlap sectors must be 0 <= n <= 75")
 	}
 
+	err := cd.waitStalled(context.Background())
+	if err != nil {
+		return err
+	}
 	overlapSet(cd, sectors)
 	return nil
 }
@@ -324,7 +567,18 @@ func (cd *AudioCD) SetSpeed(x int) error {
 	if !cd.IsOpen() {
 		return os.ErrClosed
 	}
-	return setSpeed(cd, x)
+	err := cd.waitStalled(context.Background())
+	if err != nil {
+		return err
+	}
+	cd.keepAlive.lock()
+	err = setSpeed(cd, x)
+	cd.keepAlive.unlock()
+	if err != nil {
+		return err
+	}
+	cd.speed = x
+	return nil
 }
 
 // Seek provides access to the cursor position for reading audio data.
@@ -352,28 +606,38 @@ func (cd *AudioCD) Seek(offset int64, whence int) (int64, error) {
 
 	if newoffset > cd.trueOffset && newoffset < cd.bufferedOffset {
 		// can use data already in buffer
-		_ = cd.buf.Next(int(newoffset - cd.trueOffset)) // empty the buffer up to current point
+		cd.discard(int(newoffset - cd.trueOffset)) // empty the buffer up to current point
 		cd.trueOffset = newoffset
 		return cd.trueOffset, nil
 	}
 
 	// otherwise we're going to need to wipe buffer and seek
+	return cd.seekUnbuffered(newoffset)
+}
+
+// seekUnbuffered wipes the buffer and seeks the drive to newoffset.
+func (cd *AudioCD) seekUnbuffered(newoffset int64) (int64, error) {
 	cd.buf.Truncate(0) // wipe buffered data
+	cd.raw.Reset()
 	cd.trueOffset = cd.bufferedOffset
 	secoffset := newoffset - (newoffset % BytesPerSector)
 
-	err := seekSector(cd, int(secoffset/BytesPerSector))
+	err := cd.waitStalled(context.Background())
+	if err != nil {
+		return cd.trueOffset, err
+	}
+	err = cd.seek(int(secoffset / BytesPerSector))
 	if err != nil {
 		cd.trueOffset = cd.bufferedOffset
 		return cd.trueOffset, err
 	}
-	err = cd.bufferSectors(1)
+	err = cd.bufferSectors(context.Background(), 1)
 	cd.trueOffset = cd.bufferedOffset
 	if err != nil {
 		return cd.trueOffset, err
 	}
 	// seek buffer ahead to sub-sector offset
-	_ = cd.buf.Next(int(newoffset - secoffset))
+	cd.discard(int(newoffset - secoffset))
 	cd.trueOffset = newoffset
 	return cd.trueOffset, nil
 }
@@ -384,14 +648,61 @@ func (cd *AudioCD) SeekToSector(sector int) (int64, error) {
 	return cd.Seek(int64(sector)*BytesPerSector, io.SeekStart)
 }
 
+// SeekToTime seeks the cd to the given time from the start of the
+// disc (sector 0), rounded to the nearest stereo sample.
+func (cd *AudioCD) SeekToTime(d time.Duration) (int64, error) {
+	return cd.Seek(timeToOffset(d), io.SeekStart)
+}
+
+// Position is the read position of an [AudioCD], in various units.
+type Position struct {
+	Offset int64         // the byte offset from the start of the disc
+	Sector int           // the sector containing the position
+	Sample int64         // the stereo sample (frame) from the start of the disc
+	Time   time.Duration // the time from the start of the disc
+	MSF    MSF           // the sector in minutes, seconds and frames
+	Track  int           // the track containing the position, or 0 if none
+}
+
+// Position returns the current read position.
+func (cd *AudioCD) Position() Position {
+	return positionAt(cd.tracks(), cd.trueOffset)
+}
+
+func positionAt(toc []TrackPosition, offset int64) Position {
+	sample := offset / (Channels * BytesPerSample)
+	sector := int(offset / BytesPerSector)
+	return Position{
+		Offset: offset,
+		Sector: sector,
+		Sample: sample,
+		Time:   time.Duration(sample) * time.Second / SampleRate,
+		MSF:    SectorsToMSF(sector),
+		Track:  trackAtSector(toc, sector),
+	}
+}
+
+// timeToOffset converts a time to the byte offset of the nearest sample.
+func timeToOffset(d time.Duration) int64 {
+	samples := (int64(d)*SampleRate + int64(time.Second)/2) / int64(time.Second)
+	return samples * Channels * BytesPerSample
+}
+
 // Read reads PCM audio data from the disk.
 //
 // Read only supports reading complete sectors, and will error
 // for partial reads.
 //
 // PCM data is signed 16-bit samples. Data will be in host byte order,
-// regardless of drive endianness.
+// regardless of drive endianness, unless ByteOrder is set.
 func (cd *AudioCD) Read(p []byte) (n int, err error) {
+	return cd.ReadContext(context.Background(), p)
+}
+
+// ReadContext is like [*AudioCD.Read], but stops reading when ctx is
+// done. Sectors are read one at a time, so it returns once the sector
+// being read is complete, with any data read so far and ctx.Err().
+func (cd *AudioCD) ReadContext(ctx context.Context, p []byte) (n int, err error) {
 	if len(p) == 0 {
 		return 0, nil
 	}
@@ -402,24 +713,155 @@ func (cd *AudioCD) Read(p []byte) (n int, err error) {
 			n = cd.buf.Len()
 		}
 		copy(p[:n], cd.buf.Next(n))
+		cd.notifyTrackChanges(int64(n))
+		cd.tapRead(n)
 		cd.trueOffset += int64(n)
 
 		// if more was requested, continue reading
-		nn, err := cd.Read(p[n:])
+		nn, err := cd.ReadContext(ctx, p[n:])
 		return n + nn, err
 	}
 
 	// otherwise load data into the buffer
 	nsectors := (len(p) / BytesPerSector) + 1
-	err = cd.bufferSectors(int(nsectors))
-	if err != nil {
+	err = cd.bufferSectors(ctx, int(nsectors))
+	if err != nil && cd.buf.Len() == 0 {
 		return 0, err
 	}
 	// recurse to load said data from buffer
-	return cd.Read(p)
+	n, rerr := cd.ReadContext(ctx, p)
+	if err == nil {
+		err = rerr
+	}
+	return n, err
+}
+
+// WriteTo streams PCM audio data from the read position to the end
+// of the disc into w. It reads many sectors at a time and writes them
+// to w directly, so it's more efficient than copying with Read. It is
+// used by [io.Copy].
+func (cd *AudioCD) WriteTo(w io.Writer) (int64, error) {
+	if !cd.IsOpen() {
+		return 0, os.ErrClosed
+	}
+	end := int64(cd.LengthSectors()) * BytesPerSector
+	return cd.writeTo(w, end-cd.trueOffset)
+}
+
+// writeToSectors is the number of sectors read at once by WriteTo.
+const writeToSectors = SectorsPerSecond
+
+// writeTo writes up to limit bytes from the read position into w.
+func (cd *AudioCD) writeTo(w io.Writer, limit int64) (int64, error) {
+	var written int64
+	write := func(p []byte) (int, error) {
+		cd.notifyTrackChanges(int64(len(p)))
+		n, err := w.Write(p)
+		cd.tapRead(n)
+		cd.trueOffset += int64(n)
+		written += int64(n)
+		return n, err
+	}
+
+	// first write out the data that's already buffered
+	if cd.buf.Len() > 0 {
+		n := min(int64(cd.buf.Len()), limit)
+		nw, err := write(cd.buf.Bytes()[:n])
+		cd.buf.Next(nw)
+		if err != nil {
+			return written, err
+		}
+	}
+
+	chunk := make([]byte, writeToSectors*BytesPerSector)
+	for written < limit {
+		nsectors := min((limit-written+BytesPerSector-1)/BytesPerSector, writeToSectors)
+		n, err := cd.readSectors(context.Background(), chunk[:nsectors*BytesPerSector])
+		cd.processSectors(chunk[:n])
+		cd.bufferedOffset += n
+		if cd.skipsDataTrack(err) {
+			if n == 0 {
+				skipped, err := cd.skipDataTrack()
+				limit -= skipped
+				if err == io.EOF {
+					return written, nil
+				}
+				if err != nil {
+					return written, err
+				}
+				continue
+			}
+			err = nil // skip on the next pass
+		}
+		if n == 0 && err == nil {
+			err = io.ErrUnexpectedEOF
+		}
+		nw, werr := write(chunk[:min(n, limit-written)])
+		// keep the data which wasn't written for the next Read
+		cd.buf.Write(chunk[nw:n])
+		if werr != nil {
+			return written, werr
+		}
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// notifyTrackChanges calls OnTrackChange for the track boundaries
+// in the next n bytes from the read position.
+func (cd *AudioCD) notifyTrackChanges(n int64) {
+	if cd.OnTrackChange == nil {
+		return
+	}
+	for _, tc := range trackChanges(cd.tracks(), cd.playTrack, cd.trueOffset, cd.trueOffset+n) {
+		cd.playTrack = tc.Track
+		cd.OnTrackChange(tc)
+	}
+}
+
+// ReadSectors reads whole sectors starting at the given sector into p,
+// with the same error correction as [*AudioCD.Read]. It doesn't move
+// the read cursor or disturb buffered data, so it can be used for
+// precise re-reads, e.g. when comparing several reads of a range.
+// p must be a multiple of [BytesPerSector] in length.
+//
+// PCM data is in host byte order, unless ByteOrder is set.
+func (cd *AudioCD) ReadSectors(sector int, p []byte) (int, error) {
+	if !cd.IsOpen() {
+		return 0, os.ErrClosed
+	}
+	if len(p)%BytesPerSector != 0 {
+		return 0, fmt.Errorf("audiocd: must read complete sectors")
+	}
+	if sector < 0 || sector+len(p)/BytesPerSector > cd.LengthSectors() {
+		return 0, fmt.Errorf("audiocd: sectors %d-%d are outside of the disc", sector, sector+len(p)/BytesPerSector-1)
+	}
+
+	err := cd.waitStalled(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	err = cd.seek(sector)
+	if err != nil {
+		return 0, err
+	}
+	n, err := cd.readSectors(context.Background(), p)
+	cd.convertByteOrder(p[:n])
+	if cd.stalled != nil {
+		// the cursor is put back once the read returns
+		return int(n), err
+	}
+	// put the cursor back where Read expects it
+	serr := cd.seek(int(cd.bufferedOffset / BytesPerSector))
+	if err == nil {
+		err = serr
+	}
+	return int(n), err
 }
 
-func (cd *AudioCD) readSectors(p []byte) (int64, error) {
+func (cd *AudioCD) readSectors(ctx context.Context, p []byte) (int64, error) {
 	if !cd.IsOpen() {
 		return 0, os.ErrClosed
 	}
@@ -433,35 +875,332 @@ func (cd *AudioCD) readSectors(p []byte) (int64, error) {
 
 	if int(len(p)) > BytesPerSector {
 		// read one sector
-		n, err := cd.readSectors(p[:BytesPerSector])
+		n, err := cd.readSectors(ctx, p[:BytesPerSector])
 		if err != nil {
 			return n, err
 		}
 		// read remaining sectors
-		nn, err := cd.readSectors(p[BytesPerSector:])
+		nn, err := cd.readSectors(ctx, p[BytesPerSector:])
 		return n + nn, err
 	}
 
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if t, ok := cd.dataTrackAt(cd.sector); ok {
+		if cd.DataTracks != DataTrackZeroFill {
+			return 0, &DataTrackError{TrackNum: t.TrackNum, Sector: cd.sector}
+		}
+		clear(p)
+		cd.sector++
+		return BytesPerSector, nil
+	}
+	if err := cd.waitStalled(ctx); err != nil {
+		return 0, err
+	}
+	var err error
+	if cd.SampleOffset == 0 {
+		if cd.driveSector != cd.sector {
+			// the offset was just cleared
+			err = cd.seekDrive(cd.sector)
+			if err != nil {
+				return 0, err
+			}
+		}
+		err = cd.readDrive(ctx, p)
+	} else {
+		err = cd.readShifted(ctx, p)
+	}
+	if err != nil {
+		return 0, err
+	}
+	cd.keepAlive.read(cd.driveSector)
+	cd.sector++
+	return BytesPerSector, nil
+}
+
+// readDrive reads the sector at the drive's cursor into p.
+func (cd *AudioCD) readDrive(ctx context.Context, p []byte) error {
 	retries := cd.MaxRetries
 	if retries < 0 {
 		retries = 0 // disable
 	} else if retries == 0 {
 		retries = 20 // default value
 	}
-	readLimited(cd, p, retries)
-	return BytesPerSector, nil
+	var err error
+	if cd.readTimeout <= 0 {
+		err = cd.readSector(p, retries)
+	} else {
+		err = cd.readTimed(ctx, p, retries)
+	}
+	if err != nil && cd.Substitute != SubstituteNone && cd.stalled == nil {
+		err = cd.substitute(p, err)
+	}
+	if err != nil {
+		return err
+	}
+	copy(cd.lastFrame[:], p[BytesPerSector-len(cd.lastFrame):])
+	return nil
+}
+
+// processSectors prepares sectors read at the buffered offset
+// to be returned from Read or WriteTo.
+func (cd *AudioCD) processSectors(p []byte) {
+	if cd.tap != nil {
+		cd.raw.Write(p)
+	}
+	sector := int(cd.bufferedOffset / BytesPerSector)
+	if cd.Deemphasis {
+		cd.deemphasize(sector, p)
+	}
+	if len(p) > 0 && (cd.processor != nil || cd.levels != nil) {
+		samples := unsafe.Slice((*int16)(unsafe.Pointer(&p[0])), len(p)/BytesPerSample)
+		if cd.processor != nil {
+			cd.processor(samples)
+		}
+		if cd.levels != nil {
+			cd.levels.measure(cd.tracks(), sector, samples)
+		}
+	}
+	cd.convertByteOrder(p)
+}
+
+// setTap sets w to receive the disc PCM of the data returned from then
+// on by Read and WriteTo, in little-endian order as on the disc, before
+// Deemphasis, the processor and ByteOrder are applied. Data already
+// buffered is read again so none is missed. A nil w stops the tap.
+func (cd *AudioCD) setTap(w io.Writer) error {
+	cd.tap = w
+	cd.raw.Reset()
+	if w == nil || cd.buf.Len() == 0 {
+		return nil
+	}
+	_, err := cd.seekUnbuffered(cd.trueOffset)
+	return err
+}
+
+// tapRead passes the disc PCM of n bytes returned by a read to the tap.
+func (cd *AudioCD) tapRead(n int) {
+	if cd.tap == nil {
+		return
+	}
+	p := cd.raw.Next(n)
+	if !nativeLittleEndian {
+		swapBytes(p)
+	}
+	cd.tap.Write(p)
+}
+
+// discard drops n bytes from the buffer, as if they'd been read.
+func (cd *AudioCD) discard(n int) {
+	cd.buf.Next(n)
+	cd.raw.Next(n)
+}
+
+// swapsBytes reports whether PCM data is converted from host byte
+// order to ByteOrder.
+func (cd *AudioCD) swapsBytes() bool {
+	if cd.ByteOrder == nil {
+		return false
+	}
+	little := cd.ByteOrder.Uint16([]byte{1, 0}) == 1
+	return little != nativeLittleEndian
+}
+
+// convertByteOrder converts samples in host byte order
+// to ByteOrder in place.
+func (cd *AudioCD) convertByteOrder(p []byte) {
+	if cd.swapsBytes() {
+		swapBytes(p)
+	}
+}
+
+// SetReadTimeout limits how long a single sector read may take,
+// including retries. Damaged discs can stall the drive for minutes.
+// If a read takes longer, it fails with [ErrReadTimeout] and the read
+// cursor stays on the failed sector, so the caller can retry it or
+// seek past it. The drive can't be interrupted, so the next operation
+// waits for the stalled read to finish before continuing.
+// A value of 0 disables the timeout, which is the default.
+func (cd *AudioCD) SetReadTimeout(d time.Duration) {
+	cd.readTimeout = d
+}
+
+// readTimed reads a sector in the background, giving up on it once
+// the read timeout passes or ctx is done.
+func (cd *AudioCD) readTimed(ctx context.Context, p []byte, retries int) error {
+	// the read writes to its own buffer, so an abandoned read
+	// can't modify p after returning
+	buf := make([]byte, BytesPerSector)
+	if cd.ReadMode == ReadModeCdio {
+		// open it here, so the copy doesn't open its own
+		err := cd.openCdio()
+		if err != nil {
+			return err
+		}
+	}
+	// and to its own copy of the reader, so an abandoned read
+	// can't touch cd, which may be closed or reset meanwhile
+	r := cd.detached()
+	done := make(chan struct{})
+	var readErr error
+	go func() {
+		defer close(done)
+		readErr = r.readSector(buf, retries)
+	}()
+
+	timer := time.NewTimer(cd.readTimeout)
+	defer timer.Stop()
+	var err error
+	select {
+	case <-done:
+		copy(p, buf)
+		cd.adopt(r)
+		return readErr
+	case <-timer.C:
+		err = ErrReadTimeout
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	cd.stalled = done
+	return err
+}
+
+// detached returns a copy of the reader state used by readSector.
+func (cd *AudioCD) detached() *AudioCD {
+	return &AudioCD{
+		LogMode:      cd.LogMode,
+		Logger:       cd.Logger,
+		ReadMode:     cd.ReadMode,
+		paranoiaMode: cd.paranoiaMode,
+		driveSector:  cd.driveSector,
+		stitchTail:   slices.Clone(cd.stitchTail),
+		stitchNext:   cd.stitchNext,
+		bigEndian:    cd.bigEndian,
+		endianKnown:  cd.endianKnown,
+		secure:       cd.secure,
+		checkpoint:   cd.checkpoint,
+		cacheDefeat:  cd.cacheDefeat,
+		keepAlive:    cd.keepAlive,
+		toc:          cd.toc,
+		drive:        cd.drive,
+		paranoia:     cd.paranoia,
+		cdio:         cd.cdio,
+	}
+}
+
+// adopt takes the reader state from a detached read which completed.
+func (cd *AudioCD) adopt(r *AudioCD) {
+	cd.driveSector = r.driveSector
+	cd.stitchTail = r.stitchTail
+	cd.stitchNext = r.stitchNext
+	cd.bigEndian, cd.endianKnown = r.bigEndian, r.endianKnown
+	cd.suspicious = append(cd.suspicious, r.suspicious...)
+}
+
+// readSector reads the sector at the drive's cursor in the ReadMode
+// and advances the cursor.
+func (cd *AudioCD) readSector(p []byte, retries int) error {
+	switch cd.ReadMode {
+	case ReadModeSecure, ReadModeStitch:
+		var err error
+		if cd.ReadMode == ReadModeSecure {
+			err = cd.readSecure(cd.driveSector, p)
+		} else {
+			err = cd.readStitched(cd.driveSector, p)
+		}
+		if err != nil {
+			return err
+		}
+		cd.driveToNative(p)
+		cd.driveSector++
+		return nil
+	}
+	if cd.ReadMode == ReadModeCdio {
+		err := cd.readCdio(p, retries)
+		if err != nil {
+			return err
+		}
+		cd.driveSector++
+		return nil
+	}
+	// paranoia reports the problems it worked around in the logs,
+	// and returns the best data it could get
+	cd.keepAlive.lock()
+	err := readLimited(cd, p, retries)
+	cd.keepAlive.unlock()
+	if err != nil {
+		// paranoia moved on anyway, so put it back on the failed sector
+		seekSector(cd, cd.driveSector)
+		return err
+	}
+	cd.driveSector++
+	return nil
+}
+
+// seek moves the read cursor to the given sector, and the drive's
+// cursor to where that sector's audio is read from.
+func (cd *AudioCD) seek(sector int) error {
+	err := cd.seekDrive(cd.shiftedSector(sector))
+	if err != nil {
+		return err
+	}
+	cd.sector = sector
+	cd.carry = nil
+	return nil
 }
 
-func (cd *AudioCD) bufferSectors(nsectors int) error {
+// seekDrive moves the drive's cursor to the given sector.
+func (cd *AudioCD) seekDrive(sector int) error {
+	err := cd.waitStalled(context.Background())
+	if err != nil {
+		return err
+	}
+	err = seekSector(cd, sector)
+	if err != nil {
+		return err
+	}
+	cd.driveSector = sector
+	cd.lastFrame = [len(cd.lastFrame)]byte{}
+	return nil
+}
+
+// waitStalled waits for a timed out read to return and puts the
+// cursor back on the sector it was reading.
+func (cd *AudioCD) waitStalled(ctx context.Context) error {
+	if cd.stalled == nil {
+		return nil
+	}
+	select {
+	case <-cd.stalled:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	cd.stalled = nil
+	return cd.seek(int(cd.bufferedOffset / BytesPerSector))
+}
+
+func (cd *AudioCD) bufferSectors(ctx context.Context, nsectors int) error {
 	if cd.sbuf == nil {
 		cd.sbuf = make([]byte, nsectors*BytesPerSector)
 	}
 	if len(cd.sbuf) < nsectors*BytesPerSector {
 		cd.sbuf = make([]byte, nsectors*BytesPerSector)
 	}
-	n, err := cd.readSectors(cd.sbuf)
+	n, err := cd.readSectors(ctx, cd.sbuf)
+	cd.processSectors(cd.sbuf[:n])
 	cd.bufferedOffset += n
 	cd.buf.Write(cd.sbuf[:n])
+	if cd.skipsDataTrack(err) {
+		if n > 0 {
+			// skip once the buffered data has been read
+			return nil
+		}
+		if _, err := cd.skipDataTrack(); err != nil {
+			return err
+		}
+		return cd.bufferSectors(ctx, nsectors)
+	}
 	return err
 }
 
@@ -470,17 +1209,53 @@ func (cd *AudioCD) bufferSectors(nsectors int) error {
 //
 // Close this does not refer to controlling the drive tray.
 func (cd *AudioCD) Close() error {
-	if cd.IsOpen() {
-		closeDrive(cd.drive)
-	}
-	if cd.paranoia != nil {
-		paranoiaFree(cd.paranoia)
+	cd.keepAlive.close()
+	cd.keepAlive = nil
+	if cd.stalled == nil && cd.IsOpen() && cd.doorLocked {
+		cd.LockDoor(false)
 	}
+	cd.doorLocked = false
+	cd.release()
+	cd.buf.Truncate(0)
+	cd.raw.Reset()
+	err := cd.checkpoint.close()
+	cd.checkpoint = nil
+	return err
+}
 
+// release frees the drive handle.
+func (cd *AudioCD) release() {
+	if cd.stalled != nil {
+		// release the drive once the stalled read returns
+		stalled, drive, paranoia, cdio := cd.stalled, cd.drive, cd.paranoia, cd.cdio
+		go func() {
+			<-stalled
+			closeDrive(drive)
+			paranoiaFree(paranoia)
+			if cdio != nil {
+				closeCdio(cdio)
+			}
+		}()
+		cd.stalled = nil
+	} else {
+		if cd.IsOpen() {
+			closeDrive(cd.drive)
+		}
+		if cd.paranoia != nil {
+			paranoiaFree(cd.paranoia)
+		}
+		if cd.cdio != nil {
+			closeCdio(cd.cdio)
+		}
+	}
+	if cd.claim != nil {
+		cd.claim.Close()
+	}
 	cd.paranoia = nil
 	cd.drive = nil
-	cd.buf.Truncate(0)
-	return nil
+	cd.claim = nil
+	cd.cdio = nil
+	cd.toc = nil
 }
 
 // Version returns the libcdparanoia version string.
@@ -0,0 +1,81 @@
+//go:build linux && cdio
+
+package audiocd
+
+// #cgo LDFLAGS: -lcdio_paranoia -lcdio_cdda -lcdio
+// #include <stdlib.h>
+// #include <cdio/paranoia/cdda.h>
+// #include <cdio/paranoia/paranoia.h>
+import "C"
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unsafe"
+)
+
+func openCdio(path string, lm LogMode, mode ParanoiaFlags) (*cdioReader, error) {
+	str := C.CString(path)
+	defer C.free(unsafe.Pointer(str))
+	drive := C.cdio_cddap_identify(str, C.CDDA_MESSAGE_FORGETIT, nil)
+	if drive == nil {
+		return nil, deviceError(path)
+	}
+	switch lm {
+	case LogModeStdErr:
+		C.cdio_cddap_verbose_set(drive, C.CDDA_MESSAGE_PRINTIT, C.CDDA_MESSAGE_PRINTIT)
+	case LogModeLogger:
+		C.cdio_cddap_verbose_set(drive, C.CDDA_MESSAGE_LOGIT, C.CDDA_MESSAGE_LOGIT)
+	}
+	if res := C.cdio_cddap_open(drive); res != 0 {
+		C.cdio_cddap_close(drive)
+		return nil, fmt.Errorf("audiocd: libcdio-paranoia couldn't open %s: %w", path, AudioCDError(-res))
+	}
+	paranoia := C.cdio_paranoia_init(drive)
+	C.cdio_paranoia_modeset(paranoia, C.int(mode))
+	return &cdioReader{drive: unsafe.Pointer(drive), paranoia: unsafe.Pointer(paranoia), mode: mode, next: -1}, nil
+}
+
+func closeCdio(r *cdioReader) {
+	C.cdio_paranoia_free((*C.cdrom_paranoia_t)(r.paranoia))
+	C.cdio_cddap_close((*C.cdrom_drive_t)(r.drive))
+}
+
+func cdioSetMode(r *cdioReader, flags ParanoiaFlags) {
+	C.cdio_paranoia_modeset((*C.cdrom_paranoia_t)(r.paranoia), C.int(flags))
+}
+
+func cdioSeek(r *cdioReader, sector int) error {
+	res := int64(C.cdio_paranoia_seek((*C.cdrom_paranoia_t)(r.paranoia), C.int32_t(sector), C.int(io.SeekStart)))
+	if res < 0 {
+		return AudioCDError(-res)
+	}
+	return nil
+}
+
+func cdioRead(cd *AudioCD, r *cdioReader, p []byte, retries int) error {
+	buf := unsafe.Pointer(C.cdio_paranoia_read_limited((*C.cdrom_paranoia_t)(r.paranoia), nil, C.int(retries)))
+	// as with cdparanoia, the errors logged are mostly recovered from
+	cdioFlushLogs(cd, r)
+	if buf == nil {
+		return fmt.Errorf("audiocd: libcdio-paranoia couldn't read sector")
+	}
+	copy(p, unsafe.Slice((*byte)(buf), BytesPerSector))
+	return nil
+}
+
+func cdioFlushLogs(cd *AudioCD, r *cdioReader) {
+	drive := (*C.cdrom_drive_t)(r.drive)
+	for _, str := range []*C.char{C.cdio_cddap_errors(drive), C.cdio_cddap_messages(drive)} {
+		if str == nil {
+			continue
+		}
+		if cd.LogMode == LogModeLogger && cd.Logger != nil {
+			for line := range strings.Lines(C.GoString(str)) {
+				cd.Logger.Print(line)
+			}
+		}
+		C.cdio_cddap_free_messages(str)
+	}
+}
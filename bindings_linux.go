@@ -5,9 +5,15 @@ package audiocd
 // TODO: should we link statically instead??
 
 // #cgo LDFLAGS: -lcdda_interface -lcdda_paranoia
+// #include <errno.h>
+// #include <limits.h>
 // #include <stdint.h>
 // #include <stdlib.h>
+// #include <string.h>
+// #include <sys/ioctl.h>
+// #include <linux/cdrom.h>
 // #include <linux/major.h>
+// #include <scsi/sg.h>
 // #include <cdda_interface.h>
 // #include <cdda_paranoia.h>
 //
@@ -17,13 +23,56 @@ package audiocd
 // int bridge_set_speed(set_speed_fn f, struct cdrom_drive *d, int speed) {
 //   return f(d, speed);
 // }
+//
+// /* Issue a SCSI command with the SG_IO ioctl. Returns 0 on success,
+//    the length of the sense data on a check condition, or -errno. */
+// int bridge_sg_io(int fd, unsigned char *cdb, int cdb_len, int dir,
+//                  void *buf, unsigned int buf_len,
+//                  unsigned char *sense, int sense_len, unsigned int timeout) {
+//   sg_io_hdr_t hdr;
+//   memset(&hdr, 0, sizeof(hdr));
+//   hdr.interface_id = 'S';
+//   hdr.cmdp = cdb;
+//   hdr.cmd_len = cdb_len;
+//   hdr.dxfer_direction = dir;
+//   hdr.dxferp = buf;
+//   hdr.dxfer_len = buf_len;
+//   hdr.sbp = sense;
+//   hdr.mx_sb_len = sense_len;
+//   hdr.timeout = timeout;
+//   if (ioctl(fd, SG_IO, &hdr) < 0) {
+//     return -errno;
+//   }
+//   if ((hdr.info & SG_INFO_OK_MASK) == SG_INFO_OK) {
+//     return 0;
+//   }
+//   if (hdr.sb_len_wr > 0) {
+//     return hdr.sb_len_wr;
+//   }
+//   return -EIO;
+// }
+//
+// /* ioctl is variadic, which cgo can't call directly.
+//    Returns the result, or -errno on failure. */
+// int bridge_ioctl(int fd, unsigned long req, unsigned long arg) {
+//   int res = ioctl(fd, req, arg);
+//   if (res < 0) {
+//     return -errno;
+//   }
+//   return res;
+// }
 import "C"
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"os"
+	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 	"unsafe"
 )
 
@@ -78,17 +127,49 @@ func openDrive(cd *AudioCD) error {
 	var p *C.char
 	defer logFlush(unsafe.Pointer(p))
 
+	device := cd.Device
+	if device == "" && (cd.AccessMode != AccessModeAuto || cd.Exclusive) {
+		// cdda_find_a_cdrom can't be told how to access the drive
+		var err error
+		device, err = defaultDevice()
+		if err != nil {
+			return err
+		}
+	}
+	if cd.Exclusive {
+		claim, err := claimDevice(device)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if cd.drive == nil {
+				claim.Close()
+			}
+		}()
+		cd.claim = claim
+	}
+
 	var drive *C.cdrom_drive
-	if cd.Device == "" {
+	if device == "" {
 		drive = C.cdda_find_a_cdrom(logLevel, &p)
 	} else {
-		str := C.CString(cd.Device)
+		str := C.CString(device)
 		defer C.free(unsafe.Pointer(str))
-		drive = C.cdda_identify(str, logLevel, &p)
+		switch cd.AccessMode {
+		case AccessModeIOCTL:
+			drive = C.cdda_identify_cooked(str, logLevel, &p)
+		case AccessModeSCSI:
+			drive = C.cdda_identify_scsi(str, nil, logLevel, &p)
+		default:
+			drive = C.cdda_identify(str, logLevel, &p)
+		}
 	}
 
 	if drive == nil {
-		return ErrNoDrive
+		if device == "" {
+			return ErrNoDrive
+		}
+		return deviceError(device)
 	}
 
 	if err, ok := parseError(C.cdda_open(drive)); !ok {
@@ -99,6 +180,142 @@ func openDrive(cd *AudioCD) error {
 	return nil
 }
 
+// defaultDevice returns the path of the drive cdparanoia picks when
+// no device is given.
+func defaultDevice() (string, error) {
+	drive := C.cdda_find_a_cdrom(C.CDDA_MESSAGE_FORGETIT, nil)
+	if drive == nil {
+		return "", ErrNoDrive
+	}
+	defer C.cdda_close(drive)
+	return C.GoString(drive.cdda_device_name), nil
+}
+
+// identifyDrive finds the drive at path without opening the disc,
+// so it works on empty drives. Release it with closeDrive.
+func identifyDrive(path string) (unsafe.Pointer, error) {
+	str := C.CString(path)
+	defer C.free(unsafe.Pointer(str))
+	drive := C.cdda_identify(str, C.CDDA_MESSAGE_FORGETIT, nil)
+	if drive == nil {
+		return nil, deviceError(path)
+	}
+	return unsafe.Pointer(drive), nil
+}
+
+// driveDevices lists the device files which may be optical drives,
+// with links to the same device removed.
+func driveDevices() []string {
+	var devices []string
+	seen := make(map[string]bool)
+	for _, pattern := range []string{"/dev/sr[0-9]*", "/dev/scd[0-9]*", "/dev/cdrom*"} {
+		matches, _ := filepath.Glob(pattern)
+		for _, path := range matches {
+			real, err := filepath.EvalSymlinks(path)
+			if err != nil || seen[real] {
+				continue
+			}
+			seen[real] = true
+			devices = append(devices, path)
+		}
+	}
+	return devices
+}
+
+// driveStatus polls the drive at path, reporting whether the disc
+// changed since the last poll.
+func driveStatus(path string) (driveState, bool, error) {
+	f, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return driveUnknown, false, err
+	}
+	defer f.Close()
+	fd := C.int(f.Fd())
+
+	res := C.bridge_ioctl(fd, C.CDROM_DRIVE_STATUS, C.CDSL_CURRENT)
+	if res < 0 {
+		return driveUnknown, false, fmt.Errorf("audiocd: drive status: %w", syscall.Errno(-res))
+	}
+	var state driveState
+	switch res {
+	case C.CDS_TRAY_OPEN:
+		state = driveTrayOpen
+	case C.CDS_NO_DISC:
+		state = driveEmpty
+	case C.CDS_DRIVE_NOT_READY:
+		state = driveNotReady
+	case C.CDS_DISC_OK:
+		state = driveHasDisc
+	}
+	changed := C.bridge_ioctl(fd, C.CDROM_MEDIA_CHANGED, C.CDSL_CURRENT) > 0
+	return state, changed, nil
+}
+
+// resetDevice resets the drive at path. This needs CAP_SYS_ADMIN.
+func resetDevice(path string) error {
+	f, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	res := C.bridge_ioctl(C.int(f.Fd()), C.CDROMRESET, 0)
+	if res < 0 {
+		return fmt.Errorf("audiocd: reset drive: %w", syscall.Errno(-res))
+	}
+	return nil
+}
+
+func devicePath(drive unsafe.Pointer) string {
+	return C.GoString((*C.cdrom_drive)(drive).cdda_device_name)
+}
+
+// claimDevice opens the device exclusively, so other exclusive opens,
+// and mounts, fail while it's open.
+func claimDevice(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NONBLOCK|syscall.O_EXCL, 0)
+	if errors.Is(err, syscall.EBUSY) {
+		return nil, fmt.Errorf("%w: %w", ErrDriveBusy, err)
+	}
+	if err != nil {
+		return nil, deviceError(path)
+	}
+	return f, nil
+}
+
+// deviceError opens the device to find out why it couldn't be used,
+// since cdparanoia doesn't say.
+func deviceError(path string) error {
+	f, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	switch {
+	case err == nil:
+		// it's not a drive cdparanoia can use
+		f.Close()
+		return ErrNoDrive
+	case errors.Is(err, syscall.EBUSY):
+		return fmt.Errorf("%w: %w", ErrDriveBusy, err)
+	case errors.Is(err, syscall.ENOMEDIUM):
+		return fmt.Errorf("%w: %w", ErrNoDisc, err)
+	case errors.Is(err, ErrNoDrive), errors.Is(err, ErrPermission):
+		return err
+	default:
+		return fmt.Errorf("%w: %w", ErrNoDrive, err)
+	}
+}
+
+// driveBigEndian returns cdparanoia's idea of the drive's byte order:
+// 1 for big-endian, 0 for little-endian or -1 if it hasn't read yet.
+func driveBigEndian(cd *AudioCD) int {
+	return int((*C.cdrom_drive)(cd.drive).bigendianp)
+}
+
+func setDriveBigEndian(cd *AudioCD, big bool) {
+	v := 0
+	if big {
+		v = 1
+	}
+	(*C.cdrom_drive)(cd.drive).bigendianp = C.int(v)
+}
+
 func model(drive unsafe.Pointer) string {
 	return C.GoString((*C.cdrom_drive)(drive).drive_model)
 }
@@ -184,13 +401,11 @@ func seekSector(cd *AudioCD, sector int) error {
 func readLimited(cd *AudioCD, p []byte, retries int) error {
 	// TODO: expose callback? may not be possible
 	buf := unsafe.Pointer(C.paranoia_read_limited(cd.paranoia, nil, C.int(retries)))
-	// run logs and check for errors
-	err := flushLogs(cd)
-	if err != nil {
-		return err
-	}
+	// the errors logged are mostly ones paranoia recovered from,
+	// so they're only logged
+	flushLogs(cd)
 	if buf == nil {
-		return fmt.Errorf("audiocd: unknown error")
+		return fmt.Errorf("audiocd: paranoia couldn't read sector")
 	}
 
 	res := C.GoBytes(buf, C.int(BytesPerSector))
@@ -199,6 +414,73 @@ func readLimited(cd *AudioCD, p []byte, retries int) error {
 	return nil
 }
 
+func driveFD(d unsafe.Pointer) C.int {
+	drive := (*C.cdrom_drive)(d)
+	if drive.ioctl_fd >= 0 {
+		return drive.ioctl_fd
+	}
+	return drive.cdda_fd
+}
+
+func mmcExec(cd *AudioCD, cdb []byte, dir Dir, buf []byte, timeout time.Duration) error {
+	var cdir C.int
+	switch dir {
+	case DirIn:
+		cdir = C.SG_DXFER_FROM_DEV
+	case DirOut:
+		cdir = C.SG_DXFER_TO_DEV
+	default:
+		cdir = C.SG_DXFER_NONE
+	}
+	var pbuf unsafe.Pointer
+	if len(buf) > 0 {
+		pbuf = unsafe.Pointer(&buf[0])
+	}
+	sense := make([]byte, 32)
+
+	res := C.bridge_sg_io(driveFD(cd.drive), (*C.uchar)(&cdb[0]), C.int(len(cdb)), cdir,
+		pbuf, C.uint(len(buf)), (*C.uchar)(&sense[0]), C.int(len(sense)), C.uint(timeout.Milliseconds()))
+	if res < 0 {
+		return fmt.Errorf("audiocd: mmc command 0x%02x: %w", cdb[0], syscall.Errno(-res))
+	}
+	if res > 0 {
+		return parseSense(cdb[0], sense[:res])
+	}
+	return nil
+}
+
+// cdromIoctl issues a Linux CDROM ioctl on the drive.
+func cdromIoctl(cd *AudioCD, req C.ulong, arg C.ulong) (int, error) {
+	res := int(C.bridge_ioctl(driveFD(cd.drive), req, arg))
+	if res < 0 {
+		return 0, fmt.Errorf("audiocd: cdrom ioctl 0x%04x: %w", int(req), syscall.Errno(-res))
+	}
+	return res, nil
+}
+
+func discStatus(cd *AudioCD) (DiscMode, error) {
+	res, err := cdromIoctl(cd, C.CDROM_DISC_STATUS, 0)
+	if err != nil {
+		return DiscModeUnknown, err
+	}
+	switch res {
+	case C.CDS_NO_DISC:
+		return DiscModeUnknown, ErrNoMediumPresent
+	case C.CDS_AUDIO:
+		return DiscModeCDDA, nil
+	case C.CDS_DATA_1:
+		return DiscModeCDROMMode1, nil
+	case C.CDS_DATA_2:
+		return DiscModeCDROMMode2, nil
+	case C.CDS_XA_2_1, C.CDS_XA_2_2:
+		return DiscModeCDROMXA, nil
+	case C.CDS_MIXED:
+		return DiscModeMixed, nil
+	default:
+		return DiscModeUnknown, nil
+	}
+}
+
 func closeDrive(d unsafe.Pointer) {
 	C.cdda_close((*C.cdrom_drive)(d))
 }
@@ -310,3 +592,20 @@ func (dt DriveType) String() string {
 		return "unknown"
 	}
 }
+
+func (it InterfaceType) String() string {
+	switch it {
+	case GENERIC_SCSI:
+		return "generic SCSI"
+	case COOKED_IOCTL:
+		return "cooked ioctl"
+	case TEST_INTERFACE:
+		return "test interface"
+	case SGIO_SCSI:
+		return "SG_IO SCSI"
+	case SGIO_SCSI_BUGGY1:
+		return "SG_IO SCSI (buggy kernel workaround)"
+	default:
+		return "unknown"
+	}
+}
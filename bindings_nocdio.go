@@ -0,0 +1,19 @@
+//go:build !linux || !cdio
+
+package audiocd
+
+func openCdio(path string, lm LogMode, mode ParanoiaFlags) (*cdioReader, error) {
+	return nil, ErrCdioUnsupported
+}
+
+func closeCdio(r *cdioReader) {}
+
+func cdioSetMode(r *cdioReader, flags ParanoiaFlags) {}
+
+func cdioSeek(r *cdioReader, sector int) error {
+	return ErrCdioUnsupported
+}
+
+func cdioRead(cd *AudioCD, r *cdioReader, p []byte, retries int) error {
+	return ErrCdioUnsupported
+}
@@ -6,6 +6,7 @@ import (
 	"crypto/rand"
 	"fmt"
 	"os"
+	"time"
 	"unsafe"
 )
 
@@ -19,6 +20,36 @@ func openDrive(cd *AudioCD) error {
 	return nil
 }
 
+func defaultDevice() (string, error) {
+	return "", ErrNoDrive
+}
+
+func identifyDrive(path string) (unsafe.Pointer, error) {
+	return nil, ErrNoDrive
+}
+
+func driveDevices() []string {
+	return nil
+}
+
+func driveStatus(path string) (driveState, bool, error) {
+	return driveUnknown, false, ErrOperationNotSupported
+}
+
+func resetDevice(path string) error {
+	return nil
+}
+
+func devicePath(drive unsafe.Pointer) string {
+	return ""
+}
+
+func driveBigEndian(cd *AudioCD) int {
+	return 0
+}
+
+func setDriveBigEndian(cd *AudioCD, big bool) {}
+
 func model(drive unsafe.Pointer) string {
 	return "Mock AudioCD implementation"
 }
@@ -78,6 +109,14 @@ func readLimited(cd *AudioCD, p []byte, retries int) error {
 	return err
 }
 
+func mmcExec(cd *AudioCD, cdb []byte, dir Dir, buf []byte, timeout time.Duration) error {
+	return ErrOperationNotSupported
+}
+
+func discStatus(cd *AudioCD) (DiscMode, error) {
+	return DiscModeCDDA, nil
+}
+
 func closeDrive(d unsafe.Pointer) {}
 
 func paranoiaFree(p unsafe.Pointer) {}
@@ -0,0 +1,81 @@
+package audiocd
+
+import (
+	"errors"
+	"os"
+)
+
+// DefaultCacheDefeatSectors is how far away a sector is read between
+// the re-reads of [ReadModeSecure] by default. It's larger than the
+// cache of most drives.
+const DefaultCacheDefeatSectors = 2000
+
+// SetCacheDefeat sets how far away, in sectors, a sector is read
+// between the re-reads of [ReadModeSecure], so the drive has to
+// re-read the disc instead of returning the data from its cache.
+// Drives with a large cache need a larger distance. 0 selects
+// [DefaultCacheDefeatSectors], and a negative value disables the
+// seeks, e.g. if the cache was disabled with [*AudioCD.SetReadCache].
+func (cd *AudioCD) SetCacheDefeat(sectors int) {
+	cd.cacheDefeat = sectors
+}
+
+// cacheDefeatSector returns the sector to read to flush the drive's
+// cache before re-reading the given sector, or -1 if none.
+func (cd *AudioCD) cacheDefeatSector(sector int) int {
+	distance := cd.cacheDefeat
+	if distance < 0 {
+		return -1
+	}
+	if distance == 0 {
+		distance = DefaultCacheDefeatSectors
+	}
+	if sector+distance < cd.LengthSectors() {
+		return sector + distance
+	}
+	return max(sector-distance, 0)
+}
+
+// SetReadCache enables or disables the drive's read cache, with the
+// RCD bit of the caching mode page. With the cache disabled, every
+// read goes to the disc. Many drives ignore the setting or don't
+// support the page, in which case an error is returned; check
+// [*AudioCD.ReadCacheEnabled] to be sure. The setting lasts until the
+// drive is reset.
+//
+// This requires a drive which supports MMC commands.
+func (cd *AudioCD) SetReadCache(enabled bool) error {
+	if !cd.IsOpen() {
+		return os.ErrClosed
+	}
+	page, err := cd.modeSense(modePageCaching)
+	if err != nil {
+		return err
+	}
+	if len(page) < 3 {
+		return errors.New("audiocd: caching mode page too short")
+	}
+	if enabled {
+		page[2] &^= 0x01
+	} else {
+		page[2] |= 0x01
+	}
+	return cd.modeSelect(page)
+}
+
+// ReadCacheEnabled reports whether the drive's read cache is enabled.
+//
+// This requires a drive which supports MMC commands.
+func (cd *AudioCD) ReadCacheEnabled() (bool, error) {
+	if !cd.IsOpen() {
+		return false, os.ErrClosed
+	}
+	page, err := cd.modeSense(modePageCaching)
+	if err != nil {
+		return false, err
+	}
+	if len(page) < 3 {
+		return false, errors.New("audiocd: caching mode page too short")
+	}
+	return page[2]&0x01 == 0, nil
+}
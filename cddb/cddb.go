@@ -0,0 +1,289 @@
+// Package cddb queries FreeDB-compatible CDDB servers such as
+// [gnudb] over the CDDB HTTP protocol, and parses XMCD disc entries.
+//
+// Example:
+//
+//	client := cddb.Client{User: "me", Host: "example.com"}
+//	matches, err := client.Query(ctx, cd.TOC())
+//	if err != nil {
+//		return err
+//	}
+//	disc, err := client.Read(ctx, matches[0])
+//
+// [gnudb]: https://gnudb.org
+package cddb
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/rabidaudio/audiocd"
+)
+
+// DefaultServer is the CDDB HTTP endpoint used if none is specified.
+const DefaultServer = "https://gnudb.gnudb.org/~cddb/cddb.cgi"
+
+// protocolLevel is the CDDB protocol level requested. Level 6 uses UTF-8.
+const protocolLevel = 6
+
+// ErrNotFound is returned when the server has no entry for the disc.
+var ErrNotFound = errors.New("cddb: no matching disc")
+
+// Client queries a CDDB server. The zero value uses [DefaultServer].
+//
+// The User, Host, ClientName and ClientVersion fields are sent in the
+// hello handshake. Some servers require User to be a registered e-mail
+// address.
+type Client struct {
+	HTTPClient    *http.Client // the client to make requests with. If nil, http.DefaultClient is used
+	Server        string       // the cddb.cgi URL. If empty, DefaultServer is used
+	User          string
+	Host          string
+	ClientName    string
+	ClientVersion string
+}
+
+// Match is a disc entry found by [*Client.Query].
+type Match struct {
+	Genre  string // the CDDB category, e.g. "rock"
+	DiscID string
+	Artist string
+	Title  string
+	Exact  bool // whether the server reported an exact match
+}
+
+// Query looks up the disc with the given table of contents.
+// Returns [ErrNotFound] if there are no matches.
+func (c *Client) Query(ctx context.Context, toc []audiocd.TrackPosition) ([]Match, error) {
+	if len(toc) == 0 {
+		return nil, fmt.Errorf("cddb: empty table of contents")
+	}
+	cmd := fmt.Sprintf("cddb query %08x %d", audiocd.CDDBDiscID(toc), len(toc))
+	for _, offset := range frameOffsets(toc) {
+		cmd += fmt.Sprintf(" %d", offset)
+	}
+	cmd += fmt.Sprintf(" %d", discLength(toc))
+
+	code, lines, err := c.command(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	switch code {
+	case 200:
+		// single exact match on the status line
+		_, match, _ := strings.Cut(lines[0], " ")
+		m, ok := parseMatch(match)
+		if !ok {
+			return nil, fmt.Errorf("cddb: invalid response: %v", lines[0])
+		}
+		m.Exact = true
+		return []Match{m}, nil
+	case 210, 211:
+		var matches []Match
+		for _, line := range lines[1:] {
+			if m, ok := parseMatch(line); ok {
+				m.Exact = code == 210
+				matches = append(matches, m)
+			}
+		}
+		if len(matches) == 0 {
+			return nil, ErrNotFound
+		}
+		return matches, nil
+	case 202:
+		return nil, ErrNotFound
+	default:
+		return nil, fmt.Errorf("cddb: query failed: %v", lines[0])
+	}
+}
+
+// Read fetches the full entry for a match.
+func (c *Client) Read(ctx context.Context, m Match) (*Disc, error) {
+	code, lines, err := c.command(ctx, fmt.Sprintf("cddb read %s %s", m.Genre, m.DiscID))
+	if err != nil {
+		return nil, err
+	}
+	switch code {
+	case 210:
+		return ParseXMCD(strings.NewReader(strings.Join(lines[1:], "\n")))
+	case 401:
+		return nil, ErrNotFound
+	default:
+		return nil, fmt.Errorf("cddb: read failed: %v", lines[0])
+	}
+}
+
+// command runs a CDDB command and returns the status code and the
+// response lines, excluding the terminating ".".
+func (c *Client) command(ctx context.Context, cmd string) (int, []string, error) {
+	server := c.Server
+	if server == "" {
+		server = DefaultServer
+	}
+	hello := fmt.Sprintf("%s %s %s %s", hostOr(c.User, "anonymous"), hostOr(c.Host, "localhost"),
+		hostOr(c.ClientName, "audiocd"), hostOr(c.ClientVersion, "1"))
+	query := "cmd=" + url.QueryEscape(cmd) + "&hello=" + url.QueryEscape(hello) + "&proto=" + strconv.Itoa(protocolLevel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server+"?"+query, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return 0, nil, fmt.Errorf("cddb: unexpected response: %v", res.Status)
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "." {
+			break
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, nil, err
+	}
+	if len(lines) == 0 || len(lines[0]) < 3 {
+		return 0, nil, fmt.Errorf("cddb: empty response")
+	}
+	code, err := strconv.Atoi(lines[0][:3])
+	if err != nil {
+		return 0, nil, fmt.Errorf("cddb: invalid response: %v", lines[0])
+	}
+	return code, lines, nil
+}
+
+// frameOffsets returns the track offsets as CDDB expects them,
+// including the two second pregap.
+func frameOffsets(toc []audiocd.TrackPosition) []int {
+	offsets := make([]int, len(toc))
+	for i, t := range toc {
+		offsets[i] = t.StartSector + 2*audiocd.SectorsPerSecond
+	}
+	return offsets
+}
+
+// discLength returns the length of the disc in seconds,
+// including the two second pregap.
+func discLength(toc []audiocd.TrackPosition) int {
+	last := toc[len(toc)-1]
+	return (last.StartSector + last.LengthSectors + 2*audiocd.SectorsPerSecond) / audiocd.SectorsPerSecond
+}
+
+func hostOr(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return strings.ReplaceAll(s, " ", "_")
+}
+
+// parseMatch parses "genre discid artist / title".
+func parseMatch(line string) (Match, bool) {
+	fields := strings.SplitN(line, " ", 3)
+	if len(fields) < 3 {
+		return Match{}, false
+	}
+	artist, title := splitTitle(fields[2])
+	return Match{Genre: fields[0], DiscID: fields[1], Artist: artist, Title: title}, true
+}
+
+// splitTitle splits an "artist / title" string.
+// If there is no separator, the artist is the title.
+func splitTitle(s string) (artist, title string) {
+	artist, title, ok := strings.Cut(s, " / ")
+	if !ok {
+		return strings.TrimSpace(s), strings.TrimSpace(s)
+	}
+	return strings.TrimSpace(artist), strings.TrimSpace(title)
+}
+
+// Disc is a disc entry in XMCD format.
+type Disc struct {
+	DiscID   string
+	Artist   string
+	Title    string
+	Year     string
+	Genre    string
+	Extended string // EXTD, extended data about the disc
+	Tracks   []Track
+}
+
+// Track is a track entry of a [Disc].
+type Track struct {
+	Artist   string // the track artist, only set for various-artist discs
+	Title    string
+	Extended string // EXTTn, extended data about the track
+}
+
+// ParseXMCD parses a disc entry in XMCD format, as returned by
+// [*Client.Read] or stored in a FreeDB dump.
+func ParseXMCD(r io.Reader) (*Disc, error) {
+	values := make(map[string]string)
+	ntracks := 0
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		// long values are continued on repeated keys
+		values[key] += value
+
+		for _, prefix := range []string{"TTITLE", "EXTT"} {
+			if n, err := strconv.Atoi(strings.TrimPrefix(key, prefix)); err == nil && strings.HasPrefix(key, prefix) {
+				ntracks = max(ntracks, n+1)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if _, ok := values["DTITLE"]; !ok {
+		return nil, fmt.Errorf("cddb: invalid xmcd entry: no DTITLE")
+	}
+
+	disc := &Disc{
+		DiscID:   values["DISCID"],
+		Year:     values["DYEAR"],
+		Genre:    values["DGENRE"],
+		Extended: unescape(values["EXTD"]),
+	}
+	disc.Artist, disc.Title = splitTitle(unescape(values["DTITLE"]))
+	for i := range ntracks {
+		t := Track{
+			Title:    unescape(values[fmt.Sprintf("TTITLE%d", i)]),
+			Extended: unescape(values[fmt.Sprintf("EXTT%d", i)]),
+		}
+		if a, title, ok := strings.Cut(t.Title, " / "); ok {
+			t.Artist, t.Title = strings.TrimSpace(a), strings.TrimSpace(title)
+		}
+		disc.Tracks = append(disc.Tracks, t)
+	}
+	return disc, nil
+}
+
+// unescape decodes the \n, \t and \\ escapes in XMCD values.
+func unescape(s string) string {
+	return strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\\`, `\`).Replace(s)
+}
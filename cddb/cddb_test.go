@@ -0,0 +1,142 @@
+package cddb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rabidaudio/audiocd"
+	"github.com/stretchr/testify/assert"
+)
+
+var testTOC = []audiocd.TrackPosition{
+	{TrackNum: 1, StartSector: 0, LengthSectors: 6290},
+	{TrackNum: 2, StartSector: 6290, LengthSectors: 17021},
+}
+
+const testEntry = `# xmcd
+#
+# Track frame offsets:
+#	150
+#	6440
+#
+# Disc length: 312 seconds
+#
+DISCID=0f013602
+DTITLE=Various / Compilation
+DYEAR=1999
+DGENRE=Rock
+TTITLE0=A / One
+TTITLE1=B / Tw
+TTITLE1=o
+EXTD=line 1\nline 2
+EXTT0=
+PLAYORDER=
+`
+
+func TestQueryAndRead(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "me example.com app 2", r.URL.Query().Get("hello"))
+		assert.Equal(t, "6", r.URL.Query().Get("proto"))
+		switch cmd := r.URL.Query().Get("cmd"); cmd {
+		case "cddb query 0f013602 2 150 6440 312":
+			w.Write([]byte("211 Found inexact matches, list follows (until terminating `.')\r\nrock 0f013602 Various / Compilation\r\nmisc 12013703 Other / Thing\r\n.\r\n"))
+		case "cddb read rock 0f013602":
+			w.Write([]byte("210 rock 0f013602 CD database entry follows (until terminating `.')\r\n" + testEntry + ".\r\n"))
+		default:
+			t.Errorf("unexpected command: %v", cmd)
+		}
+	}))
+	defer srv.Close()
+
+	client := Client{Server: srv.URL, User: "me", Host: "example.com", ClientName: "app", ClientVersion: "2"}
+	matches, err := client.Query(context.Background(), testTOC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []Match{
+		{Genre: "rock", DiscID: "0f013602", Artist: "Various", Title: "Compilation"},
+		{Genre: "misc", DiscID: "12013703", Artist: "Other", Title: "Thing"},
+	}, matches)
+
+	disc, err := client.Read(context.Background(), matches[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &Disc{
+		DiscID:   "0f013602",
+		Artist:   "Various",
+		Title:    "Compilation",
+		Year:     "1999",
+		Genre:    "Rock",
+		Extended: "line 1\nline 2",
+		Tracks: []Track{
+			{Artist: "A", Title: "One"},
+			{Artist: "B", Title: "Two"},
+		},
+	}, disc)
+}
+
+func TestQueryNoMatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("202 No match found.\r\n"))
+	}))
+	defer srv.Close()
+
+	client := Client{Server: srv.URL}
+	_, err := client.Query(context.Background(), testTOC)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestQueryTruncated(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("200\r\n"))
+	}))
+	defer srv.Close()
+
+	client := Client{Server: srv.URL}
+	_, err := client.Query(context.Background(), testTOC)
+	assert.ErrorContains(t, err, "invalid response")
+}
+
+func TestParseXMCDInvalid(t *testing.T) {
+	_, err := ParseXMCD(strings.NewReader("# xmcd\n"))
+	assert.Error(t, err)
+}
+
+func TestWriteXMCD(t *testing.T) {
+	long := strings.Repeat("ä", 200) + "\\end"
+	disc := &Disc{
+		Artist:   "Various",
+		Title:    "Compilation",
+		Year:     "1999",
+		Genre:    "Rock",
+		Extended: "line 1\nline 2",
+		Tracks: []Track{
+			{Artist: "A", Title: "One"},
+			{Title: long},
+		},
+	}
+	sb := strings.Builder{}
+	err := WriteXMCD(&sb, testTOC, disc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := sb.String()
+	assert.True(t, strings.HasPrefix(out, "# xmcd\n#\n# Track frame offsets:\n#\t150\n#\t6440\n#\n# Disc length: 312 seconds\n"))
+	assert.Contains(t, out, "DISCID=0f013602\n")
+	assert.Contains(t, out, "EXTD=line 1\\nline 2\n")
+	for line := range strings.Lines(out) {
+		assert.LessOrEqual(t, len(line), maxXMCDLine)
+	}
+
+	parsed, err := ParseXMCD(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	disc.DiscID = "0f013602"
+	disc.Tracks[1].Extended = ""
+	assert.Equal(t, disc, parsed)
+}
@@ -0,0 +1,97 @@
+package cddb
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rabidaudio/audiocd"
+)
+
+// maxXMCDLine is the maximum length of a line in an XMCD file.
+// Longer values are split over several lines with the same key.
+const maxXMCDLine = 256
+
+// WriteXMCD writes a disc entry in XMCD format for the given table of
+// contents, as consumed by legacy jukebox software and FreeDB dumps.
+// disc provides the metadata and may be nil, in which case the titles
+// are left empty. If disc.DiscID is empty, it's computed from toc.
+func WriteXMCD(w io.Writer, toc []audiocd.TrackPosition, disc *Disc) error {
+	if len(toc) == 0 {
+		return fmt.Errorf("cddb: empty table of contents")
+	}
+	if disc == nil {
+		disc = &Disc{}
+	}
+	discID := disc.DiscID
+	if discID == "" {
+		discID = fmt.Sprintf("%08x", audiocd.CDDBDiscID(toc))
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "# xmcd")
+	fmt.Fprintln(bw, "#")
+	fmt.Fprintln(bw, "# Track frame offsets:")
+	for _, offset := range frameOffsets(toc) {
+		fmt.Fprintf(bw, "#\t%d\n", offset)
+	}
+	fmt.Fprintln(bw, "#")
+	fmt.Fprintf(bw, "# Disc length: %d seconds\n", discLength(toc))
+	fmt.Fprintln(bw, "#")
+	fmt.Fprintln(bw, "# Revision: 0")
+	fmt.Fprintln(bw, "# Submitted via: audiocd")
+	fmt.Fprintln(bw, "#")
+
+	writeXMCDValue(bw, "DISCID", discID)
+	writeXMCDValue(bw, "DTITLE", joinTitle(disc.Artist, disc.Title))
+	writeXMCDValue(bw, "DYEAR", disc.Year)
+	writeXMCDValue(bw, "DGENRE", disc.Genre)
+	for i := range toc {
+		var title string
+		if i < len(disc.Tracks) {
+			title = joinTitle(disc.Tracks[i].Artist, disc.Tracks[i].Title)
+		}
+		writeXMCDValue(bw, fmt.Sprintf("TTITLE%d", i), title)
+	}
+	writeXMCDValue(bw, "EXTD", disc.Extended)
+	for i := range toc {
+		var ext string
+		if i < len(disc.Tracks) {
+			ext = disc.Tracks[i].Extended
+		}
+		writeXMCDValue(bw, fmt.Sprintf("EXTT%d", i), ext)
+	}
+	writeXMCDValue(bw, "PLAYORDER", "")
+	return bw.Flush()
+}
+
+// joinTitle formats an "artist / title" string, the inverse of splitTitle.
+func joinTitle(artist, title string) string {
+	if artist == "" || artist == title {
+		return title
+	}
+	return artist + " / " + title
+}
+
+// writeXMCDValue writes a key=value line, escaping the value and
+// splitting it over several lines if it's too long. Escape sequences
+// and UTF-8 characters aren't split.
+func writeXMCDValue(w *bufio.Writer, key, value string) {
+	limit := maxXMCDLine - len(key) - 2 // "=" and newline
+	line := strings.Builder{}
+	for _, r := range value {
+		s := escape(string(r))
+		if line.Len()+len(s) > limit {
+			fmt.Fprintf(w, "%s=%s\n", key, line.String())
+			line.Reset()
+		}
+		line.WriteString(s)
+	}
+	fmt.Fprintf(w, "%s=%s\n", key, line.String())
+}
+
+// escape encodes newlines, tabs and backslashes in XMCD values.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\t", `\t`).Replace(s)
+}
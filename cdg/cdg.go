@@ -0,0 +1,151 @@
+// Package cdg extracts and decodes CD+G (CD+Graphics) karaoke graphics,
+// which are stored in the R-W subchannels of an audio CD.
+//
+// The subchannel data of each sector holds four 24-byte packets. They
+// can be written out as a .cdg file, which most karaoke players accept
+// alongside the audio, or rendered with a [Screen]:
+//
+//	f, err := os.Create("track1.cdg")
+//	...
+//	err = cdg.Extract(cd, t.StartSector, t.StartSector+t.LengthSectors, f)
+//
+// Extracting requires a drive which can read the raw subchannel,
+// see [audiocd.AudioCD.ReadRawSectors].
+package cdg
+
+import (
+	"io"
+
+	"github.com/rabidaudio/audiocd"
+)
+
+// PacketSize is the size of a CD+G packet (a subchannel pack),
+// 24 six-bit symbols stored one per byte.
+const PacketSize = 24
+
+// PacketsPerSector is the number of packets in the subchannel data
+// of one sector. At 75 sectors per second, that's 300 packets per second.
+const PacketsPerSector = audiocd.SubchannelBytesPerSector / PacketSize
+
+// commandCDG is the command of packets which carry graphics.
+const commandCDG = 0x09
+
+// Packet is a single subchannel pack.
+type Packet struct {
+	Command     byte     // 0x09 for CD+G packets
+	Instruction byte     // the graphics instruction, e.g. [InstructionTileBlock]
+	Data        [16]byte // the instruction's operands, six bits each
+}
+
+// IsCDG reports whether the packet carries a CD+G instruction.
+// Other packets are usually empty.
+func (p Packet) IsCDG() bool {
+	return p.Command == commandCDG
+}
+
+// Bytes encodes the packet as it's stored in a .cdg file,
+// with the parity symbols zeroed.
+func (p Packet) Bytes() []byte {
+	b := make([]byte, PacketSize)
+	b[0] = p.Command
+	b[1] = p.Instruction
+	copy(b[4:20], p.Data[:])
+	return b
+}
+
+// ParsePacket decodes a packet from 24 symbols, as stored in a .cdg file.
+func ParsePacket(b []byte) Packet {
+	p := Packet{Command: b[0] & 0x3F, Instruction: b[1] & 0x3F}
+	for i := range p.Data {
+		p.Data[i] = b[4+i] & 0x3F
+	}
+	return p
+}
+
+// interleaveDelay is the number of packs the symbols of a pack are
+// spread over on the disc.
+const interleaveDelay = 8
+
+// Deinterleaver recovers packets from the raw P-W subchannel data of
+// consecutive sectors. On the disc, symbol j of each pack is delayed by
+// j mod 8 packs and symbols 1, 2 and 3 are swapped with symbols 18, 5
+// and 23, so each packet is complete only 7 packs later. The zero value
+// is ready to use.
+type Deinterleaver struct {
+	packs [interleaveDelay][PacketSize]byte // the last packs read, as a ring
+	n     int                               // the number of packs read
+}
+
+// Write takes the raw subchannel data of one or more sectors, as
+// returned by [audiocd.AudioCD.ReadRawSectors] without the audio, and
+// returns the packets completed by it. The first 7 packs read don't
+// complete any packets.
+func (d *Deinterleaver) Write(subchannel []byte) []Packet {
+	var packets []Packet
+	for i := 0; i+PacketSize <= len(subchannel); i += PacketSize {
+		pack := &d.packs[d.n%interleaveDelay]
+		for j := range PacketSize {
+			pack[j] = subchannel[i+j] & 0x3F // R-W are the low six bits
+		}
+		d.n++
+		if d.n < interleaveDelay {
+			continue
+		}
+
+		// the oldest pack in the ring holds symbols 0, 8 and 16
+		var b [PacketSize]byte
+		for j := range PacketSize {
+			b[j] = d.packs[(d.n+j%interleaveDelay)%interleaveDelay][j]
+		}
+		b[1], b[18] = b[18], b[1]
+		b[2], b[5] = b[5], b[2]
+		b[3], b[23] = b[23], b[3]
+		packets = append(packets, ParsePacket(b[:]))
+	}
+	return packets
+}
+
+// Extract reads the CD+G packets of the sectors [start, end) of the disc
+// and writes them to w as a .cdg packet stream. Since the packets are
+// spread over the following 7 packs, the two sectors after end are read
+// as well if there are any.
+func Extract(cd *audiocd.AudioCD, start, end int, w io.Writer) error {
+	return extract(cd, cd.LeadOut(), start, end, w)
+}
+
+// rawReader reads sectors with their subchannel data, like
+// [audiocd.AudioCD.ReadRawSectors].
+type rawReader interface {
+	ReadRawSectors(sector int, p []byte) (int, error)
+}
+
+func extract(cd rawReader, leadOut, start, end int, w io.Writer) error {
+	const chunk = 24
+	// the last packet of a sector is completed 7 packs later,
+	// in the second sector after it
+	readEnd := min(end+2, leadOut)
+	buf := make([]byte, chunk*audiocd.RawBytesPerSector)
+	d := Deinterleaver{}
+	want := (end - start) * PacketsPerSector
+	for sector := start; sector < readEnd && want > 0; sector += chunk {
+		n := min(chunk, readEnd-sector)
+		_, err := cd.ReadRawSectors(sector, buf[:n*audiocd.RawBytesPerSector])
+		if err != nil {
+			return err
+		}
+		for i := range n {
+			raw := buf[i*audiocd.RawBytesPerSector : (i+1)*audiocd.RawBytesPerSector]
+			for _, p := range d.Write(raw[audiocd.BytesPerSector:]) {
+				if want == 0 {
+					break
+				}
+				_, err := w.Write(p.Bytes())
+				if err != nil {
+					return err
+				}
+				want--
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package cdg
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+
+	"github.com/rabidaudio/audiocd"
+	"github.com/stretchr/testify/assert"
+)
+
+// interleave spreads packs over the stream as they're stored on the disc.
+func interleave(packs [][PacketSize]byte) []byte {
+	stream := make([]byte, (len(packs)+interleaveDelay-1)*PacketSize)
+	for k, pack := range packs {
+		pack[1], pack[18] = pack[18], pack[1]
+		pack[2], pack[5] = pack[5], pack[2]
+		pack[3], pack[23] = pack[23], pack[3]
+		for j, sym := range pack {
+			stream[(k+j%interleaveDelay)*PacketSize+j] = sym | 0xC0 // P and Q bits set
+		}
+	}
+	return stream
+}
+
+func TestDeinterleaver(t *testing.T) {
+	var packs [][PacketSize]byte
+	for k := range 10 {
+		var pack [PacketSize]byte
+		for j := range pack {
+			pack[j] = byte(k + j)
+		}
+		packs = append(packs, pack)
+	}
+	stream := interleave(packs)
+
+	d := Deinterleaver{}
+	// feed it in uneven pieces
+	packets := d.Write(stream[:5*PacketSize])
+	assert.Empty(t, packets)
+	packets = append(packets, d.Write(stream[5*PacketSize:])...)
+
+	assert.Len(t, packets, len(packs))
+	for k, p := range packets {
+		assert.Equal(t, ParsePacket(packs[k][:]), p)
+	}
+}
+
+// fakeDisc holds the raw sectors of a disc, audio and subchannel.
+type fakeDisc []byte
+
+func (d fakeDisc) ReadRawSectors(sector int, p []byte) (int, error) {
+	return copy(p, d[sector*audiocd.RawBytesPerSector:]), nil
+}
+
+func TestExtract(t *testing.T) {
+	const sectors = 10
+	var packs [][PacketSize]byte
+	for k := range sectors * PacketsPerSector {
+		var pack [PacketSize]byte
+		pack[0] = byte(k)
+		packs = append(packs, pack)
+	}
+	stream := interleave(packs)
+	disc := make(fakeDisc, sectors*audiocd.RawBytesPerSector)
+	for i := range sectors {
+		raw := disc[i*audiocd.RawBytesPerSector : (i+1)*audiocd.RawBytesPerSector]
+		copy(raw[audiocd.BytesPerSector:], stream[i*audiocd.SubchannelBytesPerSector:])
+	}
+
+	var buf bytes.Buffer
+	err := extract(disc, sectors, 2, 5, &buf)
+	assert.NoError(t, err)
+	want := 3 * PacketsPerSector
+	if assert.Equal(t, want*PacketSize, buf.Len()) {
+		for i := range want {
+			assert.Equal(t, ParsePacket(packs[2*PacketsPerSector+i][:]).Bytes(), buf.Bytes()[i*PacketSize:(i+1)*PacketSize])
+		}
+	}
+}
+
+func TestScreen(t *testing.T) {
+	s := NewScreen()
+	colors := Packet{Command: commandCDG, Instruction: InstructionLoadColorsLow}
+	colors.Data[2], colors.Data[3] = 0x3F, 0x3F // color 1 is white
+	s.Apply(colors)
+	assert.Equal(t, color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}, s.Palette[1])
+
+	tile := Packet{Command: commandCDG, Instruction: InstructionTileBlock}
+	tile.Data[0], tile.Data[1] = 0, 1
+	tile.Data[2], tile.Data[3] = 1, 1 // the first displayed tile
+	tile.Data[4] = 0x20               // top left pixel
+	s.Apply(tile)
+	assert.Equal(t, uint8(1), s.Pixels[12][6])
+	assert.Equal(t, uint8(0), s.Pixels[12][7])
+
+	img := s.Image()
+	assert.Equal(t, uint8(1), img.ColorIndexAt(0, 0))
+
+	xor := tile
+	xor.Instruction = InstructionTileBlockXOR
+	s.Apply(xor)
+	assert.Equal(t, uint8(0), s.Pixels[12][6])
+
+	// scroll right by a tile with wrapping
+	s.Apply(tile)
+	scroll := Packet{Command: commandCDG, Instruction: InstructionScrollCopy}
+	scroll.Data[1] = 0x10
+	s.Apply(scroll)
+	assert.Equal(t, uint8(1), s.Pixels[12][12])
+	assert.Equal(t, uint8(0), s.Pixels[12][6])
+}
@@ -0,0 +1,170 @@
+package cdg
+
+import (
+	"image"
+	"image/color"
+)
+
+// CD+G instructions
+const (
+	InstructionMemoryPreset      byte = 1
+	InstructionBorderPreset      byte = 2
+	InstructionTileBlock         byte = 6
+	InstructionScrollPreset      byte = 20
+	InstructionScrollCopy        byte = 24
+	InstructionDefineTransparent byte = 28
+	InstructionLoadColorsLow     byte = 30
+	InstructionLoadColorsHigh    byte = 31
+	InstructionTileBlockXOR      byte = 38
+)
+
+// Screen dimensions in pixels. Only the area within the border
+// is displayed.
+const (
+	Width         = 300
+	Height        = 216
+	DisplayWidth  = 288
+	DisplayHeight = 192
+	tileWidth     = 6
+	tileHeight    = 12
+)
+
+// Screen is the state of a CD+G display: a 300x216 grid of indexes
+// into a 16 color palette. Apply packets in order to render the
+// graphics, and take an Image at any point for a video frame.
+type Screen struct {
+	Pixels  [Height][Width]uint8
+	Palette [16]color.RGBA
+
+	hOffset, vOffset int // the smooth scrolling offsets in pixels
+}
+
+// NewScreen returns a blank screen with a black palette.
+func NewScreen() *Screen {
+	s := &Screen{}
+	for i := range s.Palette {
+		s.Palette[i] = color.RGBA{A: 0xFF}
+	}
+	return s
+}
+
+// Apply executes a packet. Packets which aren't CD+G instructions
+// are ignored.
+func (s *Screen) Apply(p Packet) {
+	if !p.IsCDG() {
+		return
+	}
+	switch p.Instruction {
+	case InstructionMemoryPreset:
+		s.fill(0, 0, Width, Height, p.Data[0]&0x0F)
+	case InstructionBorderPreset:
+		c := p.Data[0] & 0x0F
+		s.fill(0, 0, Width, tileHeight, c)
+		s.fill(0, Height-tileHeight, Width, Height, c)
+		s.fill(0, 0, tileWidth, Height, c)
+		s.fill(Width-tileWidth, 0, Width, Height, c)
+	case InstructionTileBlock, InstructionTileBlockXOR:
+		s.tile(p.Data, p.Instruction == InstructionTileBlockXOR)
+	case InstructionScrollPreset, InstructionScrollCopy:
+		s.scroll(p.Data, p.Instruction == InstructionScrollCopy)
+	case InstructionLoadColorsLow, InstructionLoadColorsHigh:
+		base := 0
+		if p.Instruction == InstructionLoadColorsHigh {
+			base = 8
+		}
+		for i := range 8 {
+			hi, lo := p.Data[i*2], p.Data[i*2+1]
+			r := hi >> 2 & 0x0F
+			g := (hi&0x03)<<2 | lo>>4&0x03
+			b := lo & 0x0F
+			s.Palette[base+i] = color.RGBA{R: r * 17, G: g * 17, B: b * 17, A: 0xFF}
+		}
+	}
+}
+
+func (s *Screen) fill(x0, y0, x1, y1 int, c uint8) {
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			s.Pixels[y][x] = c
+		}
+	}
+}
+
+// tile draws a 6x12 block of two colors.
+func (s *Screen) tile(data [16]byte, xor bool) {
+	c0, c1 := data[0]&0x0F, data[1]&0x0F
+	row, col := int(data[2]&0x1F), int(data[3]&0x3F)
+	x0, y0 := col*tileWidth, row*tileHeight
+	if x0+tileWidth > Width || y0+tileHeight > Height {
+		return
+	}
+	for y := range tileHeight {
+		bits := data[4+y]
+		for x := range tileWidth {
+			c := c0
+			if bits&(0x20>>x) != 0 {
+				c = c1
+			}
+			if xor {
+				s.Pixels[y0+y][x0+x] ^= c
+			} else {
+				s.Pixels[y0+y][x0+x] = c
+			}
+		}
+	}
+}
+
+// scroll moves the screen by a tile, filling the uncovered area with
+// a color (preset) or the area scrolled off the other side (copy).
+func (s *Screen) scroll(data [16]byte, wrap bool) {
+	c := data[0] & 0x0F
+	hCmd, vCmd := data[1]>>4&0x03, data[2]>>4&0x03
+	s.hOffset, s.vOffset = int(data[1]&0x07), int(data[2]&0x0F)
+
+	var dx, dy int
+	switch hCmd {
+	case 1:
+		dx = tileWidth
+	case 2:
+		dx = -tileWidth
+	}
+	switch vCmd {
+	case 1:
+		dy = tileHeight
+	case 2:
+		dy = -tileHeight
+	}
+	if dx == 0 && dy == 0 {
+		return
+	}
+
+	old := s.Pixels
+	for y := range Height {
+		for x := range Width {
+			sx, sy := x-dx, y-dy
+			if wrap {
+				sx, sy = (sx+Width)%Width, (sy+Height)%Height
+			} else if sx < 0 || sx >= Width || sy < 0 || sy >= Height {
+				s.Pixels[y][x] = c
+				continue
+			}
+			s.Pixels[y][x] = old[sy][sx]
+		}
+	}
+}
+
+// Image returns the displayed area of the screen, taking the smooth
+// scrolling offsets into account.
+func (s *Screen) Image() *image.Paletted {
+	palette := make(color.Palette, len(s.Palette))
+	for i, c := range s.Palette {
+		palette[i] = c
+	}
+	img := image.NewPaletted(image.Rect(0, 0, DisplayWidth, DisplayHeight), palette)
+	for y := range DisplayHeight {
+		for x := range DisplayWidth {
+			img.SetColorIndex(x, y, s.Pixels[y+tileHeight+s.vOffset][x+tileWidth+s.hOffset])
+		}
+	}
+	return img
+}
@@ -0,0 +1,66 @@
+package audiocd
+
+import (
+	"errors"
+	"unsafe"
+)
+
+// ErrCdioUnsupported is returned by reads in [ReadModeCdio] when the
+// package was built without the cdio build tag.
+var ErrCdioUnsupported = errors.New("audiocd: built without libcdio-paranoia support")
+
+// cdioReader is a handle on the drive through libcdio-paranoia, for
+// [ReadModeCdio]. It's opened on the first read in that mode.
+type cdioReader struct {
+	drive    unsafe.Pointer // *C.cdrom_drive_t
+	paranoia unsafe.Pointer // *C.cdrom_paranoia_t
+	mode     ParanoiaFlags  // the mode set on paranoia
+	next     int            // the sector paranoia reads next, or -1 if unknown
+}
+
+// openCdio opens the libcdio-paranoia handle, if it isn't open.
+func (cd *AudioCD) openCdio() error {
+	if cd.cdio != nil {
+		return nil
+	}
+	path := cd.Device
+	if path == "" {
+		path = devicePath(cd.drive)
+	}
+	r, err := openCdio(path, cd.LogMode, cd.paranoiaMode)
+	if err != nil {
+		return err
+	}
+	cd.cdio = r
+	return nil
+}
+
+// readCdio reads the sector at the drive's cursor with libcdio-paranoia.
+func (cd *AudioCD) readCdio(p []byte, retries int) error {
+	err := cd.openCdio()
+	if err != nil {
+		return err
+	}
+	r := cd.cdio
+	if r.mode != cd.paranoiaMode {
+		cdioSetMode(r, cd.paranoiaMode)
+		r.mode = cd.paranoiaMode
+	}
+	if r.next != cd.driveSector {
+		err := cdioSeek(r, cd.driveSector)
+		if err != nil {
+			return err
+		}
+		r.next = cd.driveSector
+	}
+	cd.keepAlive.lock()
+	err = cdioRead(cd, r, p, retries)
+	cd.keepAlive.unlock()
+	if err != nil {
+		// paranoia may have moved on
+		r.next = -1
+		return err
+	}
+	r.next++
+	return nil
+}
@@ -0,0 +1,472 @@
+package audiocd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// CDText holds the CD-Text information from one language block of the disc.
+// Discs can carry up to eight blocks, each in a different language.
+type CDText struct {
+	Language   CDTextLanguageCode // the language of the block
+	Title      string             // album title
+	Performer  string             // album artist
+	Songwriter string
+	Composer   string
+	Arranger   string
+	Message    string
+	Genre      string
+	Tracks     []CDTextTrack
+}
+
+// CDTextTrack holds the CD-Text information for a single track.
+type CDTextTrack struct {
+	TrackNum   int
+	Title      string
+	Performer  string
+	Songwriter string
+	Composer   string
+	Arranger   string
+	Message    string
+}
+
+// Track returns the CD-Text for the track with the given number,
+// or a zero value if there is none.
+func (t CDText) Track(n int) CDTextTrack {
+	for _, tt := range t.Tracks {
+		if tt.TrackNum == n {
+			return tt
+		}
+	}
+	return CDTextTrack{TrackNum: n}
+}
+
+// CDTextLanguageCode is the EBU Tech 3258 language code of a CD-Text block.
+type CDTextLanguageCode byte
+
+const (
+	CDTextLanguageUnknown    CDTextLanguageCode = 0x00
+	CDTextLanguageCzech      CDTextLanguageCode = 0x06
+	CDTextLanguageDanish     CDTextLanguageCode = 0x07
+	CDTextLanguageGerman     CDTextLanguageCode = 0x08
+	CDTextLanguageEnglish    CDTextLanguageCode = 0x09
+	CDTextLanguageSpanish    CDTextLanguageCode = 0x0A
+	CDTextLanguageFrench     CDTextLanguageCode = 0x0F
+	CDTextLanguageItalian    CDTextLanguageCode = 0x15
+	CDTextLanguageHungarian  CDTextLanguageCode = 0x1B
+	CDTextLanguageDutch      CDTextLanguageCode = 0x1D
+	CDTextLanguageNorwegian  CDTextLanguageCode = 0x1E
+	CDTextLanguagePolish     CDTextLanguageCode = 0x20
+	CDTextLanguagePortuguese CDTextLanguageCode = 0x21
+	CDTextLanguageFinnish    CDTextLanguageCode = 0x27
+	CDTextLanguageSwedish    CDTextLanguageCode = 0x28
+	CDTextLanguageTurkish    CDTextLanguageCode = 0x29
+	CDTextLanguageRussian    CDTextLanguageCode = 0x56
+	CDTextLanguageKorean     CDTextLanguageCode = 0x65
+	CDTextLanguageJapanese   CDTextLanguageCode = 0x69
+	CDTextLanguageChinese    CDTextLanguageCode = 0x75
+)
+
+// Code returns the ISO 639-1 code for the language, e.g. "ja".
+// Languages without a known code are formatted as "x-" followed
+// by the hex EBU code.
+func (l CDTextLanguageCode) Code() string {
+	switch l {
+	case CDTextLanguageCzech:
+		return "cs"
+	case CDTextLanguageDanish:
+		return "da"
+	case CDTextLanguageGerman:
+		return "de"
+	case CDTextLanguageEnglish:
+		return "en"
+	case CDTextLanguageSpanish:
+		return "es"
+	case CDTextLanguageFrench:
+		return "fr"
+	case CDTextLanguageItalian:
+		return "it"
+	case CDTextLanguageHungarian:
+		return "hu"
+	case CDTextLanguageDutch:
+		return "nl"
+	case CDTextLanguageNorwegian:
+		return "no"
+	case CDTextLanguagePolish:
+		return "pl"
+	case CDTextLanguagePortuguese:
+		return "pt"
+	case CDTextLanguageFinnish:
+		return "fi"
+	case CDTextLanguageSwedish:
+		return "sv"
+	case CDTextLanguageTurkish:
+		return "tr"
+	case CDTextLanguageRussian:
+		return "ru"
+	case CDTextLanguageKorean:
+		return "ko"
+	case CDTextLanguageJapanese:
+		return "ja"
+	case CDTextLanguageChinese:
+		return "zh"
+	default:
+		return fmt.Sprintf("x-%02x", byte(l))
+	}
+}
+
+// SelectCDText returns the block in the given language. If there is no
+// such block, it falls back to the first block. This is useful for
+// picking a primary language, e.g. for generating filenames.
+func SelectCDText(blocks []CDText, lang CDTextLanguageCode) (CDText, bool) {
+	for _, b := range blocks {
+		if b.Language == lang {
+			return b, true
+		}
+	}
+	if len(blocks) > 0 {
+		return blocks[0], false
+	}
+	return CDText{}, false
+}
+
+// CDTextTags exports the CD-Text fields for a track (starting at 1)
+// as tags, e.g. for Vorbis comments. Fields from the primary language
+// block use plain tag names (TITLE), and fields from the other blocks
+// are suffixed with the language code (TITLE:ja). If there is
+// no block for the primary language, the first block is used.
+//
+// Empty fields are omitted.
+func CDTextTags(blocks []CDText, track int, primary CDTextLanguageCode) map[string]string {
+	tags := make(map[string]string)
+	main, _ := SelectCDText(blocks, primary)
+
+	for _, b := range blocks {
+		suffix := ""
+		if b.Language != main.Language {
+			suffix = ":" + b.Language.Code()
+		}
+		set := func(key, value string) {
+			if value != "" {
+				tags[key+suffix] = value
+			}
+		}
+		t := b.Track(track)
+		set("ALBUM", b.Title)
+		set("ALBUMARTIST", b.Performer)
+		set("GENRE", b.Genre)
+		set("TITLE", t.Title)
+		set("ARTIST", t.Performer)
+		set("LYRICIST", t.Songwriter)
+		set("COMPOSER", t.Composer)
+		set("ARRANGER", t.Arranger)
+		set("COMMENT", t.Message)
+	}
+	return tags
+}
+
+// ErrNoCDText is returned when the disc has no CD-Text.
+var ErrNoCDText = errors.New("audiocd: disc has no CD-Text")
+
+// CDText reads the CD-Text information from the disc. If the disc
+// has more than one language block, only the first is returned;
+// use [*AudioCD.CDTextBlocks] or [*AudioCD.CDTextLanguage] to read the others.
+//
+// Returns [ErrNoCDText] if the disc has no CD-Text.
+func (cd *AudioCD) CDText() (CDText, error) {
+	blocks, err := cd.CDTextBlocks()
+	if err != nil {
+		return CDText{}, err
+	}
+	return blocks[0], nil
+}
+
+// CDTextBlocks reads all the CD-Text language blocks from the disc,
+// in the order they are stored. There is at least one block if
+// the error is nil.
+//
+// Returns [ErrNoCDText] if the disc has no CD-Text.
+func (cd *AudioCD) CDTextBlocks() ([]CDText, error) {
+	data, err := cd.RawCDText()
+	if err != nil {
+		return nil, err
+	}
+	blocks := parseCDText(data[4:])
+	if len(blocks) == 0 {
+		return nil, ErrNoCDText
+	}
+	return blocks, nil
+}
+
+// CDTextLanguages lists the languages of the CD-Text blocks on the disc.
+//
+// Returns [ErrNoCDText] if the disc has no CD-Text.
+func (cd *AudioCD) CDTextLanguages() ([]CDTextLanguageCode, error) {
+	blocks, err := cd.CDTextBlocks()
+	if err != nil {
+		return nil, err
+	}
+	langs := make([]CDTextLanguageCode, len(blocks))
+	for i, b := range blocks {
+		langs[i] = b.Language
+	}
+	return langs, nil
+}
+
+// CDTextLanguage reads the CD-Text block in the given language.
+//
+// Returns [ErrNoCDText] if the disc has no CD-Text in that language.
+func (cd *AudioCD) CDTextLanguage(lang CDTextLanguageCode) (CDText, error) {
+	blocks, err := cd.CDTextBlocks()
+	if err != nil {
+		return CDText{}, err
+	}
+	for _, b := range blocks {
+		if b.Language == lang {
+			return b, nil
+		}
+	}
+	return CDText{}, ErrNoCDText
+}
+
+// RawCDText returns the CD-Text data of the disc verbatim, as a 4-byte
+// header (the big-endian data length plus two reserved bytes) followed
+// by the 18-byte packs. This is the layout of the .cdt files written by
+// cdrecord and accepted by cue sheets' CDTEXTFILE command, so it can be
+// archived or burned as-is.
+//
+// Returns [ErrNoCDText] if the disc has no CD-Text.
+func (cd *AudioCD) RawCDText() ([]byte, error) {
+	if !cd.IsOpen() {
+		return nil, os.ErrClosed
+	}
+	data, err := cd.readTOC(tocFormatCDText, 0)
+	if err != nil {
+		var se *SenseError
+		if errors.As(err, &se) && se.Key == SenseIllegalRequest {
+			return nil, ErrNoCDText
+		}
+		return nil, err
+	}
+	if len(data) < 4+cdTextPackSize {
+		return nil, ErrNoCDText
+	}
+	return data, nil
+}
+
+// CD-Text pack types
+const (
+	cdTextPackTitle      byte = 0x80
+	cdTextPackPerformer  byte = 0x81
+	cdTextPackSongwriter byte = 0x82
+	cdTextPackComposer   byte = 0x83
+	cdTextPackArranger   byte = 0x84
+	cdTextPackMessage    byte = 0x85
+	cdTextPackGenre      byte = 0x87
+	cdTextPackSizeInfo   byte = 0x8F
+)
+
+// cdTextPackSize is the size of one CD-Text pack, including the CRC.
+const cdTextPackSize = 18
+
+// CD-Text character sets
+const (
+	cdTextCharsetLatin1 byte = 0x00
+	cdTextCharsetASCII  byte = 0x01
+	cdTextCharsetMSJIS  byte = 0x80
+)
+
+// cdTextGenres are the genre codes defined by the CD-Text spec.
+var cdTextGenres = []string{
+	"", "", "Adult Contemporary", "Alternative Rock", "Childrens Music",
+	"Classical", "Contemporary Christian", "Country", "Dance", "Easy Listening",
+	"Erotic", "Folk", "Gospel", "Hip Hop", "Jazz", "Latin", "Musical",
+	"New Age", "Opera", "Operetta", "Pop Music", "Rap", "Reggae",
+	"Rock Music", "Rhythm & Blues", "Sound Effects", "Spoken Word", "World Music",
+}
+
+// parseCDText decodes CD-Text packs into one CDText per language block.
+// Packs with a bad CRC are ignored. Text in the MS-JIS character set
+// is returned undecoded.
+func parseCDText(data []byte) []CDText {
+	type stream struct {
+		firstTrack int
+		dbcc       bool
+		data       []byte
+	}
+	streams := make(map[[2]byte]*stream) // by block and pack type
+	var blocks []byte
+
+	for i := 0; i+cdTextPackSize <= len(data); i += cdTextPackSize {
+		pack := data[i : i+cdTextPackSize]
+		if crc := binary.BigEndian.Uint16(pack[16:]); crc != 0 && crc != ^crc16(pack[:16]) {
+			continue
+		}
+		typ := pack[0]
+		block := (pack[3] >> 4) & 0x07
+		if typ < cdTextPackTitle || typ > cdTextPackSizeInfo {
+			continue
+		}
+		if !slices.Contains(blocks, block) {
+			blocks = append(blocks, block)
+		}
+		k := [2]byte{block, typ}
+		s, ok := streams[k]
+		if !ok {
+			s = &stream{firstTrack: int(pack[1] & 0x7F), dbcc: pack[3]&0x80 != 0}
+			streams[k] = s
+		}
+		s.data = append(s.data, pack[4:16]...)
+	}
+	slices.Sort(blocks)
+
+	var res []CDText
+	for _, block := range blocks {
+		var size []byte
+		if s, ok := streams[[2]byte{block, cdTextPackSizeInfo}]; ok {
+			size = s.data
+		} else if s, ok := streams[[2]byte{0, cdTextPackSizeInfo}]; ok {
+			size = s.data
+		}
+		charset := cdTextCharsetLatin1
+		t := CDText{}
+		if len(size) >= 36 {
+			charset = size[0]
+			t.Language = CDTextLanguageCode(size[28+block])
+			for n := int(size[1]); n <= int(size[2]) && n > 0; n++ {
+				t.Tracks = append(t.Tracks, CDTextTrack{TrackNum: n})
+			}
+		}
+
+		track := func(n int) *CDTextTrack {
+			for i := range t.Tracks {
+				if t.Tracks[i].TrackNum == n {
+					return &t.Tracks[i]
+				}
+			}
+			t.Tracks = append(t.Tracks, CDTextTrack{TrackNum: n})
+			return &t.Tracks[len(t.Tracks)-1]
+		}
+
+		for typ := cdTextPackTitle; typ <= cdTextPackMessage; typ++ {
+			s, ok := streams[[2]byte{block, typ}]
+			if !ok {
+				continue
+			}
+			for i, str := range splitCDText(s.data, s.dbcc, charset) {
+				if str == "" {
+					continue
+				}
+				if n := s.firstTrack + i; n == 0 {
+					*t.field(typ) = str
+				} else {
+					*track(n).field(typ) = str
+				}
+			}
+		}
+
+		if s, ok := streams[[2]byte{block, cdTextPackGenre}]; ok && len(s.data) >= 2 {
+			code := int(binary.BigEndian.Uint16(s.data))
+			strs := splitCDText(s.data[2:], false, charset)
+			if len(strs) > 0 && strs[0] != "" {
+				t.Genre = strs[0]
+			} else if code < len(cdTextGenres) {
+				t.Genre = cdTextGenres[code]
+			}
+		}
+
+		slices.SortFunc(t.Tracks, func(a, b CDTextTrack) int { return a.TrackNum - b.TrackNum })
+		res = append(res, t)
+	}
+	return res
+}
+
+func (t *CDText) field(typ byte) *string {
+	switch typ {
+	case cdTextPackTitle:
+		return &t.Title
+	case cdTextPackPerformer:
+		return &t.Performer
+	case cdTextPackSongwriter:
+		return &t.Songwriter
+	case cdTextPackComposer:
+		return &t.Composer
+	case cdTextPackArranger:
+		return &t.Arranger
+	default:
+		return &t.Message
+	}
+}
+
+func (t *CDTextTrack) field(typ byte) *string {
+	switch typ {
+	case cdTextPackTitle:
+		return &t.Title
+	case cdTextPackPerformer:
+		return &t.Performer
+	case cdTextPackSongwriter:
+		return &t.Songwriter
+	case cdTextPackComposer:
+		return &t.Composer
+	case cdTextPackArranger:
+		return &t.Arranger
+	default:
+		return &t.Message
+	}
+}
+
+// splitCDText splits a stream of NUL-terminated strings. A tab
+// character repeats the previous string.
+func splitCDText(data []byte, dbcc bool, charset byte) []string {
+	var strs []string
+	width := 1
+	if dbcc {
+		width = 2
+	}
+	start := 0
+	for i := 0; i+width <= len(data); i += width {
+		if data[i] != 0 || (dbcc && data[i+1] != 0) {
+			continue
+		}
+		raw := data[start:i]
+		start = i + width
+
+		var str string
+		switch {
+		case string(raw) == "\t" || string(raw) == "\t\t":
+			if len(strs) > 0 {
+				str = strs[len(strs)-1]
+			}
+		case charset == cdTextCharsetLatin1:
+			runes := make([]rune, len(raw))
+			for j, b := range raw {
+				runes[j] = rune(b)
+			}
+			str = string(runes)
+		default:
+			str = string(raw)
+		}
+		strs = append(strs, str)
+	}
+	return strs
+}
+
+// crc16 computes the CRC-16/CCITT (polynomial 0x1021) used by
+// CD-Text packs and the Q subchannel.
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for range 8 {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
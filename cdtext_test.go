@@ -0,0 +1,89 @@
+package audiocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCDTextTags(t *testing.T) {
+	blocks := []CDText{
+		{
+			Language:  CDTextLanguageEnglish,
+			Title:     "Album",
+			Performer: "Artist",
+			Tracks:    []CDTextTrack{{TrackNum: 1, Title: "Song"}},
+		},
+		{
+			Language:  CDTextLanguageJapanese,
+			Title:     "アルバム",
+			Performer: "アーティスト",
+			Tracks:    []CDTextTrack{{TrackNum: 1, Title: "歌"}},
+		},
+	}
+
+	tags := CDTextTags(blocks, 1, CDTextLanguageJapanese)
+	assert.Equal(t, map[string]string{
+		"ALBUM":          "アルバム",
+		"ALBUMARTIST":    "アーティスト",
+		"TITLE":          "歌",
+		"ALBUM:en":       "Album",
+		"ALBUMARTIST:en": "Artist",
+		"TITLE:en":       "Song",
+	}, tags)
+
+	main, ok := SelectCDText(blocks, CDTextLanguageGerman)
+	assert.False(t, ok)
+	assert.Equal(t, CDTextLanguageEnglish, main.Language)
+	assert.Equal(t, "x-7f", CDTextLanguageCode(0x7f).Code())
+}
+
+// cdTextPacks encodes a stream of strings into CD-Text packs.
+func cdTextPacks(typ, block byte, strs ...string) []byte {
+	data := []byte{}
+	for _, s := range strs {
+		data = append(data, s...)
+		data = append(data, 0)
+	}
+	for len(data)%12 != 0 {
+		data = append(data, 0)
+	}
+	var packs []byte
+	for i := 0; i < len(data); i += 12 {
+		pack := []byte{typ, 0, byte(i / 12), block << 4}
+		pack = append(pack, data[i:i+12]...)
+		crc := ^crc16(pack)
+		packs = append(packs, pack...)
+		packs = append(packs, byte(crc>>8), byte(crc))
+	}
+	return packs
+}
+
+func TestParseCDText(t *testing.T) {
+	size := make([]byte, 36)
+	size[1], size[2] = 1, 2
+	size[28], size[29] = byte(CDTextLanguageEnglish), byte(CDTextLanguageGerman)
+
+	data := cdTextPacks(cdTextPackTitle, 0, "Album", "Song One", "Song Two")
+	data = append(data, cdTextPacks(cdTextPackPerformer, 0, "Artist", "Singer", "\t")...)
+	data = append(data, cdTextPacks(cdTextPackSizeInfo, 0, string(size[:35]))...)
+	data = append(data, cdTextPacks(cdTextPackTitle, 1, "\xC4bum")...)
+	bad := cdTextPacks(cdTextPackTitle, 0, "Garbage")
+	bad[17]++ // packs with a bad CRC are dropped
+	data = append(data, bad...)
+
+	blocks := parseCDText(data)
+	assert.Len(t, blocks, 2)
+
+	assert.Equal(t, CDText{
+		Language:  CDTextLanguageEnglish,
+		Title:     "Album",
+		Performer: "Artist",
+		Tracks: []CDTextTrack{
+			{TrackNum: 1, Title: "Song One", Performer: "Singer"},
+			{TrackNum: 2, Title: "Song Two", Performer: "Singer"},
+		},
+	}, blocks[0])
+	assert.Equal(t, CDTextLanguageGerman, blocks[1].Language)
+	assert.Equal(t, "Äbum", blocks[1].Title)
+}
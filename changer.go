@@ -0,0 +1,85 @@
+package audiocd
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"time"
+)
+
+// changerTimeout is how long a changer may take to swap discs.
+const changerTimeout = time.Minute
+
+// Slots returns the number of discs the drive holds, which is more
+// than 1 for CD changers.
+//
+// This requires a drive which supports MMC commands.
+func (cd *AudioCD) Slots() (int, error) {
+	_, slots, err := cd.mechanismStatus()
+	return slots, err
+}
+
+// Slot returns the changer slot the loaded disc is from, starting at 0.
+//
+// This requires a drive which supports MMC commands.
+func (cd *AudioCD) Slot() (int, error) {
+	slot, _, err := cd.mechanismStatus()
+	return slot, err
+}
+
+// SelectSlot loads the disc in the changer slot, starting at 0, and
+// opens it like [*AudioCD.LoadMedia], so a changer can be ripped a
+// disc at a time. The read position and the state kept for the
+// previous disc, like checkpoints, are reset.
+//
+// This requires a drive which supports MMC commands.
+func (cd *AudioCD) SelectSlot(ctx context.Context, slot int) error {
+	slots, err := cd.Slots()
+	if err != nil {
+		return err
+	}
+	if slot < 0 || slot >= slots {
+		return fmt.Errorf("audiocd: slot %d is outside of the %d slots", slot, slots)
+	}
+	cdb := []byte{mmcLoadUnload, 0, 0, 0, 0x03, 0, 0, 0, byte(slot), 0, 0, 0} // load and start
+	err = mmcCommand(cd, cdb, DirNone, nil, changerTimeout)
+	if err != nil {
+		return err
+	}
+
+	// the disc changed, so start again
+	if cd.Device == "" {
+		cd.Device = devicePath(cd.drive)
+	}
+	err = cd.Close()
+	if err != nil {
+		return err
+	}
+	return cd.LoadMedia(ctx)
+}
+
+// mechanismStatus issues MECHANISM STATUS.
+func (cd *AudioCD) mechanismStatus() (slot, slots int, err error) {
+	if !cd.IsOpen() {
+		return 0, 0, os.ErrClosed
+	}
+	header := make([]byte, 8)
+	cdb := make([]byte, 12)
+	cdb[0] = mmcMechanismStatus
+	binary.BigEndian.PutUint16(cdb[8:], uint16(len(header)))
+	err = mmcCommand(cd, cdb, DirIn, header, mmcTimeout)
+	if err != nil {
+		return 0, 0, err
+	}
+	slot, slots = parseMechanismStatus(header)
+	return slot, slots, nil
+}
+
+// parseMechanismStatus decodes the header of the MECHANISM STATUS
+// response. Drives which aren't changers may report no slots.
+func parseMechanismStatus(header []byte) (slot, slots int) {
+	slot = int(header[1]&0x07)<<5 | int(header[0]&0x1F)
+	slots = max(int(header[5]&0x1F), 1)
+	return slot, slots
+}
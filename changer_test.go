@@ -0,0 +1,19 @@
+package audiocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMechanismStatus(t *testing.T) {
+	// a 5 disc changer on slot 3, with the door closed
+	slot, slots := parseMechanismStatus([]byte{0x23, 0x00, 0, 0, 0, 0x05, 0x00, 0x14})
+	assert.Equal(t, 3, slot)
+	assert.Equal(t, 5, slots)
+
+	// an ordinary drive
+	slot, slots = parseMechanismStatus([]byte{0x00, 0x10, 0, 0, 0, 0x00, 0x00, 0x00})
+	assert.Equal(t, 0, slot)
+	assert.Equal(t, 1, slots)
+}
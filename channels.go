@@ -0,0 +1,66 @@
+package audiocd
+
+import (
+	"bytes"
+	"io"
+)
+
+// SplitChannels splits a stream of interleaved stereo PCM data, such
+// as an [AudioCD] or [TrackReader], into two mono streams of 16-bit
+// samples for the left and right channels.
+//
+// Both readers share the reads from r: data read from r for one
+// channel is held for the other until it's read. Data is buffered
+// without limit, so the two readers should be consumed at a similar
+// pace. They aren't safe for concurrent use.
+func SplitChannels(r io.Reader) (left, right io.Reader) {
+	s := &channelSplitter{r: r}
+	return &channelReader{s: s, ch: 0}, &channelReader{s: s, ch: 1}
+}
+
+// splitChunk is the amount of stereo data read from the source at once.
+const splitChunk = 16 * BytesPerSector
+
+type channelSplitter struct {
+	r       io.Reader
+	chunk   []byte
+	partial []byte          // bytes of an incomplete frame from the last read
+	bufs    [2]bytes.Buffer // deinterleaved data not yet read, per channel
+	err     error
+}
+
+// fill reads from the source and deinterleaves the data.
+func (s *channelSplitter) fill() {
+	if s.chunk == nil {
+		s.chunk = make([]byte, splitChunk)
+	}
+	n := copy(s.chunk, s.partial)
+	nn, err := s.r.Read(s.chunk[n:])
+	n += nn
+	s.err = err
+
+	const frameSize = Channels * BytesPerSample
+	frames := n / frameSize
+	for i := range frames {
+		frame := s.chunk[i*frameSize:]
+		s.bufs[0].Write(frame[:BytesPerSample])
+		s.bufs[1].Write(frame[BytesPerSample:frameSize])
+	}
+	s.partial = append(s.partial[:0], s.chunk[frames*frameSize:n]...)
+}
+
+type channelReader struct {
+	s  *channelSplitter
+	ch int
+}
+
+func (cr *channelReader) Read(p []byte) (int, error) {
+	buf := &cr.s.bufs[cr.ch]
+	for buf.Len() == 0 {
+		if cr.s.err != nil {
+			return 0, cr.s.err
+		}
+		cr.s.fill()
+	}
+	return buf.Read(p)
+}
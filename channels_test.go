@@ -0,0 +1,33 @@
+package audiocd
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitChannels(t *testing.T) {
+	stereo := []byte{
+		0x01, 0x02, 0xA1, 0xA2,
+		0x03, 0x04, 0xA3, 0xA4,
+		0x05, 0x06, 0xA5, 0xA6,
+	}
+	left, right := SplitChannels(iotest.OneByteReader(bytes.NewReader(stereo)))
+
+	// read the channels in turn
+	l := make([]byte, 2)
+	_, err := io.ReadFull(left, l)
+	failIfErr(t, err)
+	assert.Equal(t, []byte{0x01, 0x02}, l)
+
+	r, err := io.ReadAll(right)
+	failIfErr(t, err)
+	assert.Equal(t, []byte{0xA1, 0xA2, 0xA3, 0xA4, 0xA5, 0xA6}, r)
+
+	l, err = io.ReadAll(left)
+	failIfErr(t, err)
+	assert.Equal(t, []byte{0x03, 0x04, 0x05, 0x06}, l)
+}
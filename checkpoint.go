@@ -0,0 +1,180 @@
+package audiocd
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sync"
+)
+
+// ErrCheckpointMismatch is returned by [*AudioCD.SetCheckpoint] when
+// the checkpoint file is for a different disc.
+var ErrCheckpointMismatch = errors.New("audiocd: checkpoint is for a different disc")
+
+// checkpointMagic starts a checkpoint file. It's followed by the disc's
+// fingerprint as a line of JSON, then a record for each sector.
+const checkpointMagic = "audiocd secure checkpoint 1\n"
+
+// checkpointRecordSize is the size of a sector record: the sector and
+// the CRC-32 of its audio, both little-endian.
+const checkpointRecordSize = 8
+
+// checkpointFlushSectors is the number of confirmed sectors buffered
+// before they're written to the file.
+const checkpointFlushSectors = SectorsPerSecond
+
+// SetCheckpoint keeps a record of the sectors confirmed in
+// [ReadModeSecure] in the file at path, so that if a long rip is
+// interrupted, the confirmed sectors only need to be read once more
+// to match the record when it's resumed, instead of being verified
+// again. If the file exists, its record is loaded and added to.
+// The file is written as the rip goes and when cd is closed.
+//
+// Returns [ErrCheckpointMismatch] if the file is for another disc.
+// An empty path stops keeping a record.
+func (cd *AudioCD) SetCheckpoint(path string) error {
+	if !cd.IsOpen() {
+		return os.ErrClosed
+	}
+	err := cd.checkpoint.close()
+	cd.checkpoint = nil
+	if err != nil || path == "" {
+		return err
+	}
+	cp, err := openCheckpoint(path, NewDiscFingerprint(cd.TOC(), ""))
+	if err != nil {
+		return err
+	}
+	cd.checkpoint = cp
+	return nil
+}
+
+// checkpoint is a file of the sectors confirmed in secure mode.
+type checkpoint struct {
+	mu      sync.Mutex // a stalled read may still be confirming sectors
+	f       *os.File
+	w       *bufio.Writer
+	sums    map[int]uint32
+	pending int
+}
+
+func openCheckpoint(path string, fp DiscFingerprint) (*checkpoint, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	cp := &checkpoint{f: f, sums: make(map[int]uint32)}
+	err = cp.load(fp)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	cp.w = bufio.NewWriter(f)
+	return cp, nil
+}
+
+// load reads the records of the file, or writes the header of a new
+// one, and leaves the file ready for more records.
+func (cp *checkpoint) load(fp DiscFingerprint) error {
+	header, err := json.Marshal(fp)
+	if err != nil {
+		return err
+	}
+	r := bufio.NewReader(cp.f)
+	magic, err := r.ReadString('\n')
+	if err == io.EOF && magic == "" {
+		_, err = fmt.Fprintf(cp.f, "%s%s\n", checkpointMagic, header)
+		return err
+	}
+	if magic != checkpointMagic {
+		return fmt.Errorf("audiocd: %s isn't a checkpoint file", cp.f.Name())
+	}
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		return fmt.Errorf("audiocd: %s isn't a checkpoint file", cp.f.Name())
+	}
+	var other DiscFingerprint
+	if json.Unmarshal(line, &other) != nil || !fp.Equal(other) {
+		return ErrCheckpointMismatch
+	}
+
+	end := int64(len(magic) + len(line))
+	var rec [checkpointRecordSize]byte
+	for {
+		_, err := io.ReadFull(r, rec[:])
+		if err != nil {
+			// drop a record cut short by a crash
+			break
+		}
+		cp.sums[int(binary.LittleEndian.Uint32(rec[:]))] = binary.LittleEndian.Uint32(rec[4:])
+		end += checkpointRecordSize
+	}
+	err = cp.f.Truncate(end)
+	if err != nil {
+		return err
+	}
+	_, err = cp.f.Seek(end, io.SeekStart)
+	return err
+}
+
+// sum returns the CRC-32 of a confirmed sector.
+func (cp *checkpoint) sum(sector int) (uint32, bool) {
+	if cp == nil {
+		return 0, false
+	}
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	sum, ok := cp.sums[sector]
+	return sum, ok
+}
+
+// confirm records a confirmed sector, with its audio in the disc's
+// byte order.
+func (cp *checkpoint) confirm(sector int, p []byte) {
+	if cp == nil {
+		return
+	}
+	sum := crc32.ChecksumIEEE(p)
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	if cp.w == nil {
+		// closed
+		return
+	}
+	if prev, ok := cp.sums[sector]; ok && prev == sum {
+		return
+	}
+	cp.sums[sector] = sum
+	var rec [checkpointRecordSize]byte
+	binary.LittleEndian.PutUint32(rec[:], uint32(sector))
+	binary.LittleEndian.PutUint32(rec[4:], sum)
+	cp.w.Write(rec[:])
+	cp.pending++
+	if cp.pending >= checkpointFlushSectors {
+		// errors are caught by the next flush
+		cp.w.Flush()
+		cp.pending = 0
+	}
+}
+
+func (cp *checkpoint) close() error {
+	if cp == nil {
+		return nil
+	}
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	if cp.w == nil {
+		return nil
+	}
+	err := cp.w.Flush()
+	if cerr := cp.f.Close(); err == nil {
+		err = cerr
+	}
+	cp.w = nil
+	return err
+}
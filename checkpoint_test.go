@@ -0,0 +1,52 @@
+package audiocd
+
+import (
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rip.checkpoint")
+	fp := NewDiscFingerprint([]TrackPosition{{TrackNum: 1, StartSector: 0, LengthSectors: 6290}}, "")
+
+	cp, err := openCheckpoint(path, fp)
+	failIfErr(t, err)
+	sector := make([]byte, BytesPerSector)
+	sector[0] = 1
+	cp.confirm(10, sector)
+	cp.confirm(11, make([]byte, BytesPerSector))
+	failIfErr(t, cp.close())
+
+	// a record cut short
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	failIfErr(t, err)
+	f.Write([]byte{1, 2, 3})
+	f.Close()
+
+	cp, err = openCheckpoint(path, fp)
+	failIfErr(t, err)
+	sum, ok := cp.sum(10)
+	assert.True(t, ok)
+	assert.Equal(t, crc32.ChecksumIEEE(sector), sum)
+	_, ok = cp.sum(12)
+	assert.False(t, ok)
+	cp.confirm(12, sector)
+	failIfErr(t, cp.close())
+
+	cp, err = openCheckpoint(path, fp)
+	failIfErr(t, err)
+	assert.Len(t, cp.sums, 3)
+	failIfErr(t, cp.close())
+
+	other := NewDiscFingerprint([]TrackPosition{{TrackNum: 1, StartSector: 0, LengthSectors: 6291}}, "")
+	_, err = openCheckpoint(path, other)
+	assert.ErrorIs(t, err, ErrCheckpointMismatch)
+
+	var nilCheckpoint *checkpoint
+	_, ok = nilCheckpoint.sum(10)
+	assert.False(t, ok)
+}
@@ -0,0 +1,62 @@
+package audiocd
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+	"hash"
+	"hash/crc32"
+)
+
+// trackChecksums computes the checksums of a track as it streams,
+// from the audio in little-endian order as stored on the disc.
+type trackChecksums struct {
+	crc       hash.Hash32
+	crcNoNull hash.Hash32
+	md5       hash.Hash
+	sha1      hash.Hash
+	ar        *accurateRipSum // if set, the AccurateRip checksums are computed too
+	half      []byte          // the first byte of a sample split across writes
+	buf       []byte
+}
+
+func newTrackChecksums() *trackChecksums {
+	return &trackChecksums{
+		crc:       crc32.NewIEEE(),
+		crcNoNull: crc32.NewIEEE(),
+		md5:       md5.New(),
+		sha1:      sha1.New(),
+	}
+}
+
+// Write adds PCM data to the checksums.
+func (tc *trackChecksums) Write(p []byte) (int, error) {
+	tc.crc.Write(p)
+	tc.md5.Write(p)
+	tc.sha1.Write(p)
+	if tc.ar != nil {
+		tc.ar.Write(p)
+	}
+	tc.buf = append(append(tc.buf[:0], tc.half...), p...)
+	whole := len(tc.buf) &^ 1
+	data := tc.buf[:whole]
+	// like EAC, the CRC without null samples skips samples which are 0
+	for i := 0; i < len(data); i += BytesPerSample {
+		if data[i] != 0 || data[i+1] != 0 {
+			tc.crcNoNull.Write(data[i : i+BytesPerSample])
+		}
+	}
+	tc.half = append(tc.half[:0], tc.buf[whole:]...)
+	return len(p), nil
+}
+
+// report sets the checksums of the track report.
+func (tc *trackChecksums) report(tp *TrackReport) {
+	tp.CRC32 = tc.crc.Sum32()
+	tp.CRC32NoNull = tc.crcNoNull.Sum32()
+	tp.MD5 = hex.EncodeToString(tc.md5.Sum(nil))
+	tp.SHA1 = hex.EncodeToString(tc.sha1.Sum(nil))
+	if tc.ar != nil {
+		tp.AccurateRipV1, tp.AccurateRipV2 = tc.ar.v1, tc.ar.v2
+	}
+}
@@ -0,0 +1,61 @@
+package audiocd
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"hash/crc32"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackChecksums(t *testing.T) {
+	le := []byte{1, 2, 0, 0, 3, 4, 0, 5}
+	md5sum := md5.Sum(le)
+
+	sums := newTrackChecksums()
+	// split across a sample
+	sums.Write(le[:3])
+	sums.Write(le[3:])
+	var tp TrackReport
+	sums.report(&tp)
+
+	assert.Equal(t, crc32.ChecksumIEEE(le), tp.CRC32)
+	assert.Equal(t, crc32.ChecksumIEEE([]byte{1, 2, 3, 4, 0, 5}), tp.CRC32NoNull)
+	assert.Equal(t, hex.EncodeToString(md5sum[:]), tp.MD5)
+	assert.Len(t, tp.SHA1, 40)
+}
+
+func TestChecksumTap(t *testing.T) {
+	cd := AudioCD{ByteOrder: binary.BigEndian}
+	cd.SetSampleProcessor(func(s []int16) {
+		for i := range s {
+			s[i] = 0
+		}
+	})
+	var tap bytes.Buffer
+	failIfErr(t, cd.setTap(&tap))
+
+	disc := make([]byte, BytesPerSector)
+	for i := range disc {
+		disc[i] = byte(i)
+	}
+	p := make([]byte, BytesPerSector)
+	for i := 0; i < len(p); i += BytesPerSample {
+		binary.NativeEndian.PutUint16(p[i:], binary.LittleEndian.Uint16(disc[i:]))
+	}
+	cd.processSectors(p)
+	cd.buf.Write(p)
+
+	// the tap follows what's read, with the audio as on the disc
+	out := make([]byte, 1000)
+	n, err := cd.Read(out)
+	failIfErr(t, err)
+	assert.Equal(t, disc[:n], tap.Bytes())
+	_, err = cd.Read(out)
+	failIfErr(t, err)
+	assert.Equal(t, disc[:2*n], tap.Bytes())
+	assert.Equal(t, make([]byte, n), out)
+}
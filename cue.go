@@ -0,0 +1,292 @@
+package audiocd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// CueSheet describes the layout of a disc image in the
+// cue sheet format understood by most burning and ripping software.
+//
+// A CueSheet can be built from the table of contents with [NewCueSheet]
+// and then extended with metadata before being written out with
+// [*CueSheet.WriteTo].
+type CueSheet struct {
+	Catalog   string       // media catalog number (UPC/EAN), emitted as CATALOG
+	Title     string       // album title
+	Performer string       // album artist
+	Comments  []CueComment // emitted as REM lines, e.g. disc ids and rip settings
+	Tracks    []CueTrack
+	LeadOut   int // the disc sector after the end of the last track
+}
+
+// CueComment is a REM line in a cue sheet, e.g.
+//
+//	REM DISCID 8C0A3B0B
+//	REM COMMENT "ExactAudioCopy v1.6"
+type CueComment struct {
+	Key   string
+	Value string
+}
+
+// CueTrack is a single TRACK entry in a cue sheet.
+type CueTrack struct {
+	TrackNum   int
+	Flags      byte   // control flags, same encoding as [TrackPosition].Flags
+	ISRC       string // international standard recording code
+	Title      string
+	Performer  string
+	Songwriter string
+
+	File      string // the audio file the track is stored in
+	FileStart int    // the disc sector the start of File corresponds to
+
+	Indexes []CueIndex
+}
+
+// CueIndex is an index point within a track. Index 0 marks the start
+// of the pregap, index 1 the start of the track proper.
+type CueIndex struct {
+	Number int
+	Sector int // the disc sector of the index point
+}
+
+// NewCueSheet creates a cue sheet for a single image file
+// containing the audio tracks from toc, starting from sector 0.
+// Tracks with PregapSectors get an INDEX 00 at the start of the pregap.
+func NewCueSheet(toc []TrackPosition, file string) *CueSheet {
+	cs := &CueSheet{}
+	for _, t := range toc {
+		if !t.IsAudio() {
+			continue
+		}
+		ct := CueTrack{
+			TrackNum: t.TrackNum,
+			Flags:    t.Flags,
+			File:     file,
+		}
+		if t.PregapSectors > 0 {
+			ct.Indexes = append(ct.Indexes, CueIndex{Number: 0, Sector: t.StartSector - t.PregapSectors})
+		} else if t.TrackNum == 1 && t.StartSector > 0 {
+			ct.Indexes = append(ct.Indexes, CueIndex{Number: 0, Sector: 0})
+		}
+		ct.Indexes = append(ct.Indexes, CueIndex{Number: 1, Sector: t.StartSector})
+		cs.Tracks = append(cs.Tracks, ct)
+		cs.LeadOut = t.StartSector + t.LengthSectors
+	}
+	return cs
+}
+
+// CueSheetOptions configures [*AudioCD.CueSheet].
+type CueSheetOptions struct {
+	File        string                 // the disc image file, for a single-file cue sheet
+	TrackFiles  func(track int) string // if set, the file of each track, for one file per track starting at index 1
+	ScanIndexes bool                   // scan the subchannel for pregaps and index points, which takes a while
+}
+
+// CueSheet builds a cue sheet for the disc from the table of contents,
+// adding the MCN, ISRCs and CD-Text titles if the drive can read them.
+// With ScanIndexes, the pregaps and index points of each track are read
+// from the subchannel, see [*AudioCD.TrackIndexes].
+func (cd *AudioCD) CueSheet(opts CueSheetOptions) (*CueSheet, error) {
+	if !cd.IsOpen() {
+		return nil, os.ErrClosed
+	}
+	toc := cd.TOC()
+	cs := NewCueSheet(toc, opts.File)
+	cs.AddComment("DISCID", fmt.Sprintf("%08X", CDDBDiscID(toc)))
+
+	if mcn, err := cd.MCN(); err == nil {
+		cs.Catalog = mcn
+	}
+	cdtext, err := cd.CDText()
+	hasCDText := err == nil
+	if hasCDText {
+		cs.Title = cdtext.Title
+		cs.Performer = cdtext.Performer
+	}
+
+	for i := range cs.Tracks {
+		ct := &cs.Tracks[i]
+		if opts.ScanIndexes {
+			indexes, err := cd.TrackIndexes(ct.TrackNum)
+			if err != nil {
+				return nil, err
+			}
+			ct.Indexes = indexes
+		}
+		if opts.TrackFiles != nil {
+			ct.File = opts.TrackFiles(ct.TrackNum)
+			ct.FileStart = trackFileStart(*ct)
+		}
+		if isrc, err := cd.TrackISRC(ct.TrackNum); err == nil {
+			ct.ISRC = isrc
+		}
+		if hasCDText {
+			t := cdtext.Track(ct.TrackNum)
+			ct.Title = t.Title
+			ct.Performer = t.Performer
+			ct.Songwriter = t.Songwriter
+		}
+	}
+	return cs, nil
+}
+
+// trackFileStart returns the sector a per-track file starts at:
+// index 1, except for the first track, whose file includes any
+// audio hidden in its pregap.
+func trackFileStart(ct CueTrack) int {
+	for _, idx := range ct.Indexes {
+		if idx.Number == 1 || ct.TrackNum == 1 {
+			return idx.Sector
+		}
+	}
+	return 0
+}
+
+// WriteCueSheet writes a cue sheet for the disc to w.
+// See [*AudioCD.CueSheet] for the options.
+func (cd *AudioCD) WriteCueSheet(w io.Writer, opts CueSheetOptions) error {
+	cs, err := cd.CueSheet(opts)
+	if err != nil {
+		return err
+	}
+	_, err = cs.WriteTo(w)
+	return err
+}
+
+// AddComment appends a REM line to the cue sheet.
+func (cs *CueSheet) AddComment(key, value string) {
+	cs.Comments = append(cs.Comments, CueComment{Key: key, Value: value})
+}
+
+// CueFlags returns the FLAGS values for the track, e.g. "DCP PRE".
+// It returns an empty string if no flags are set.
+func (ct CueTrack) CueFlags() string {
+	var flags []string
+	if ct.Flags&0x02 != 0 {
+		flags = append(flags, "DCP")
+	}
+	if ct.Flags&0x08 != 0 {
+		flags = append(flags, "4CH")
+	}
+	if ct.Flags&0x01 != 0 {
+		flags = append(flags, "PRE")
+	}
+	return strings.Join(flags, " ")
+}
+
+// WriteTo writes the cue sheet in text form to w.
+//
+// Pregap index points which lie before the start of the track's file
+// are written relative to the previous file, as is customary for
+// rips with one file per track.
+func (cs *CueSheet) WriteTo(w io.Writer) (int64, error) {
+	cw := &cueWriter{w: bufio.NewWriter(w)}
+
+	for _, c := range cs.Comments {
+		cw.printf("REM %s %s\n", c.Key, cueValue(c.Value))
+	}
+	if cs.Catalog != "" {
+		cw.printf("CATALOG %s\n", cs.Catalog)
+	}
+	if cs.Performer != "" {
+		cw.printf("PERFORMER %s\n", cueString(cs.Performer))
+	}
+	if cs.Title != "" {
+		cw.printf("TITLE %s\n", cueString(cs.Title))
+	}
+
+	file := ""
+	fileStart := 0
+	for _, t := range cs.Tracks {
+		if t.File == file {
+			cw.track(t)
+			cw.indexes(t.Indexes, fileStart)
+			continue
+		}
+
+		// index points before the start of the new file
+		// belong to the end of the previous one
+		var pre, post []CueIndex
+		for _, idx := range t.Indexes {
+			if file != "" && idx.Sector < t.FileStart {
+				pre = append(pre, idx)
+			} else {
+				post = append(post, idx)
+			}
+		}
+		if len(pre) > 0 {
+			cw.track(t)
+			cw.indexes(pre, fileStart)
+		}
+		file, fileStart = t.File, t.FileStart
+		cw.printf("FILE %s WAVE\n", cueString(file))
+		if len(pre) == 0 {
+			cw.track(t)
+		}
+		cw.indexes(post, fileStart)
+	}
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+type cueWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *cueWriter) printf(format string, args ...any) {
+	if cw.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(cw.w, format, args...)
+	cw.n += int64(n)
+	cw.err = err
+}
+
+func (cw *cueWriter) track(t CueTrack) {
+	cw.printf("  TRACK %02d AUDIO\n", t.TrackNum)
+	if t.Title != "" {
+		cw.printf("    TITLE %s\n", cueString(t.Title))
+	}
+	if t.Performer != "" {
+		cw.printf("    PERFORMER %s\n", cueString(t.Performer))
+	}
+	if t.Songwriter != "" {
+		cw.printf("    SONGWRITER %s\n", cueString(t.Songwriter))
+	}
+	if flags := t.CueFlags(); flags != "" {
+		cw.printf("    FLAGS %s\n", flags)
+	}
+	if t.ISRC != "" {
+		cw.printf("    ISRC %s\n", t.ISRC)
+	}
+}
+
+func (cw *cueWriter) indexes(indexes []CueIndex, fileStart int) {
+	for _, idx := range indexes {
+		cw.printf("    INDEX %02d %s\n", idx.Number, SectorsToMSF(idx.Sector-fileStart))
+	}
+}
+
+// cueString quotes a string value. Cue sheets have no
+// escape mechanism, so double quotes are replaced.
+func cueString(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "'") + `"`
+}
+
+// cueValue quotes a REM value only if it contains spaces.
+func cueValue(s string) string {
+	if strings.ContainsAny(s, " \t\"") || s == "" {
+		return cueString(s)
+	}
+	return s
+}
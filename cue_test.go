@@ -0,0 +1,101 @@
+package audiocd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCueSheetSingleFile(t *testing.T) {
+	toc := []TrackPosition{
+		{TrackNum: 1, Flags: 0x02, StartSector: 0, LengthSectors: 6290},
+		{TrackNum: 2, Flags: 0x01, StartSector: 6290, LengthSectors: 17021},
+	}
+	cs := NewCueSheet(toc, "Range.wav")
+	cs.Catalog = "0724384497729"
+	cs.Title = `Album "Title"`
+	cs.Performer = "Artist"
+	cs.AddComment("DISCID", "1A034C02")
+	cs.AddComment("COMMENT", "audiocd v1")
+	cs.Tracks[1].ISRC = "USRC17607839"
+
+	buf := strings.Builder{}
+	_, err := cs.WriteTo(&buf)
+	failIfErr(t, err)
+
+	assert.Equal(t, `REM DISCID 1A034C02
+REM COMMENT "audiocd v1"
+CATALOG 0724384497729
+PERFORMER "Artist"
+TITLE "Album 'Title'"
+FILE "Range.wav" WAVE
+  TRACK 01 AUDIO
+    FLAGS DCP
+    INDEX 01 00:00:00
+  TRACK 02 AUDIO
+    FLAGS PRE
+    ISRC USRC17607839
+    INDEX 01 01:23:65
+`, buf.String())
+}
+
+func TestCueSheetPerTrackFiles(t *testing.T) {
+	cs := CueSheet{Tracks: []CueTrack{
+		{TrackNum: 1, File: "01.wav", FileStart: 0, Indexes: []CueIndex{{1, 0}}},
+		{TrackNum: 2, File: "02.wav", FileStart: 6290, Indexes: []CueIndex{{0, 6140}, {1, 6290}}},
+	}}
+
+	buf := strings.Builder{}
+	_, err := cs.WriteTo(&buf)
+	failIfErr(t, err)
+
+	assert.Equal(t, `FILE "01.wav" WAVE
+  TRACK 01 AUDIO
+    INDEX 01 00:00:00
+  TRACK 02 AUDIO
+    INDEX 00 01:21:65
+FILE "02.wav" WAVE
+    INDEX 01 00:00:00
+`, buf.String())
+}
+
+func TestFLACCueSheetBlock(t *testing.T) {
+	toc := []TrackPosition{
+		{TrackNum: 1, StartSector: 0, LengthSectors: 100},
+		{TrackNum: 2, Flags: 0x01, StartSector: 100, LengthSectors: 50},
+	}
+	cs := NewCueSheet(toc, "disc.flac")
+	cs.Tracks[1].Indexes = []CueIndex{{0, 90}, {1, 100}}
+	cs.Tracks[1].ISRC = "USRC17607839"
+
+	block, err := cs.FLACMetadataBlock(true)
+	failIfErr(t, err)
+
+	// header + catalog + lead-in + flags/reserved + ntracks + 2 tracks + 3 indexes + lead-out
+	bodylen := 128 + 8 + 259 + 1 + 3*36 + 3*12
+	assert.Equal(t, 4+bodylen, len(block))
+	assert.Equal(t, []byte{0x85, 0, byte(bodylen >> 8), byte(bodylen)}, block[:4])
+
+	body := block[4:]
+	assert.Equal(t, byte(0x80), body[136], "is CD")
+	assert.Equal(t, byte(3), body[395], "track count includes lead-out")
+
+	track2 := body[396+36+12:]
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0xCE, 0xB8}, track2[:8], "offset of index 0 (90*588)")
+	assert.Equal(t, byte(2), track2[8])
+	assert.Equal(t, "USRC17607839", string(track2[9:21]))
+	assert.Equal(t, byte(0x40), track2[21], "pre-emphasis")
+	assert.Equal(t, byte(2), track2[35])
+
+	leadout := body[len(body)-36:]
+	assert.Equal(t, byte(170), leadout[8])
+}
+
+func TestTrackFileStart(t *testing.T) {
+	htoa := CueTrack{TrackNum: 1, Indexes: []CueIndex{{0, 0}, {1, 33}}}
+	assert.Equal(t, 0, trackFileStart(htoa))
+
+	pregap := CueTrack{TrackNum: 2, Indexes: []CueIndex{{0, 6140}, {1, 6290}, {2, 7000}}}
+	assert.Equal(t, 6290, trackFileStart(pregap))
+}
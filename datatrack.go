@@ -0,0 +1,87 @@
+package audiocd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DataTrackPolicy selects what reads do when they reach a data track
+// on a mixed-mode disc.
+type DataTrackPolicy int
+
+const (
+	// DataTrackStop stops reading at the start of the data track
+	// with a [*DataTrackError]. This is the default.
+	DataTrackStop DataTrackPolicy = 0
+	// DataTrackSkip skips ahead to the next audio track, or to the
+	// end of the disc if there isn't one. This applies to
+	// [*AudioCD.Read] and [*AudioCD.WriteTo]; other reads stop.
+	DataTrackSkip DataTrackPolicy = 1
+	// DataTrackZeroFill reads the data track as silence.
+	DataTrackZeroFill DataTrackPolicy = 2
+)
+
+func (p DataTrackPolicy) String() string {
+	switch p {
+	case DataTrackStop:
+		return "Stop"
+	case DataTrackSkip:
+		return "Skip"
+	case DataTrackZeroFill:
+		return "ZeroFill"
+	default:
+		return fmt.Sprintf("DataTrackPolicy(%d)", int(p))
+	}
+}
+
+// DataTrackError is returned when a read reaches a data track.
+// Sector is where the read stopped.
+type DataTrackError struct {
+	TrackNum int
+	Sector   int
+}
+
+func (e *DataTrackError) Error() string {
+	return fmt.Sprintf("audiocd: sector %d is in data track %d", e.Sector, e.TrackNum)
+}
+
+// dataTrackAt returns the data track containing the sector, if any.
+func (cd *AudioCD) dataTrackAt(sector int) (TrackPosition, bool) {
+	for _, t := range cd.tracks() {
+		if t.ContainsSector(sector) {
+			return t, !t.IsAudio()
+		}
+	}
+	return TrackPosition{}, false
+}
+
+// skipsDataTrack reports whether a read which failed with err
+// should continue after the data track.
+func (cd *AudioCD) skipsDataTrack(err error) bool {
+	var dte *DataTrackError
+	return cd.DataTracks == DataTrackSkip && errors.As(err, &dte)
+}
+
+// skipDataTrack moves the read position from the start of a data
+// track to the next audio track. The buffer must be empty. It returns
+// the number of bytes skipped, and [io.EOF] if there are no more
+// audio tracks.
+func (cd *AudioCD) skipDataTrack() (int64, error) {
+	sector := int(cd.bufferedOffset / BytesPerSector)
+	next := cd.LengthSectors()
+	for _, t := range cd.tracks() {
+		if t.IsAudio() && t.StartSector > sector {
+			next = t.StartSector
+			break
+		}
+	}
+	skipped := int64(next-sector) * BytesPerSector
+	cd.notifyTrackChanges(skipped)
+	cd.bufferedOffset += skipped
+	cd.trueOffset += skipped
+	if next >= cd.LengthSectors() {
+		return skipped, io.EOF
+	}
+	return skipped, cd.seek(next)
+}
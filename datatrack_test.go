@@ -0,0 +1,24 @@
+package audiocd
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataTrackError(t *testing.T) {
+	err := fmt.Errorf("rip: %w", &DataTrackError{TrackNum: 12, Sector: 250000})
+	assert.Equal(t, "rip: audiocd: sector 250000 is in data track 12", err.Error())
+
+	cd := AudioCD{}
+	assert.False(t, cd.skipsDataTrack(err))
+	cd.DataTracks = DataTrackSkip
+	assert.True(t, cd.skipsDataTrack(err))
+	assert.False(t, cd.skipsDataTrack(io.EOF))
+	assert.False(t, cd.skipsDataTrack(nil))
+
+	assert.Equal(t, "ZeroFill", DataTrackZeroFill.String())
+	assert.Equal(t, "DataTrackPolicy(7)", DataTrackPolicy(7).String())
+}
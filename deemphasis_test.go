@@ -0,0 +1,46 @@
+package audiocd
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeemphasisFilter(t *testing.T) {
+	samples := func(f *deemphasisFilter, v func(i int) int16) []int16 {
+		p := make([]byte, BytesPerSector)
+		for i := range SamplesPerSector * Channels {
+			binary.NativeEndian.PutUint16(p[i*BytesPerSample:], uint16(v(i/Channels)))
+		}
+		f.apply(p)
+		s := make([]int16, SamplesPerSector*Channels)
+		for i := range s {
+			s[i] = int16(binary.NativeEndian.Uint16(p[i*BytesPerSample:]))
+		}
+		return s
+	}
+
+	// low frequencies pass unchanged
+	f := newDeemphasisFilter(SampleRate)
+	s := samples(f, func(int) int16 { return 10000 })
+	assert.InDelta(t, 10000, s[len(s)-1], 1)
+	assert.InDelta(t, 10000, s[len(s)-2], 1)
+
+	// high frequencies are cut to 15/50 of their level
+	f.reset()
+	s = samples(f, func(i int) int16 {
+		if i%2 == 0 {
+			return 10000
+		}
+		return -10000
+	})
+	assert.InDelta(t, -3000, s[len(s)-2], 10)
+	assert.InDelta(t, 3000, s[len(s)-4], 10)
+}
+
+func TestClampSample(t *testing.T) {
+	assert.Equal(t, int16(32767), clampSample(40000))
+	assert.Equal(t, int16(-32768), clampSample(-40000))
+	assert.Equal(t, int16(-2), clampSample(-1.6))
+}
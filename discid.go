@@ -0,0 +1,156 @@
+package audiocd
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// discIDPregap is the 2 second offset between LBA 0 and the
+// start of the disc in MSF addressing, used by disc id algorithms.
+const discIDPregap = 2 * SectorsPerSecond
+
+// leadOut returns the sector after the end of the last track.
+func leadOut(toc []TrackPosition) int {
+	if len(toc) == 0 {
+		return 0
+	}
+	last := toc[len(toc)-1]
+	return last.StartSector + last.LengthSectors
+}
+
+// CDDBDiscID computes the 32-bit FreeDB/CDDB disc id from the
+// table of contents, e.g. from [*AudioCD.TOC]. It is usually
+// formatted as 8 hex digits:
+//
+//	fmt.Sprintf("%08x", audiocd.CDDBDiscID(toc))
+func CDDBDiscID(toc []TrackPosition) uint32 {
+	if len(toc) == 0 {
+		return 0
+	}
+	n := 0
+	for _, t := range toc {
+		for s := (t.StartSector + discIDPregap) / SectorsPerSecond; s > 0; s /= 10 {
+			n += s % 10
+		}
+	}
+	start := (toc[0].StartSector + discIDPregap) / SectorsPerSecond
+	end := (leadOut(toc) + discIDPregap) / SectorsPerSecond
+	return uint32(n%0xFF)<<24 | uint32(end-start)<<8 | uint32(len(toc))
+}
+
+// cdExtraGap is the number of sectors between the end of the audio
+// session and the start of the data session on an Enhanced CD.
+const cdExtraGap = 11400
+
+// musicBrainzTracks returns the audio tracks and lead-out used by
+// the MusicBrainz disc id. Data tracks at the end of the disc
+// (Enhanced CDs) are excluded, and the lead-out is moved to the end
+// of the audio session.
+func musicBrainzTracks(toc []TrackPosition) ([]TrackPosition, int) {
+	end := leadOut(toc)
+	for len(toc) > 1 && !toc[len(toc)-1].IsAudio() {
+		end = toc[len(toc)-1].StartSector - cdExtraGap
+		toc = toc[:len(toc)-1]
+	}
+	return toc, end
+}
+
+// MusicBrainzTOC returns the table of contents in the format used
+// for MusicBrainz lookups: the first and last track numbers, the lead-out
+// offset and the offset of each track, separated by spaces, e.g.
+//
+//	1 12 267257 150 22767 41887 ...
+//
+// This is the value of the toc parameter for the web service.
+func MusicBrainzTOC(toc []TrackPosition) string {
+	tracks, end := musicBrainzTracks(toc)
+	if len(tracks) == 0 {
+		return ""
+	}
+	s := fmt.Sprintf("%d %d %d", tracks[0].TrackNum, tracks[len(tracks)-1].TrackNum, end+discIDPregap)
+	for _, t := range tracks {
+		s += fmt.Sprintf(" %d", t.StartSector+discIDPregap)
+	}
+	return s
+}
+
+// MusicBrainzDiscID computes the MusicBrainz disc id from the table
+// of contents, e.g. "I5l9cCSFccLKFEKS.7wqSZAorPU-".
+//
+// See the [MusicBrainz documentation] for details of the algorithm.
+//
+// [MusicBrainz documentation]: https://musicbrainz.org/doc/Disc_ID_Calculation
+func MusicBrainzDiscID(toc []TrackPosition) string {
+	tracks, end := musicBrainzTracks(toc)
+	if len(tracks) == 0 {
+		return ""
+	}
+
+	h := sha1.New()
+	fmt.Fprintf(h, "%02X%02X", tracks[0].TrackNum, tracks[len(tracks)-1].TrackNum)
+	offsets := make([]int, 100)
+	offsets[0] = end + discIDPregap
+	for _, t := range tracks {
+		if t.TrackNum > 0 && t.TrackNum < len(offsets) {
+			offsets[t.TrackNum] = t.StartSector + discIDPregap
+		}
+	}
+	for _, o := range offsets {
+		fmt.Fprintf(h, "%08X", o)
+	}
+
+	id := base64.StdEncoding.EncodeToString(h.Sum(nil))
+	return strings.NewReplacer("+", ".", "/", "_", "=", "-").Replace(id)
+}
+
+// accurateRipBaseURL is the location of the AccurateRip database.
+const accurateRipBaseURL = "http://www.accuraterip.com/accuraterip/"
+
+// AccurateRipDiscID is the set of identifiers AccurateRip uses to
+// locate the checksums for a disc.
+type AccurateRipDiscID struct {
+	Tracks int    `json:"tracks"` // the number of audio tracks
+	ID1    uint32 `json:"id1"`    // sum of the audio track offsets and the lead-out
+	ID2    uint32 `json:"id2"`    // sum of the audio track offsets weighted by their position among the audio tracks
+	CDDB   uint32 `json:"cddb"`   // the FreeDB disc id, see [CDDBDiscID]
+}
+
+// NewAccurateRipDiscID computes the AccurateRip identifiers from the
+// table of contents. Data tracks are not counted, but the lead-out is
+// the end of the last track, including data tracks on Enhanced CDs.
+func NewAccurateRipDiscID(toc []TrackPosition) AccurateRipDiscID {
+	id := AccurateRipDiscID{CDDB: CDDBDiscID(toc)}
+	for _, t := range toc {
+		if !t.IsAudio() {
+			continue
+		}
+		id.Tracks++
+		id.ID1 += uint32(t.StartSector)
+		// weighted by the position among the audio tracks, which
+		// isn't the track number when a data track comes first
+		id.ID2 += uint32(max(t.StartSector, 1) * id.Tracks)
+	}
+	end := leadOut(toc)
+	id.ID1 += uint32(end)
+	id.ID2 += uint32(end * (id.Tracks + 1))
+	return id
+}
+
+// Filename returns the name of the AccurateRip database file for the disc,
+// e.g. "dBAR-005-00027afa-000bcbe8-2702f605.bin".
+func (id AccurateRipDiscID) Filename() string {
+	return fmt.Sprintf("dBAR-%03d-%08x-%08x-%08x.bin", id.Tracks, id.ID1, id.ID2, id.CDDB)
+}
+
+// Path returns the path of the database file relative to the root
+// of the AccurateRip database, e.g. "a/f/a/dBAR-005-00027afa-000bcbe8-2702f605.bin".
+func (id AccurateRipDiscID) Path() string {
+	return fmt.Sprintf("%x/%x/%x/%s", id.ID1&0xF, id.ID1>>4&0xF, id.ID1>>8&0xF, id.Filename())
+}
+
+// URL returns the location of the database file for the disc.
+func (id AccurateRipDiscID) URL() string {
+	return accurateRipBaseURL + id.Path()
+}
@@ -0,0 +1,59 @@
+package audiocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tocFromOffsets builds a table of contents from MSF track offsets
+// (including the 150 sector pregap), as used in disc id examples.
+func tocFromOffsets(leadout int, offsets ...int) []TrackPosition {
+	toc := make([]TrackPosition, len(offsets))
+	for i, o := range offsets {
+		toc[i].TrackNum = i + 1
+		toc[i].StartSector = o - 150
+		end := leadout
+		if i+1 < len(offsets) {
+			end = offsets[i+1]
+		}
+		toc[i].LengthSectors = end - o
+	}
+	return toc
+}
+
+func TestCDDBDiscID(t *testing.T) {
+	toc := tocFromOffsets(56891+150, 150, 6440, 23461, 31224, 45138)
+	assert.Equal(t, uint32(0x2702f605), CDDBDiscID(toc))
+	assert.Equal(t, uint32(0), CDDBDiscID(nil))
+}
+
+func TestMusicBrainzDiscID(t *testing.T) {
+	toc := tocFromOffsets(267257, 150, 22767, 41887, 58317, 72102, 91375, 104652, 115380, 132165, 143932, 159870, 174597)
+	assert.Equal(t, "1 12 267257 150 22767 41887 58317 72102 91375 104652 115380 132165 143932 159870 174597", MusicBrainzTOC(toc))
+	assert.Equal(t, "I5l9cCSFccLKFEKS.7wqSZAorPU-", MusicBrainzDiscID(toc))
+
+	// enhanced CD, the data track is excluded
+	extra := append(toc, TrackPosition{TrackNum: 13, Flags: 0x04, StartSector: 267257 - 150 + cdExtraGap, LengthSectors: 1000})
+	extra[11].LengthSectors += cdExtraGap
+	assert.Equal(t, "I5l9cCSFccLKFEKS.7wqSZAorPU-", MusicBrainzDiscID(extra))
+}
+
+func TestAccurateRipDiscID(t *testing.T) {
+	toc := tocFromOffsets(56891+150, 150, 6440, 23461, 31224, 45138)
+	id := NewAccurateRipDiscID(toc)
+
+	assert.Equal(t, 5, id.Tracks)
+	assert.Equal(t, uint32(0+6290+23311+31074+44988+56891), id.ID1)
+	assert.Equal(t, uint32(1*1+6290*2+23311*3+31074*4+44988*5+56891*6), id.ID2)
+	assert.Equal(t, CDDBDiscID(toc), id.CDDB)
+	assert.Equal(t, "dBAR-005-00027afa-000bcbe8-2702f605.bin", id.Filename())
+	assert.Equal(t, "http://www.accuraterip.com/accuraterip/a/f/a/dBAR-005-00027afa-000bcbe8-2702f605.bin", id.URL())
+
+	// a data track first, the audio tracks are weighted from 1
+	toc[0].Flags = 0x04
+	id = NewAccurateRipDiscID(toc)
+	assert.Equal(t, 4, id.Tracks)
+	assert.Equal(t, uint32(6290+23311+31074+44988+56891), id.ID1)
+	assert.Equal(t, uint32(6290*1+23311*2+31074*3+44988*4+56891*5), id.ID2)
+}
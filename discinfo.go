@@ -0,0 +1,85 @@
+package audiocd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// DiscInfo bundles the information about the drive and the disc
+// in it, for example to export as JSON.
+type DiscInfo struct {
+	Model           string            `json:"model"`
+	TOC             []TrackPosition   `json:"toc"`
+	LeadOut         int               `json:"leadOut"`
+	DurationSeconds float64           `json:"durationSeconds"`
+	CDDBID          string            `json:"cddbId"`
+	MusicBrainzID   string            `json:"musicBrainzId"`
+	MusicBrainzTOC  string            `json:"musicBrainzToc"`
+	AccurateRipID   AccurateRipDiscID `json:"accurateRipId"`
+	MCN             string            `json:"mcn,omitempty"`
+	CDText          *CDText           `json:"cdText,omitempty"`
+	Sessions        []Session         `json:"sessions,omitempty"`
+}
+
+// DiscInfo collects the drive model, table of contents, disc ids,
+// media catalog number, CD-Text and sessions of the disc in a single call.
+//
+// The MCN, CD-Text and sessions are left empty if the disc doesn't
+// have them or the drive can't read them. The duration only covers the
+// audio, so the data session of an Enhanced CD isn't counted.
+func (cd *AudioCD) DiscInfo() (DiscInfo, error) {
+	if !cd.IsOpen() {
+		return DiscInfo{}, os.ErrClosed
+	}
+	info := newDiscInfo(cd.Model(), cd.TOC())
+
+	mcn, err := cd.MCN()
+	if errors.Is(err, os.ErrClosed) {
+		return info, err
+	}
+	info.MCN = mcn
+
+	cdtext, err := cd.CDText()
+	if err == nil {
+		info.CDText = &cdtext
+	}
+
+	sessions, err := cd.Sessions()
+	if err == nil {
+		info.setSessions(sessions)
+	}
+	return info, nil
+}
+
+func newDiscInfo(model string, toc []TrackPosition) DiscInfo {
+	info := DiscInfo{
+		Model:          model,
+		TOC:            toc,
+		LeadOut:        leadOut(toc),
+		CDDBID:         fmt.Sprintf("%08x", CDDBDiscID(toc)),
+		MusicBrainzID:  MusicBrainzDiscID(toc),
+		MusicBrainzTOC: MusicBrainzTOC(toc),
+		AccurateRipID:  NewAccurateRipDiscID(toc),
+	}
+	// assume the usual CD-Extra layout until the sessions are known
+	tracks, end := musicBrainzTracks(toc)
+	if len(tracks) > 0 {
+		info.DurationSeconds = float64(end-tracks[0].StartSector) / SectorsPerSecond
+	}
+	return info
+}
+
+// setSessions records the sessions and measures the duration
+// up to the lead-out of the first session with audio.
+func (info *DiscInfo) setSessions(sessions []Session) {
+	info.Sessions = sessions
+	for _, s := range sessions {
+		for _, t := range info.TOC {
+			if t.IsAudio() && s.ContainsTrack(t.TrackNum) {
+				info.DurationSeconds = float64(s.LeadOut-t.StartSector) / SectorsPerSecond
+				return
+			}
+		}
+	}
+}
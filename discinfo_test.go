@@ -0,0 +1,51 @@
+package audiocd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscInfoJSON(t *testing.T) {
+	toc := []TrackPosition{
+		{TrackNum: 1, StartSector: 0, LengthSectors: 6290},
+		{TrackNum: 2, StartSector: 6290, LengthSectors: 17021},
+	}
+	info := newDiscInfo("MATSHITA", toc)
+	info.MCN = "0724384497729"
+
+	data, err := json.Marshal(info)
+	failIfErr(t, err)
+	assert.JSONEq(t, `{
+		"model": "MATSHITA",
+		"toc": [
+			{"flags": 0, "trackNum": 1, "startSector": 0, "lengthSectors": 6290, "pregapSectors": 0},
+			{"flags": 0, "trackNum": 2, "startSector": 6290, "lengthSectors": 17021, "pregapSectors": 0}
+		],
+		"leadOut": 23311,
+		"durationSeconds": 310.81333333333333,
+		"cddbId": "0f013602",
+		"musicBrainzId": "`+MusicBrainzDiscID(toc)+`",
+		"musicBrainzToc": "1 2 23461 150 6440",
+		"accurateRipId": {"tracks": 2, "id1": 29601, "id2": 82514, "cddb": 251737602},
+		"mcn": "0724384497729"
+	}`, string(data))
+}
+
+func TestDiscInfoSessions(t *testing.T) {
+	toc := []TrackPosition{
+		{TrackNum: 1, StartSector: 0, LengthSectors: 6290},
+		{TrackNum: 2, StartSector: 6290, LengthSectors: 28421},
+		{TrackNum: 3, StartSector: 34711, LengthSectors: 145214, Flags: 0x04},
+	}
+	info := newDiscInfo("", toc)
+	assert.Equal(t, 23311.0/75, info.DurationSeconds)
+
+	info.setSessions([]Session{
+		{Number: 1, FirstTrack: 1, LastTrack: 2, StartSector: 0, LeadOut: 23300},
+		{Number: 2, FirstTrack: 3, LastTrack: 3, StartSector: 34711, LeadOut: 179925},
+	})
+	assert.Equal(t, 23300.0/75, info.DurationSeconds)
+	assert.Len(t, info.Sessions, 2)
+}
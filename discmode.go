@@ -0,0 +1,91 @@
+package audiocd
+
+import "os"
+
+// DiscMode is the kind of disc in the drive.
+type DiscMode int
+
+const (
+	DiscModeUnknown    DiscMode = 0
+	DiscModeCDDA       DiscMode = 1 // audio only
+	DiscModeCDROMMode1 DiscMode = 2 // data only, mode 1
+	DiscModeCDROMMode2 DiscMode = 3 // data only, mode 2
+	DiscModeCDROMXA    DiscMode = 4 // data only, mode 2 XA
+	DiscModeMixed      DiscMode = 5 // a data track and audio tracks in one session
+	DiscModeCDExtra    DiscMode = 6 // audio tracks followed by a data session (Enhanced CD)
+	DiscModeDVD        DiscMode = 7
+	DiscModeBluRay     DiscMode = 8
+)
+
+func (m DiscMode) String() string {
+	switch m {
+	case DiscModeCDDA:
+		return "CD-DA"
+	case DiscModeCDROMMode1:
+		return "CD-ROM Mode 1"
+	case DiscModeCDROMMode2:
+		return "CD-ROM Mode 2"
+	case DiscModeCDROMXA:
+		return "CD-ROM XA"
+	case DiscModeMixed:
+		return "Mixed Mode CD"
+	case DiscModeCDExtra:
+		return "CD-Extra"
+	case DiscModeDVD:
+		return "DVD"
+	case DiscModeBluRay:
+		return "Blu-ray"
+	default:
+		return "unknown"
+	}
+}
+
+// HasAudio reports whether discs of this mode have audio tracks.
+func (m DiscMode) HasAudio() bool {
+	return m == DiscModeCDDA || m == DiscModeMixed || m == DiscModeCDExtra
+}
+
+// DiscMode detects the kind of disc in the drive, so that discs
+// without audio can be rejected before reading.
+func (cd *AudioCD) DiscMode() (DiscMode, error) {
+	if !cd.IsOpen() {
+		return DiscModeUnknown, os.ErrClosed
+	}
+	// drives without MMC support can only read CDs anyway
+	profile, err := cd.currentProfile()
+	if err == nil {
+		if mode, ok := profileDiscMode(profile); ok {
+			return mode, nil
+		}
+	}
+
+	mode, err := discStatus(cd)
+	if err != nil {
+		return DiscModeUnknown, err
+	}
+	return refineDiscMode(mode, cd.TOC()), nil
+}
+
+// profileDiscMode maps the MMC profiles of discs other than CDs.
+func profileDiscMode(profile uint16) (DiscMode, bool) {
+	switch {
+	case profile >= 0x10 && profile <= 0x1F, profile == 0x2A, profile == 0x2B:
+		return DiscModeDVD, true
+	case profile >= 0x40 && profile <= 0x4F:
+		return DiscModeBluRay, true
+	}
+	return DiscModeUnknown, false
+}
+
+// refineDiscMode tells Enhanced CDs apart from mixed mode CDs, which
+// the drive reports the same way: Enhanced CDs start with audio and
+// end with data, while mixed mode CDs start with the data track.
+func refineDiscMode(mode DiscMode, toc []TrackPosition) DiscMode {
+	if mode != DiscModeMixed || len(toc) < 2 {
+		return mode
+	}
+	if toc[0].IsAudio() && !toc[len(toc)-1].IsAudio() {
+		return DiscModeCDExtra
+	}
+	return mode
+}
@@ -0,0 +1,33 @@
+package audiocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscMode(t *testing.T) {
+	extra := []TrackPosition{
+		{TrackNum: 1, StartSector: 0, LengthSectors: 6290},
+		{TrackNum: 2, StartSector: 6290, LengthSectors: 28421},
+		{TrackNum: 3, StartSector: 34711, LengthSectors: 145214, Flags: 0x04},
+	}
+	assert.Equal(t, DiscModeCDExtra, refineDiscMode(DiscModeMixed, extra))
+
+	mixed := []TrackPosition{
+		{TrackNum: 1, StartSector: 0, LengthSectors: 20000, Flags: 0x04},
+		{TrackNum: 2, StartSector: 20000, LengthSectors: 17021},
+	}
+	assert.Equal(t, DiscModeMixed, refineDiscMode(DiscModeMixed, mixed))
+	assert.Equal(t, DiscModeCDDA, refineDiscMode(DiscModeCDDA, extra))
+
+	mode, ok := profileDiscMode(0x1B) // DVD+R
+	assert.True(t, ok)
+	assert.Equal(t, DiscModeDVD, mode)
+	_, ok = profileDiscMode(0x09) // CD-R
+	assert.False(t, ok)
+
+	assert.True(t, DiscModeCDExtra.HasAudio())
+	assert.False(t, DiscModeCDROMXA.HasAudio())
+	assert.Equal(t, "CD-Extra", DiscModeCDExtra.String())
+}
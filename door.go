@@ -0,0 +1,154 @@
+package audiocd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// LockDoor stops the eject button from opening the drive while
+// locked, so a rip can't be interrupted. The door is unlocked again
+// by Close.
+//
+// This requires a drive which supports MMC commands.
+func (cd *AudioCD) LockDoor(locked bool) error {
+	if !cd.IsOpen() {
+		return os.ErrClosed
+	}
+	var prevent byte
+	if locked {
+		prevent = 0x01
+	}
+	cdb := []byte{mmcPreventRemoval, 0, 0, 0, prevent, 0}
+	err := mmcCommand(cd, cdb, DirNone, nil, mmcTimeout)
+	if err != nil {
+		return err
+	}
+	cd.doorLocked = locked
+	return nil
+}
+
+// loadPollInterval is how often LoadMedia checks if the drive is ready.
+const loadPollInterval = 250 * time.Millisecond
+
+// noMediumGrace is how long LoadMedia waits for a disc to be detected
+// after the tray closes, before deciding the drive is empty.
+const noMediumGrace = 5 * time.Second
+
+// LoadMedia closes the drive's tray, waits until the disc has spun up
+// and then opens it, like [*AudioCD.OpenContext]. If the tray is empty
+// it returns [ErrNoDisc]. If Device isn't set, [DefaultDevice] is used.
+func (cd *AudioCD) LoadMedia(ctx context.Context) error {
+	if cd.IsOpen() {
+		return nil
+	}
+	if cd.Device == "" {
+		device, err := DefaultDevice()
+		if err != nil {
+			return err
+		}
+		cd.Device = device
+	}
+	drive, err := identifyDrive(cd.Device)
+	if err != nil {
+		return err
+	}
+	err = waitLoaded(ctx, &AudioCD{drive: drive})
+	closeDrive(drive)
+	if err != nil {
+		return err
+	}
+	return cd.OpenContext(ctx)
+}
+
+// waitLoaded closes the tray and polls the drive until it's ready.
+func waitLoaded(ctx context.Context, cd *AudioCD) error {
+	cdb := []byte{mmcStartStopUnit, 0, 0, 0, 0x03, 0} // load and start
+	err := mmcCommand(cd, cdb, DirNone, nil, mmcTimeout)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	ticker := time.NewTicker(loadPollInterval)
+	defer ticker.Stop()
+	for {
+		err := mmcCommand(cd, []byte{mmcTestUnitReady, 0, 0, 0, 0, 0}, DirNone, nil, mmcTimeout)
+		var se *SenseError
+		switch {
+		case err == nil:
+			return nil
+		case errors.As(err, &se) && se.NoMedium():
+			if time.Since(start) > noMediumGrace {
+				return fmt.Errorf("%w: %w", ErrNoDisc, err)
+			}
+		case Classify(err) != ErrorTransient:
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Eject unlocks the door and ejects the disc, then closes the drive
+// like [*AudioCD.Close]. Device is kept, so the AudioCD can be opened
+// again once there's a new disc, e.g. with [*AudioCD.LoadMedia].
+//
+// This requires a drive which supports MMC commands.
+func (cd *AudioCD) Eject() error {
+	if !cd.IsOpen() {
+		return os.ErrClosed
+	}
+	if cd.doorLocked {
+		err := cd.LockDoor(false)
+		if err != nil {
+			return err
+		}
+	}
+	cdb := []byte{mmcStartStopUnit, 0, 0, 0, 0x02, 0} // eject
+	err := mmcCommand(cd, cdb, DirNone, nil, mmcTimeout)
+	if err != nil {
+		return err
+	}
+	if cd.Device == "" {
+		cd.Device = devicePath(cd.drive)
+	}
+	return cd.Close()
+}
+
+// Reload ejects the disc, waits for another one to be inserted and
+// opens it, so one AudioCD can be used for disc after disc. It returns
+// ctx.Err() if ctx is done before a disc is inserted.
+//
+// This requires a drive which supports MMC commands.
+func (cd *AudioCD) Reload(ctx context.Context) error {
+	err := cd.Eject()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// start from the ejected disc, so the first poll without it, e.g.
+	// of a slot-loading drive or a tray closed again already, is seen
+	// as its removal
+	w := Watcher{Devices: []string{cd.Device}, Interval: loadPollInterval, assume: driveHasDisc}
+	removed := false
+	for e := range w.Watch(ctx) {
+		switch e.Type {
+		case EventDiscRemoved, EventTrayOpened:
+			removed = true
+		case EventDiscInserted:
+			// the ejected disc may be seen before the tray opens
+			if removed {
+				return cd.LoadMedia(ctx)
+			}
+		}
+	}
+	return ctx.Err()
+}
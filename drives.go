@@ -0,0 +1,123 @@
+package audiocd
+
+import (
+	"os"
+	"strings"
+	"unsafe"
+)
+
+// DriveInfo describes an optical drive found by [ListDrives].
+type DriveInfo struct {
+	Device   string        // the path to pass as [AudioCD].Device
+	Vendor   string        // the manufacturer, e.g. "HL-DT-ST"
+	Model    string        // the product name, e.g. "BD-RE BH16NS40"
+	Revision string        // the firmware version
+	Driver   InterfaceType // how the drive is accessed
+}
+
+// Identify returns the drive's identification, with the vendor, model
+// and revision which [*AudioCD.Model] joins split apart.
+func (cd *AudioCD) Identify() (DriveInfo, error) {
+	if !cd.IsOpen() {
+		return DriveInfo{}, os.ErrClosed
+	}
+	device := cd.Device
+	if device == "" {
+		device = devicePath(cd.drive)
+	}
+	return driveInfo(device, cd.drive), nil
+}
+
+// DefaultDevice returns the drive [*AudioCD.Open] uses when Device
+// isn't set, e.g. to show the user before opening it. It returns
+// [ErrNoDrive] if there's no usable drive.
+func DefaultDevice() (string, error) {
+	return defaultDevice()
+}
+
+// ListDrives returns the optical drives on the system, whether or not
+// they have a disc in them, e.g. to let the user pick one. Drives
+// which are busy or can't be accessed are left out. If no drives are
+// found because of such a problem, the error explains why, e.g.
+// [ErrPermission].
+func ListDrives() ([]DriveInfo, error) {
+	return findDrives(func(unsafe.Pointer) bool { return true })
+}
+
+// FindAudioCDs returns the drives which have a disc with audio tracks
+// in them, including mixed mode and Enhanced CDs, so a program can go
+// straight to the drive to rip from. Errors are as for [ListDrives].
+func FindAudioCDs() ([]DriveInfo, error) {
+	return findDrives(hasAudioDisc)
+}
+
+// findDrives identifies the drives on the system and returns
+// those that match.
+func findDrives(match func(drive unsafe.Pointer) bool) ([]DriveInfo, error) {
+	var drives []DriveInfo
+	var firstErr error
+	for _, device := range driveDevices() {
+		drive, err := identifyDrive(device)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if match(drive) {
+			drives = append(drives, driveInfo(device, drive))
+		}
+		closeDrive(drive)
+	}
+	if len(drives) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+	return drives, nil
+}
+
+// hasAudioDisc reports whether the drive has a disc with audio in it.
+func hasAudioDisc(drive unsafe.Pointer) bool {
+	cd := &AudioCD{drive: drive}
+	// DVDs and Blu-rays may look like data CDs to the ioctl
+	if profile, err := cd.currentProfile(); err == nil {
+		if _, ok := profileDiscMode(profile); ok {
+			return false
+		}
+	}
+	mode, err := discStatus(cd)
+	return err == nil && mode.HasAudio()
+}
+
+// driveInfo asks the drive who it is with INQUIRY, falling back to
+// the model cdparanoia found if the drive doesn't answer.
+func driveInfo(device string, drive unsafe.Pointer) DriveInfo {
+	info := DriveInfo{Device: device, Driver: interfaceType(drive)}
+	buf := make([]byte, 36)
+	cdb := []byte{mmcInquiry, 0, 0, 0, byte(len(buf)), 0}
+	err := mmcCommand(&AudioCD{drive: drive}, cdb, DirIn, buf, mmcTimeout)
+	if err != nil {
+		info.Vendor, info.Model, info.Revision = splitModel(model(drive))
+		return info
+	}
+	info.Vendor, info.Model, info.Revision = parseInquiry(buf)
+	return info
+}
+
+// splitModel splits the model string of cdparanoia's SCSI interfaces,
+// the INQUIRY fields joined by spaces. Others are kept whole as the
+// model.
+func splitModel(s string) (vendor, model, revision string) {
+	if len(s) != 8+1+16+1+4 || s[8] != ' ' || s[25] != ' ' {
+		return "", strings.TrimSpace(s), ""
+	}
+	return strings.TrimSpace(s[:8]), strings.TrimSpace(s[9:25]), strings.TrimSpace(s[26:])
+}
+
+// parseInquiry returns the identification strings of standard
+// INQUIRY data.
+func parseInquiry(data []byte) (vendor, model, revision string) {
+	field := func(start, end int) string {
+		return strings.TrimSpace(strings.TrimRight(string(data[start:end]), "\x00"))
+	}
+	return field(8, 16), field(16, 32), field(32, 36)
+}
@@ -0,0 +1,27 @@
+package audiocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInquiry(t *testing.T) {
+	data := []byte("\x05\x80\x00\x32\x5b\x00\x00\x00HL-DT-STBD-RE  BH16NS40 1.05")
+	vendor, model, revision := parseInquiry(data)
+	assert.Equal(t, "HL-DT-ST", vendor)
+	assert.Equal(t, "BD-RE  BH16NS40", model)
+	assert.Equal(t, "1.05", revision)
+}
+
+func TestSplitModel(t *testing.T) {
+	vendor, model, revision := splitModel("HL-DT-ST BD-RE  BH16NS40  1.05")
+	assert.Equal(t, "HL-DT-ST", vendor)
+	assert.Equal(t, "BD-RE  BH16NS40", model)
+	assert.Equal(t, "1.05", revision)
+
+	vendor, model, revision = splitModel("ATAPI compatible ")
+	assert.Equal(t, "", vendor)
+	assert.Equal(t, "ATAPI compatible", model)
+	assert.Equal(t, "", revision)
+}
@@ -0,0 +1,92 @@
+package audiocd
+
+import (
+	"encoding/binary"
+	"os"
+)
+
+// endianProbeSectors is the number of sectors read to detect the
+// drive's byte order.
+const endianProbeSectors = 10
+
+// DriveByteOrder returns the byte order in which the drive returns
+// samples. CD audio is little-endian, but some drives return it
+// byte-swapped. Read and the other methods returning audio convert
+// it to host byte order either way.
+//
+// cdparanoia detects the order on its first read. Otherwise it's
+// detected by reading some audio and checking which order gives the
+// smoother signal, as cdparanoia does. If the audio read is silent,
+// little-endian is assumed.
+func (cd *AudioCD) DriveByteOrder() (binary.ByteOrder, error) {
+	if !cd.IsOpen() {
+		return nil, os.ErrClosed
+	}
+	if cd.driveIsBigEndian() {
+		return binary.BigEndian, nil
+	}
+	return binary.LittleEndian, nil
+}
+
+// driveIsBigEndian detects whether the drive returns big-endian samples.
+func (cd *AudioCD) driveIsBigEndian() bool {
+	if cd.endianKnown {
+		return cd.bigEndian
+	}
+	if b := driveBigEndian(cd); b >= 0 {
+		cd.bigEndian, cd.endianKnown = b == 1, true
+		return cd.bigEndian
+	}
+
+	// probe the middle of the first audio track
+	var probe *TrackPosition
+	for _, t := range cd.TOC() {
+		if t.IsAudio() && t.LengthSectors >= endianProbeSectors {
+			probe = &t
+			break
+		}
+	}
+	cd.endianKnown, cd.bigEndian = true, false
+	if probe == nil {
+		return false
+	}
+	buf := make([]byte, endianProbeSectors*BytesPerSector)
+	sector := probe.StartSector + (probe.LengthSectors-endianProbeSectors)/2
+	err := cd.readCD(sector, endianProbeSectors, readCDUserData, 0, buf)
+	if err != nil {
+		// drives without MMC support are read through cdparanoia
+		return false
+	}
+	big, ok := detectBigEndian(buf)
+	if ok {
+		cd.bigEndian = big
+		setDriveBigEndian(cd, big)
+	}
+	return cd.bigEndian
+}
+
+// driveToNative converts samples as returned by the drive to host
+// byte order in place.
+func (cd *AudioCD) driveToNative(p []byte) {
+	if cd.driveIsBigEndian() == nativeLittleEndian {
+		swapBytes(p)
+	}
+}
+
+// detectBigEndian guesses whether audio is big-endian, from which
+// order has the smaller differences between neighbouring samples of
+// each channel. It returns false if there's no signal to tell by.
+func detectBigEndian(p []byte) (big, ok bool) {
+	const frame = Channels * BytesPerSample
+	var little, swapped uint64
+	for i := frame; i+frame <= len(p); i += BytesPerSample {
+		d := int(int16(binary.LittleEndian.Uint16(p[i:]))) - int(int16(binary.LittleEndian.Uint16(p[i-frame:])))
+		little += uint64(abs(d))
+		d = int(int16(binary.BigEndian.Uint16(p[i:]))) - int(int16(binary.BigEndian.Uint16(p[i-frame:])))
+		swapped += uint64(abs(d))
+	}
+	if little == swapped {
+		return false, false
+	}
+	return swapped < little, true
+}
@@ -0,0 +1,29 @@
+package audiocd
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectBigEndian(t *testing.T) {
+	p := make([]byte, SamplesPerSector*Channels*BytesPerSample)
+	for i := range SamplesPerSector {
+		v := int16(8000 * math.Sin(float64(i)/20))
+		binary.LittleEndian.PutUint16(p[i*4:], uint16(v))
+		binary.LittleEndian.PutUint16(p[i*4+2:], uint16(-v))
+	}
+	big, ok := detectBigEndian(p)
+	assert.True(t, ok)
+	assert.False(t, big)
+
+	swapBytes(p)
+	big, ok = detectBigEndian(p)
+	assert.True(t, ok)
+	assert.True(t, big)
+
+	_, ok = detectBigEndian(make([]byte, BytesPerSector))
+	assert.False(t, ok)
+}
@@ -1,12 +1,28 @@
 package audiocd
 
 import (
+	"errors"
 	"fmt"
 	"io/fs"
 )
 
-// ErrNoDrive is returned when no valid cd drive was found.
-var ErrNoDrive = fs.ErrNotExist
+// Errors returned by [*AudioCD.Open], for telling the user what to do.
+// The more specific errors of the drive match them with [errors.Is].
+var (
+	ErrNoDrive    = fs.ErrNotExist                                  // no valid cd drive was found
+	ErrNoDisc     = errors.New("audiocd: no disc in drive")         // the drive is empty
+	ErrNotAudioCD = errors.New("audiocd: disc has no audio tracks") // the disc is e.g. a data CD or a DVD
+	ErrDriveBusy  = errors.New("audiocd: drive is busy")            // another program has the drive open exclusively
+	ErrPermission = fs.ErrPermission                                // the user may not access the drive
+)
+
+// ErrReadTimeout is returned when reading a sector takes longer than
+// the limit set with [*AudioCD.SetReadTimeout].
+var ErrReadTimeout = errors.New("audiocd: sector read timed out")
+
+// ErrOffsetNotFound is returned by [*AudioCD.DetectReadOffset] when no
+// offset in the searched range matches the checksums.
+var ErrOffsetNotFound = errors.New("audiocd: no read offset matches the checksums")
 
 // Errors returned while reading audio data.
 type AudioCDError int
@@ -35,6 +51,20 @@ func (pe AudioCDError) Error() string {
 	return fmt.Sprintf("audiocd: %v", pe.name())
 }
 
+// Is matches the sentinel errors of [*AudioCD.Open] with the drive's
+// errors for the same conditions.
+func (pe AudioCDError) Is(target error) bool {
+	switch pe {
+	case ErrNoMediumPresent:
+		return target == ErrNoDisc
+	case ErrNoAudioTracks:
+		return target == ErrNotAudioCD
+	case ErrPermissionDenied:
+		return target == ErrPermission
+	}
+	return false
+}
+
 func (pe AudioCDError) name() string {
 	switch pe {
 	case ErrSetReadAudioMode:
@@ -78,3 +108,75 @@ func (pe AudioCDError) name() string {
 		return fmt.Sprintf("unknown error code: %v", int(pe))
 	}
 }
+
+// ErrorClass tells whether an error is worth retrying.
+type ErrorClass int
+
+const (
+	ErrorUnknown   ErrorClass = 0 // the error isn't from the drive, or can't be classified
+	ErrorTransient ErrorClass = 1 // the same operation may succeed later, e.g. the disc is spinning up or the drive is busy
+	ErrorFatal     ErrorClass = 2 // retrying won't help, e.g. there's no disc or the track doesn't exist
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorUnknown:
+		return "unknown"
+	case ErrorTransient:
+		return "transient"
+	case ErrorFatal:
+		return "fatal"
+	default:
+		return fmt.Sprintf("ErrorClass(%d)", int(c))
+	}
+}
+
+// Classify reports whether err, as returned by an [AudioCD] method,
+// is transient or fatal, so retry loops can give up on errors which
+// will never go away. Read errors on a damaged disc are transient,
+// since the sector may read on a later attempt.
+func Classify(err error) ErrorClass {
+	var pe AudioCDError
+	var se *SenseError
+	var dte *DataTrackError
+	switch {
+	case err == nil:
+		return ErrorUnknown
+	case errors.As(err, &se):
+		return se.class()
+	case errors.As(err, &pe):
+		return pe.class()
+	case errors.As(err, &dte):
+		return ErrorFatal
+	case errors.Is(err, ErrDriveBusy), errors.Is(err, ErrReadTimeout):
+		return ErrorTransient
+	case errors.Is(err, ErrNoDrive), errors.Is(err, ErrNoDisc), errors.Is(err, ErrNotAudioCD),
+		errors.Is(err, ErrPermission), errors.Is(err, fs.ErrClosed):
+		return ErrorFatal
+	}
+	return ErrorUnknown
+}
+
+func (pe AudioCDError) class() ErrorClass {
+	switch pe {
+	case ErrSetReadAudioMode, ErrReadTOCLeadOut, ErrReadTOCHeader, ErrReadTOCEntry,
+		ErrNoData, ErrUnknownReadError, ErrKernelMemory:
+		return ErrorTransient
+	default:
+		return ErrorFatal
+	}
+}
+
+func (se *SenseError) class() ErrorClass {
+	switch se.Key {
+	case SenseNotReady:
+		if se.NoMedium() {
+			return ErrorFatal
+		}
+		return ErrorTransient // e.g. becoming ready (asc 0x04)
+	case SenseNoSense, SenseRecoveredError, SenseMediumError, SenseUnitAttention, SenseAbortedCommand:
+		return ErrorTransient
+	default:
+		return ErrorFatal
+	}
+}
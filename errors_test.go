@@ -0,0 +1,43 @@
+package audiocd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	assert.ErrorIs(t, ErrNoMediumPresent, ErrNoDisc)
+	assert.ErrorIs(t, ErrNoAudioTracks, ErrNotAudioCD)
+	assert.ErrorIs(t, ErrPermissionDenied, ErrPermission)
+	assert.NotErrorIs(t, ErrReadTOCHeader, ErrNoDisc)
+	assert.NotErrorIs(t, ErrNoMediumPresent, ErrNotAudioCD)
+
+	var pe AudioCDError
+	assert.True(t, errors.As(error(ErrNoMediumPresent), &pe))
+	assert.Equal(t, "audiocd: no medium present", ErrNoMediumPresent.Error())
+}
+
+func TestClassify(t *testing.T) {
+	assert.Equal(t, ErrorUnknown, Classify(nil))
+	assert.Equal(t, ErrorUnknown, Classify(io.ErrShortWrite))
+	assert.Equal(t, ErrorFatal, Classify(ErrNoMediumPresent))
+	assert.Equal(t, ErrorFatal, Classify(ErrInvalidTrackNumber))
+	assert.Equal(t, ErrorTransient, Classify(ErrReadTOCHeader))
+	assert.Equal(t, ErrorTransient, Classify(fmt.Errorf("open: %w", ErrDriveBusy)))
+	assert.Equal(t, ErrorTransient, Classify(ErrReadTimeout))
+	assert.Equal(t, ErrorFatal, Classify(os.ErrClosed))
+	assert.Equal(t, ErrorFatal, Classify(&DataTrackError{TrackNum: 2}))
+
+	// becoming ready
+	assert.Equal(t, ErrorTransient, Classify(&SenseError{Key: SenseNotReady, ASC: 0x04, ASCQ: 0x01}))
+	// no medium
+	assert.Equal(t, ErrorFatal, Classify(&SenseError{Key: SenseNotReady, ASC: 0x3A}))
+	assert.Equal(t, ErrorTransient, Classify(fmt.Errorf("read: %w", &SenseError{Key: SenseMediumError, ASC: 0x11})))
+	assert.Equal(t, ErrorFatal, Classify(&SenseError{Key: SenseIllegalRequest, ASC: 0x21}))
+	assert.Equal(t, "transient", ErrorTransient.String())
+}
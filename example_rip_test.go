@@ -3,6 +3,7 @@ package audiocd_test
 import (
 	"encoding/binary"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/rabidaudio/audiocd"
@@ -63,3 +64,65 @@ func CreateWavHeader(nbytes uint32) []byte {
 	binary.LittleEndian.PutUint32(b[40:44], nbytes)
 	return b
 }
+
+// Example of ripping a single track with a track reader
+func ExampleAudioCD_Track() {
+	cd := audiocd.AudioCD{Device: "/dev/cdrom"}
+	err := cd.Open()
+	if err != nil {
+		panic(err)
+	}
+	defer cd.Close()
+
+	// get a reader for track 2
+	track, err := cd.Track(2)
+	if err != nil {
+		panic(err)
+	}
+
+	// create a new wave file to stream to
+	f, err := os.Create("track2.wav")
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	_, err = f.Write(CreateWavHeader(uint32(track.Size())))
+	if err != nil {
+		panic(err)
+	}
+
+	// stream to file
+	_, err = io.Copy(f, track)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Example of ripping every track of the disc to a wave file
+func ExampleAudioCD_Rip() {
+	cd := audiocd.AudioCD{Device: "/dev/cdrom", ByteOrder: binary.LittleEndian}
+	err := cd.Open()
+	if err != nil {
+		panic(err)
+	}
+	defer cd.Close()
+
+	report, err := cd.Rip(audiocd.RipOptions{
+		Retries: 3,
+		Output: func(t audiocd.TrackPosition) (io.Writer, error) {
+			f, err := os.Create(fmt.Sprintf("track%02d.wav", t.TrackNum))
+			if err != nil {
+				return nil, err
+			}
+			_, err = f.Write(CreateWavHeader(uint32(t.LengthSectors * audiocd.BytesPerSector)))
+			return f, err
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	for _, t := range report.Tracks {
+		fmt.Printf("track %02d: CRC %08X, %d errors\n", t.TrackNum, t.CRC32, t.Errors)
+	}
+}
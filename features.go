@@ -0,0 +1,61 @@
+package audiocd
+
+import "errors"
+
+// DriveFeatures describes what the drive can do, so ripping software
+// can choose how to read it.
+type DriveFeatures struct {
+	AccurateStream bool     `json:"accurateStream"` // the drive returns audio at exactly the requested position, so reads can be joined without overlap checks
+	C2Pointers     bool     `json:"c2Pointers"`     // the drive reports C2 errors, see [*AudioCD.ReadSectorsC2]
+	CDText         bool     `json:"cdText"`         // the drive reads CD-Text, as reported by its CD Read feature
+	CacheKB        int      `json:"cacheKB"`        // the size of the drive's buffer in kilobytes, or 0 if unknown
+	MaxReadSpeed   int      `json:"maxReadSpeed"`   // the fastest read speed in kB/s, or 0 if unknown
+	ReadSpeed      int      `json:"readSpeed"`      // the read speed currently in effect in kB/s, or 0 if unknown
+	Overread       Overread `json:"overread"`       // the edges of the disc the drive can read past
+}
+
+// DriveFeatures reads the drive's capabilities mode page and CD Read
+// feature, and tries overreading the disc (see [*AudioCD.DetectOverread]).
+//
+// This requires a drive which supports MMC commands.
+func (cd *AudioCD) DriveFeatures() (DriveFeatures, error) {
+	f, err := cd.capabilities()
+	if err != nil {
+		return f, err
+	}
+	desc, err := cd.feature(featureCDRead)
+	if err == nil {
+		// drives older than MMC-2 don't report features
+		f.CDText = parseCDReadFeature(desc)
+	}
+	f.Overread, err = cd.DetectOverread()
+	return f, err
+}
+
+// featureCDRead is the GET CONFIGURATION feature describing how the
+// drive reads CDs.
+const featureCDRead uint16 = 0x001E
+
+// parseCDReadFeature reports whether the CD Read feature descriptor
+// has the CD-Text bit set.
+func parseCDReadFeature(desc []byte) (cdText bool) {
+	return len(desc) > 4 && desc[4]&0x01 != 0
+}
+
+// parseCapabilities decodes the CD/DVD capabilities mode page.
+func parseCapabilities(page []byte) (DriveFeatures, error) {
+	if len(page) < 14 {
+		return DriveFeatures{}, errors.New("audiocd: capabilities mode page too short")
+	}
+	f := DriveFeatures{
+		AccurateStream: page[5]&0x02 != 0,
+		C2Pointers:     page[5]&0x10 != 0,
+		MaxReadSpeed:   int(page[8])<<8 | int(page[9]),
+		CacheKB:        int(page[12])<<8 | int(page[13]),
+	}
+	if len(page) >= 16 {
+		// obsolete since MMC-3, but still filled in by most drives
+		f.ReadSpeed = int(page[14])<<8 | int(page[15])
+	}
+	return f, nil
+}
@@ -0,0 +1,50 @@
+package audiocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCapabilities(t *testing.T) {
+	page := []byte{
+		0x2A, 0x14, // code, length
+		0x3F, 0x37, 0xF1, 0x77, 0x29, 0x23,
+		0x1B, 0x90, // max read speed, 7056 kB/s (40x)
+		0x01, 0x00,
+		0x08, 0x00, // 2 MB buffer
+		0x0B, 0x04, // current read speed, 2820 kB/s (16x)
+		0, 0, 0x1B, 0x90, 0x1B, 0x90,
+	}
+	f, err := parseCapabilities(page)
+	failIfErr(t, err)
+	assert.Equal(t, DriveFeatures{
+		AccurateStream: true,
+		C2Pointers:     true,
+		CacheKB:        2048,
+		MaxReadSpeed:   7056,
+		ReadSpeed:      2820,
+	}, f)
+
+	page[5] = 0x01
+	f, err = parseCapabilities(page)
+	failIfErr(t, err)
+	assert.False(t, f.AccurateStream)
+	assert.False(t, f.C2Pointers)
+
+	_, err = parseCapabilities(page[:8])
+	assert.Error(t, err)
+}
+
+func TestParseCDReadFeature(t *testing.T) {
+	assert.True(t, parseCDReadFeature([]byte{0x00, 0x1E, 0x08, 0x04, 0x03, 0, 0, 0}))
+	assert.False(t, parseCDReadFeature([]byte{0x00, 0x1E, 0x08, 0x04, 0x82, 0, 0, 0}))
+	assert.False(t, parseCDReadFeature(nil))
+}
+
+func TestSpeedMultiplier(t *testing.T) {
+	assert.Equal(t, 40, speedMultiplier(7056))
+	assert.Equal(t, 16, speedMultiplier(2820))
+	assert.Equal(t, 1, speedMultiplier(176))
+	assert.Equal(t, 0, speedMultiplier(0))
+}
@@ -0,0 +1,150 @@
+package audiocd
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// Filesystem selects the rules used to sanitize generated filenames.
+type Filesystem int
+
+const (
+	FilesystemPOSIX   Filesystem = 0 // replace only '/' and NUL
+	FilesystemWindows Filesystem = 1 // also replace <>:"\|?*, control characters, trailing dots/spaces and reserved names (NTFS, FAT)
+)
+
+// maxFilenameBytes is the maximum length of a single path component
+// on most filesystems.
+const maxFilenameBytes = 255
+
+// maxExtensionBytes is the longest extension kept when a filename
+// is shortened.
+const maxExtensionBytes = 16
+
+// FilenameData is the data available to a [FilenameTemplate].
+type FilenameData struct {
+	TrackNum    int
+	TrackCount  int
+	Title       string
+	Artist      string
+	Album       string
+	AlbumArtist string
+	Genre       string
+	Year        int
+	DiscID      string
+}
+
+// FilenameTemplate generates safe output paths for ripped tracks
+// from a [text/template] pattern, e.g.
+//
+//	{{.AlbumArtist}}/{{.Album}}/{{printf "%02d" .TrackNum}} - {{.Artist}} - {{.Title}}
+//
+// Slashes in the pattern separate directories. String fields are
+// sanitized for the target filesystem before they are substituted,
+// so a title can't introduce extra directories or invalid characters.
+type FilenameTemplate struct {
+	tmpl *template.Template
+	fs   Filesystem
+}
+
+// NewFilenameTemplate parses a filename pattern.
+func NewFilenameTemplate(pattern string, fs Filesystem) (*FilenameTemplate, error) {
+	tmpl, err := template.New("filename").Option("missingkey=error").Parse(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("audiocd: invalid filename template: %w", err)
+	}
+	return &FilenameTemplate{tmpl: tmpl, fs: fs}, nil
+}
+
+// Execute generates the path for the given track data. The extension,
+// if any, should be included in the pattern.
+func (ft *FilenameTemplate) Execute(data FilenameData) (string, error) {
+	data.Title = ft.sanitize(data.Title)
+	data.Artist = ft.sanitize(data.Artist)
+	data.Album = ft.sanitize(data.Album)
+	data.AlbumArtist = ft.sanitize(data.AlbumArtist)
+	data.Genre = ft.sanitize(data.Genre)
+	data.DiscID = ft.sanitize(data.DiscID)
+
+	sb := strings.Builder{}
+	err := ft.tmpl.Execute(&sb, data)
+	if err != nil {
+		return "", fmt.Errorf("audiocd: filename template: %w", err)
+	}
+
+	parts := strings.Split(sb.String(), "/")
+	for i, p := range parts {
+		if i == 0 && p == "" {
+			continue // absolute path
+		}
+		parts[i] = ft.component(p, i == len(parts)-1)
+	}
+	return path.Clean(strings.Join(parts, "/")), nil
+}
+
+// sanitize replaces the characters which are invalid
+// within a filename on the target filesystem.
+func (ft *FilenameTemplate) sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '/' || r == 0:
+			return '_'
+		case ft.fs == FilesystemWindows && (r < 0x20 || strings.ContainsRune(`<>:"\|?*`, r)):
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+// component makes a single path component valid. Long filenames
+// are shortened before the extension, so it's kept.
+func (ft *FilenameTemplate) component(p string, file bool) string {
+	if len(p) > maxFilenameBytes {
+		ext := ""
+		if file {
+			ext = path.Ext(p)
+			if len(ext) > maxExtensionBytes {
+				// a dot in the name, not an extension
+				ext = ""
+			}
+		}
+		p = truncateUTF8(p[:len(p)-len(ext)], maxFilenameBytes-len(ext)) + ext
+	}
+	if ft.fs == FilesystemWindows {
+		p = strings.TrimRight(p, ". ")
+		base, _, _ := strings.Cut(p, ".")
+		if isReservedWindowsName(base) {
+			p = "_" + p
+		}
+	}
+	if p == "" || p == "." || p == ".." {
+		return "_"
+	}
+	return p
+}
+
+// truncateUTF8 shortens s to at most n bytes without splitting a
+// character.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	s = s[:n]
+	for !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+func isReservedWindowsName(name string) bool {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case "CON", "PRN", "AUX", "NUL",
+		"COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
+		"LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package audiocd
+
+import (
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilenameTemplate(t *testing.T) {
+	data := FilenameData{
+		TrackNum: 3,
+		Title:    "What? / Why?",
+		Artist:   "AC/DC",
+		Album:    "Con",
+	}
+
+	ft, err := NewFilenameTemplate(`{{.Artist}}/{{.Album}}/{{printf "%02d" .TrackNum}} - {{.Title}}.flac`, FilesystemPOSIX)
+	failIfErr(t, err)
+	name, err := ft.Execute(data)
+	failIfErr(t, err)
+	assert.Equal(t, "AC_DC/Con/03 - What? _ Why?.flac", name)
+
+	ft, err = NewFilenameTemplate(`{{.Artist}}/{{.Album}}/{{printf "%02d" .TrackNum}} - {{.Title}}.flac`, FilesystemWindows)
+	failIfErr(t, err)
+	name, err = ft.Execute(data)
+	failIfErr(t, err)
+	assert.Equal(t, "AC_DC/_Con/03 - What_ _ Why_.flac", name)
+
+	ft, err = NewFilenameTemplate(`/music/{{.Album}}/{{.Title}}`, FilesystemPOSIX)
+	failIfErr(t, err)
+	name, err = ft.Execute(FilenameData{Title: ".."})
+	failIfErr(t, err)
+	assert.Equal(t, "/music/_/_", name)
+
+	// long names are shortened before the extension, on a character
+	// boundary
+	ft, err = NewFilenameTemplate(`{{.Album}}/{{.Title}}.flac`, FilesystemPOSIX)
+	failIfErr(t, err)
+	long := strings.Repeat("ü", 200)
+	name, err = ft.Execute(FilenameData{Album: long, Title: "x" + long})
+	failIfErr(t, err)
+	dir, file := path.Split(name)
+	assert.Equal(t, strings.Repeat("ü", 127)+"/", dir)
+	assert.Equal(t, "x"+strings.Repeat("ü", 124)+".flac", file)
+
+	_, err = NewFilenameTemplate(`{{.Nope`, FilesystemPOSIX)
+	assert.Error(t, err)
+}
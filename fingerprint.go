@@ -0,0 +1,49 @@
+package audiocd
+
+import (
+	"errors"
+	"os"
+	"slices"
+)
+
+// DiscFingerprint identifies a disc by its table of contents and, if
+// available, its media catalog number. It can be stored (e.g. as JSON)
+// and compared to the disc in the drive later, for example to check
+// that it's safe to resume an interrupted rip.
+type DiscFingerprint struct {
+	TOC []TrackPosition `json:"toc"`
+	MCN string          `json:"mcn,omitempty"`
+}
+
+// NewDiscFingerprint creates a fingerprint from a table of contents
+// and an optional media catalog number.
+func NewDiscFingerprint(toc []TrackPosition, mcn string) DiscFingerprint {
+	return DiscFingerprint{TOC: slices.Clone(toc), MCN: mcn}
+}
+
+// Fingerprint returns the fingerprint of the disc in the drive.
+// The MCN is left empty if the drive can't read it.
+func (cd *AudioCD) Fingerprint() (DiscFingerprint, error) {
+	if !cd.IsOpen() {
+		return DiscFingerprint{}, os.ErrClosed
+	}
+	mcn, err := cd.MCN()
+	if errors.Is(err, os.ErrClosed) {
+		return DiscFingerprint{}, err
+	}
+	return NewDiscFingerprint(cd.TOC(), mcn), nil
+}
+
+// Equal reports whether two fingerprints are of the same disc. The
+// tables of contents must match, apart from pregaps, which are only
+// known once [*AudioCD.ScanPregaps] has run. The MCNs are only
+// compared if both are known, since not every drive can read them.
+func (f DiscFingerprint) Equal(other DiscFingerprint) bool {
+	if f.MCN != "" && other.MCN != "" && f.MCN != other.MCN {
+		return false
+	}
+	return slices.EqualFunc(f.TOC, other.TOC, func(a, b TrackPosition) bool {
+		a.PregapSectors, b.PregapSectors = 0, 0
+		return a == b
+	})
+}
@@ -0,0 +1,38 @@
+package audiocd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscFingerprintEqual(t *testing.T) {
+	toc := []TrackPosition{
+		{TrackNum: 1, StartSector: 0, LengthSectors: 6290},
+		{TrackNum: 2, StartSector: 6290, LengthSectors: 17021},
+	}
+	a := NewDiscFingerprint(toc, "0724384497729")
+
+	assert.True(t, a.Equal(NewDiscFingerprint(toc, "0724384497729")))
+	assert.True(t, a.Equal(NewDiscFingerprint(toc, "")), "unknown MCN should be ignored")
+	assert.False(t, a.Equal(NewDiscFingerprint(toc, "0000000000001")))
+
+	other := []TrackPosition{
+		{TrackNum: 1, StartSector: 0, LengthSectors: 6290},
+		{TrackNum: 2, StartSector: 6290, LengthSectors: 17022},
+	}
+	assert.False(t, a.Equal(NewDiscFingerprint(other, "0724384497729")))
+	assert.False(t, a.Equal(NewDiscFingerprint(toc[:1], "")))
+
+	scanned := slices.Clone(toc)
+	scanned[1].PregapSectors = 150
+	assert.True(t, a.Equal(NewDiscFingerprint(scanned, "")), "pregaps should be ignored")
+	data := slices.Clone(toc)
+	data[1].Flags = 0x04
+	assert.False(t, a.Equal(NewDiscFingerprint(data, "")))
+
+	// the fingerprint shouldn't alias the caller's TOC
+	toc[0].LengthSectors = 1
+	assert.Equal(t, 6290, a.TOC[0].LengthSectors)
+}
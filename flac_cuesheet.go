@@ -0,0 +1,103 @@
+package audiocd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// flacCueSheetBlockType is the FLAC metadata block type for CUESHEET.
+const flacCueSheetBlockType = 5
+
+// flacLeadOutTrack is the track number of the lead-out track for CD-DA.
+const flacLeadOutTrack = 170
+
+// flacLeadInSamples is the number of lead-in samples of a CD-DA disc,
+// i.e. the 2 seconds before sector 0.
+const flacLeadInSamples = 2 * SampleRate
+
+// FLACMetadataBlock encodes the cue sheet as a binary FLAC CUESHEET
+// metadata block, including the 4-byte block header. Set last if
+// this is the final metadata block before the audio frames.
+//
+// All tracks must be stored in the same file, and LeadOut must be set.
+// Sample offsets are relative to the FileStart of the tracks.
+//
+// See the [FLAC format] for details.
+//
+// [FLAC format]: https://xiph.org/flac/format.html#metadata_block_cuesheet
+func (cs *CueSheet) FLACMetadataBlock(last bool) ([]byte, error) {
+	if len(cs.Tracks) == 0 {
+		return nil, fmt.Errorf("audiocd: cue sheet has no tracks")
+	}
+	if len(cs.Tracks) > 99 {
+		return nil, fmt.Errorf("audiocd: cue sheet has too many tracks")
+	}
+	if len(cs.Catalog) > 128 {
+		return nil, fmt.Errorf("audiocd: invalid catalog number: %v", cs.Catalog)
+	}
+	fileStart := cs.Tracks[0].FileStart
+	if cs.LeadOut <= fileStart {
+		return nil, fmt.Errorf("audiocd: cue sheet lead-out not set")
+	}
+
+	body := bytes.Buffer{}
+	catalog := make([]byte, 128)
+	copy(catalog, cs.Catalog)
+	body.Write(catalog)
+	body.Write(binary.BigEndian.AppendUint64(nil, flacLeadInSamples))
+	body.WriteByte(0x80) // is CD-DA
+	body.Write(make([]byte, 258))
+	body.WriteByte(byte(len(cs.Tracks) + 1))
+
+	for _, t := range cs.Tracks {
+		if t.File != cs.Tracks[0].File || t.FileStart != fileStart {
+			return nil, fmt.Errorf("audiocd: FLAC cue sheets require all tracks in a single file")
+		}
+		if len(t.Indexes) == 0 {
+			return nil, fmt.Errorf("audiocd: track %d has no index points", t.TrackNum)
+		}
+		if len(t.ISRC) != 0 && len(t.ISRC) != 12 {
+			return nil, fmt.Errorf("audiocd: invalid ISRC for track %d: %v", t.TrackNum, t.ISRC)
+		}
+
+		start := t.Indexes[0].Sector
+		if start < fileStart {
+			return nil, fmt.Errorf("audiocd: track %d starts before the file", t.TrackNum)
+		}
+		body.Write(binary.BigEndian.AppendUint64(nil, uint64(start-fileStart)*SamplesPerSector))
+		body.WriteByte(byte(t.TrackNum))
+		isrc := make([]byte, 12)
+		copy(isrc, t.ISRC)
+		body.Write(isrc)
+		var flags byte
+		if t.Flags&0x04 != 0 {
+			flags |= 0x80 // non-audio
+		}
+		if t.Flags&0x01 != 0 {
+			flags |= 0x40 // pre-emphasis
+		}
+		body.WriteByte(flags)
+		body.Write(make([]byte, 13))
+		body.WriteByte(byte(len(t.Indexes)))
+		for _, idx := range t.Indexes {
+			body.Write(binary.BigEndian.AppendUint64(nil, uint64(idx.Sector-start)*SamplesPerSector))
+			body.WriteByte(byte(idx.Number))
+			body.Write(make([]byte, 3))
+		}
+	}
+
+	// lead-out track
+	body.Write(binary.BigEndian.AppendUint64(nil, uint64(cs.LeadOut-fileStart)*SamplesPerSector))
+	body.WriteByte(flacLeadOutTrack)
+	body.Write(make([]byte, 12+1+13))
+	body.WriteByte(0)
+
+	header := byte(flacCueSheetBlockType)
+	if last {
+		header |= 0x80
+	}
+	n := body.Len()
+	block := []byte{header, byte(n >> 16), byte(n >> 8), byte(n)}
+	return append(block, body.Bytes()...), nil
+}
@@ -0,0 +1,76 @@
+package flacenc
+
+// bitWriter packs values most significant bit first.
+type bitWriter struct {
+	buf []byte
+	acc uint64
+	n   uint // the number of bits in acc
+}
+
+// write appends the low bits of v. bits must be at most 32.
+func (bw *bitWriter) write(v uint64, bits uint) {
+	bw.acc = bw.acc<<bits | v&(1<<bits-1)
+	bw.n += bits
+	for bw.n >= 8 {
+		bw.n -= 8
+		bw.buf = append(bw.buf, byte(bw.acc>>bw.n))
+	}
+}
+
+// writeSigned appends v in two's complement.
+func (bw *bitWriter) writeSigned(v int32, bits uint) {
+	bw.write(uint64(uint32(v)), bits)
+}
+
+// writeUnary appends q zero bits followed by a one.
+func (bw *bitWriter) writeUnary(q uint32) {
+	for q >= 32 {
+		bw.write(0, 32)
+		q -= 32
+	}
+	bw.write(1, uint(q)+1)
+}
+
+// align pads with zero bits to a byte boundary.
+func (bw *bitWriter) align() {
+	if bw.n > 0 {
+		bw.write(0, 8-bw.n)
+	}
+}
+
+// bytes returns the data written, which must be byte aligned.
+func (bw *bitWriter) bytes() []byte {
+	return bw.buf
+}
+
+// crc8 computes the CRC-8 (polynomial 0x07) of a frame header.
+func crc8(data []byte) byte {
+	var crc byte
+	for _, b := range data {
+		crc ^= b
+		for range 8 {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// crc16 computes the CRC-16 (polynomial 0x8005) of a frame.
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for range 8 {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x8005
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
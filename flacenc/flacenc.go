@@ -0,0 +1,292 @@
+// Package flacenc encodes CD audio to FLAC, so tracks can be ripped
+// straight to compressed files:
+//
+//	report, err := cd.Rip(audiocd.RipOptions{
+//		Output: func(t audiocd.TrackPosition) (io.Writer, error) {
+//			f, err := os.Create(fmt.Sprintf("track%02d.flac", t.TrackNum))
+//			...
+//			return flacenc.NewEncoder(f, flacenc.Options{CloseWriter: true})
+//		},
+//	})
+//
+// To rip the whole disc to a single file, embed a cue sheet with the
+// track and index points, and optionally write it out as a sidecar too:
+//
+//	cs, err := cd.CueSheet(audiocd.CueSheetOptions{File: "disc.flac", ScanIndexes: true})
+//	...
+//	enc, err := flacenc.NewEncoder(f, flacenc.Options{CueSheet: cs, SeekInterval: 10 * time.Second})
+//	...
+//	_, err = cd.RipRange(0, cs.LeadOut, enc)
+//	...
+//	err = enc.Close()
+//	...
+//	_, err = cs.WriteTo(cueFile)
+//
+// The encoder writes 16-bit, 44.1kHz stereo streams using the fixed
+// predictors, which compresses CD audio nearly as well as the reference
+// encoder's default settings.
+package flacenc
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+	"time"
+
+	"github.com/rabidaudio/audiocd"
+)
+
+// BlockSize is the number of samples per channel in each frame.
+const BlockSize = 4096
+
+// Metadata block types
+const (
+	BlockTypeStreamInfo    = 0
+	BlockTypePadding       = 1
+	BlockTypeSeekTable     = 3
+	BlockTypeVorbisComment = 4
+	BlockTypeCueSheet      = 5
+)
+
+// streamInfoSize is the size of the STREAMINFO block, without its header.
+const streamInfoSize = 34
+
+// Options configures an [Encoder].
+type Options struct {
+	// CueSheet, if set, is embedded as a CUESHEET block, for a rip
+	// of the disc to a single file. See [audiocd.CueSheet.FLACMetadataBlock].
+	CueSheet *audiocd.CueSheet
+
+	// TotalSamples is the number of samples per channel which will be
+	// written, if known. It defaults to the length of the CueSheet.
+	TotalSamples uint64
+
+	// SeekInterval, if set along with TotalSamples, adds a SEEKTABLE
+	// block with a seek point at each interval. The points are filled
+	// in by Close if the writer is seekable.
+	SeekInterval time.Duration
+
+	// Metadata holds additional metadata blocks to write after the
+	// STREAMINFO block, each including its 4-byte header.
+	// The last-block flags are set by the encoder.
+	Metadata [][]byte
+
+	// CloseWriter makes Close close the underlying writer too, if it's
+	// an [io.Closer], e.g. for writers returned to [audiocd.RipOptions]
+	// Output, which are only closed through the encoder.
+	CloseWriter bool
+}
+
+// Encoder writes PCM audio as a FLAC stream. Data is expected in host
+// byte order, as returned by [audiocd.AudioCD.Read] when ByteOrder
+// isn't set.
+//
+// If the underlying writer is an [io.WriteSeeker], Close fills in
+// the total number of samples and the MD5 of the audio in the
+// STREAMINFO block. Otherwise they are left unset, which FLAC
+// decoders accept.
+type Encoder struct {
+	w     io.Writer
+	ws    io.WriteSeeker
+	start int64 // the position of the stream in ws
+
+	closeWriter bool  // whether Close closes w
+	infoLast    bool  // whether STREAMINFO is the only metadata block
+	audioStart  int64 // the offset of the first frame from the stream start
+	seekTable   seekTable
+
+	md5      hash.Hash
+	pending  []byte // PCM data not yet encoded
+	written  int64  // bytes written to w
+	frames   []Frame
+	total    uint64 // samples per channel encoded
+	minFrame int
+	maxFrame int
+
+	channels [4][]int32 // left, right, mid and side
+	residual [4][]int32
+	err      error
+}
+
+// Frame describes an encoded frame, as used by a seek table.
+type Frame struct {
+	Sample uint64 // the number of the first sample (per channel) of the frame
+	Offset int64  // the byte offset of the frame from the first frame
+}
+
+// frameBytes is the amount of PCM data in a full frame.
+const frameBytes = BlockSize * audiocd.Channels * audiocd.BytesPerSample
+
+// NewEncoder writes the FLAC stream header and metadata to w
+// and returns an encoder for the audio.
+func NewEncoder(w io.Writer, opts Options) (*Encoder, error) {
+	e := &Encoder{w: w, md5: md5.New(), closeWriter: opts.CloseWriter}
+	if ws, ok := w.(io.WriteSeeker); ok {
+		start, err := ws.Seek(0, io.SeekCurrent)
+		if err == nil {
+			e.ws, e.start = ws, start
+		}
+	}
+	for i := range e.channels {
+		e.channels[i] = make([]int32, BlockSize)
+		e.residual[i] = make([]int32, BlockSize)
+	}
+
+	var blocks [][]byte
+	total := opts.TotalSamples
+	if opts.CueSheet != nil {
+		block, err := opts.CueSheet.FLACMetadataBlock(false)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+		if total == 0 && len(opts.CueSheet.Tracks) > 0 {
+			total = uint64(opts.CueSheet.LeadOut-opts.CueSheet.Tracks[0].FileStart) * audiocd.SamplesPerSector
+		}
+	}
+	if opts.SeekInterval > 0 && total > 0 {
+		e.seekTable = newSeekTable(total, opts.SeekInterval)
+		// goes first, so its position is known
+		blocks = append([][]byte{e.seekTable.placeholder()}, blocks...)
+	}
+	for _, block := range opts.Metadata {
+		if len(block) < 4 {
+			return nil, errors.New("flacenc: metadata block is missing its header")
+		}
+		blocks = append(blocks, append([]byte{}, block...))
+	}
+
+	e.infoLast = len(blocks) == 0
+	e.seekTable.last = len(blocks) == 1
+	header := []byte("fLaC")
+	header = append(header, e.streamInfo(e.infoLast)...)
+	for i, block := range blocks {
+		block[0] &^= 0x80
+		if i == len(blocks)-1 {
+			block[0] |= 0x80
+		}
+		header = append(header, block...)
+	}
+	err := e.write(header)
+	if err != nil {
+		return nil, err
+	}
+	e.audioStart = e.written
+	return e, nil
+}
+
+// streamInfo encodes the STREAMINFO block with what is known so far.
+func (e *Encoder) streamInfo(last bool) []byte {
+	b := metadataHeader(BlockTypeStreamInfo, streamInfoSize, last)
+	b = binary.BigEndian.AppendUint16(b, BlockSize)
+	b = binary.BigEndian.AppendUint16(b, BlockSize)
+	b = append(b, byte(e.minFrame>>16), byte(e.minFrame>>8), byte(e.minFrame))
+	b = append(b, byte(e.maxFrame>>16), byte(e.maxFrame>>8), byte(e.maxFrame))
+	// 20 bits sample rate, 3 bits channels-1, 5 bits bits per sample-1,
+	// 36 bits total samples
+	v := uint64(audiocd.SampleRate)<<44 |
+		uint64(audiocd.Channels-1)<<41 |
+		uint64(audiocd.BitsPerSample-1)<<36 |
+		e.total&(1<<36-1)
+	b = binary.BigEndian.AppendUint64(b, v)
+	if e.total > 0 {
+		b = e.md5.Sum(b)
+	} else {
+		b = append(b, make([]byte, md5.Size)...)
+	}
+	return b
+}
+
+// metadataHeader returns the header of a metadata block.
+func metadataHeader(blockType byte, size int, last bool) []byte {
+	if last {
+		blockType |= 0x80
+	}
+	return []byte{blockType, byte(size >> 16), byte(size >> 8), byte(size)}
+}
+
+func (e *Encoder) write(p []byte) error {
+	if e.err != nil {
+		return e.err
+	}
+	n, err := e.w.Write(p)
+	e.written += int64(n)
+	e.err = err
+	return err
+}
+
+// Write encodes PCM data, writing out each frame once it's complete.
+func (e *Encoder) Write(p []byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+	e.pending = append(e.pending, p...)
+	for len(e.pending) >= frameBytes {
+		if err := e.encodeFrame(e.pending[:frameBytes]); err != nil {
+			return 0, err
+		}
+		e.pending = e.pending[frameBytes:]
+	}
+	// reclaim the space of encoded data
+	e.pending = append(e.pending[:0:0], e.pending...)
+	return len(p), nil
+}
+
+// Close encodes the remaining data and completes the STREAMINFO
+// block if possible. It doesn't close the underlying writer, unless
+// CloseWriter was set.
+func (e *Encoder) Close() error {
+	err := e.finish()
+	if c, ok := e.w.(io.Closer); ok && e.closeWriter {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+func (e *Encoder) finish() error {
+	if e.err != nil {
+		return e.err
+	}
+	frame := audiocd.Channels * audiocd.BytesPerSample
+	if len(e.pending)%frame != 0 {
+		return errors.New("flacenc: stream ends with a partial sample")
+	}
+	if len(e.pending) > 0 {
+		if err := e.encodeFrame(e.pending); err != nil {
+			return err
+		}
+		e.pending = nil
+	}
+	if e.ws == nil {
+		return nil
+	}
+	end := e.start + e.written
+	if _, err := e.ws.Seek(e.start+4, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := e.ws.Write(e.streamInfo(e.infoLast)); err != nil {
+		return err
+	}
+	if e.seekTable.points > 0 {
+		// the seek table follows STREAMINFO
+		if _, err := e.ws.Write(e.seekTable.encode(e.frames, e.total)); err != nil {
+			return err
+		}
+	}
+	_, err := e.ws.Seek(end, io.SeekStart)
+	return err
+}
+
+// Frames returns the frames encoded so far.
+func (e *Encoder) Frames() []Frame {
+	return e.frames
+}
+
+// TotalSamples returns the number of samples per channel encoded so far.
+func (e *Encoder) TotalSamples() uint64 {
+	return e.total
+}
@@ -0,0 +1,472 @@
+package flacenc
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rabidaudio/audiocd"
+)
+
+// bitReader is a minimal reader for checking encoded streams.
+type bitReader struct {
+	data []byte
+	pos  int // in bits
+}
+
+func (br *bitReader) read(n uint) uint64 {
+	var v uint64
+	for range n {
+		bit := br.data[br.pos/8] >> (7 - br.pos%8) & 1
+		v = v<<1 | uint64(bit)
+		br.pos++
+	}
+	return v
+}
+
+func (br *bitReader) readSigned(n uint) int32 {
+	v := br.read(n)
+	return int32(v<<(64-n)>>(64-n)) | -int32(v>>(n-1)&1)<<(n-1)
+}
+
+func (br *bitReader) readUTF8() uint64 {
+	b := br.read(8)
+	n := 0
+	for b&(0x80>>n) != 0 {
+		n++
+	}
+	if n == 0 {
+		return b
+	}
+	v := b & (0xFF >> (n + 1))
+	for range n - 1 {
+		v = v<<6 | br.read(8)&0x3F
+	}
+	return v
+}
+
+// decode decodes a stream written by the encoder into
+// interleaved samples, checking the CRCs along the way.
+func decode(t *testing.T, data []byte) (info []byte, samples []int16, metadata [][]byte) {
+	if string(data[:4]) != "fLaC" {
+		t.Fatal("missing stream marker")
+	}
+	pos := 4
+	for last := false; !last; {
+		last = data[pos]&0x80 != 0
+		size := int(data[pos+1])<<16 | int(data[pos+2])<<8 | int(data[pos+3])
+		block := data[pos : pos+4+size]
+		if block[0]&0x7F == BlockTypeStreamInfo {
+			info = block[4:]
+		} else {
+			metadata = append(metadata, block)
+		}
+		pos += 4 + size
+	}
+
+	br := &bitReader{data: data, pos: pos * 8}
+	for br.pos/8 < len(data) {
+		start := br.pos / 8
+		if sync := br.read(16); sync != 0xFFF8 {
+			t.Fatalf("bad sync code %x at %d", sync, start)
+		}
+		sizeCode := br.read(4)
+		br.read(4)
+		assignment := br.read(4)
+		br.read(4)
+		br.readUTF8()
+		n := BlockSize
+		switch sizeCode {
+		case 0x6:
+			n = int(br.read(8)) + 1
+		case 0x7:
+			n = int(br.read(16)) + 1
+		}
+		if crc := byte(br.read(8)); crc != crc8(data[start:br.pos/8-1]) {
+			t.Fatal("bad header CRC")
+		}
+
+		var ch [2][]int32
+		for c := range ch {
+			bps := uint(16)
+			if (assignment == channelsLeftSide && c == 1) ||
+				(assignment == channelsRightSide && c == 0) ||
+				(assignment == channelsMidSide && c == 1) {
+				bps = 17
+			}
+			ch[c] = decodeSubframe(t, br, n, bps)
+		}
+		if br.pos%8 != 0 {
+			br.pos += 8 - br.pos%8
+		}
+		end := br.pos / 8
+		if crc := uint16(br.read(16)); crc != crc16(data[start:end]) {
+			t.Fatal("bad frame CRC")
+		}
+
+		for i := range n {
+			a, b := ch[0][i], ch[1][i]
+			var l, r int32
+			switch assignment {
+			case channelsIndependent:
+				l, r = a, b
+			case channelsLeftSide:
+				l, r = a, a-b
+			case channelsRightSide:
+				l, r = a+b, b
+			case channelsMidSide:
+				m := a<<1 | b&1
+				l, r = (m+b)>>1, (m-b)>>1
+			}
+			samples = append(samples, int16(l), int16(r))
+		}
+	}
+	return info, samples, metadata
+}
+
+func decodeSubframe(t *testing.T, br *bitReader, n int, bps uint) []int32 {
+	header := br.read(8)
+	x := make([]int32, n)
+	switch kind := header >> 1; {
+	case kind == 0:
+		v := br.readSigned(bps)
+		for i := range x {
+			x[i] = v
+		}
+	case kind == 1:
+		for i := range x {
+			x[i] = br.readSigned(bps)
+		}
+	case kind&0x38 == 0x08:
+		order := int(kind & 0x07)
+		for i := range order {
+			x[i] = br.readSigned(bps)
+		}
+		if br.read(2) != 0 || br.read(4) != 0 {
+			t.Fatal("unexpected residual coding")
+		}
+		k := uint(br.read(4))
+		for i := order; i < n; i++ {
+			q := uint64(0)
+			for br.read(1) == 0 {
+				q++
+			}
+			u := uint32(q<<k | br.read(k))
+			res := int32(u>>1) ^ -int32(u&1)
+			x[i] = res + (x[i] - predict(x, i, order))
+		}
+	default:
+		t.Fatalf("unexpected subframe type %x", header)
+	}
+	return x
+}
+
+func pcm(samples []int16) []byte {
+	p := make([]byte, len(samples)*2)
+	for i, s := range samples {
+		binary.NativeEndian.PutUint16(p[i*2:], uint16(s))
+	}
+	return p
+}
+
+func testSignal(n int) []int16 {
+	s := make([]int16, n*2)
+	for i := range n {
+		s[i*2] = int16(20000 * math.Sin(float64(i)/20))
+		s[i*2+1] = int16(15000*math.Sin(float64(i)/7) + float64(i%13))
+	}
+	// a stretch of silence and some noise
+	for i := 1000; i < min(5000, len(s)); i++ {
+		s[i] = 0
+	}
+	for i := 6000; i < min(6100, len(s)); i++ {
+		s[i] = int16(i * 7919 % 65536)
+	}
+	return s
+}
+
+func TestEncoder(t *testing.T) {
+	samples := testSignal(3*BlockSize + 100)
+	data := pcm(samples)
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "test.flac"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	e, err := NewEncoder(f, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// write in odd sized pieces
+	for p := data; len(p) > 0; {
+		n := min(len(p), 3001)
+		if _, err := e.Write(p[:n]); err != nil {
+			t.Fatal(err)
+		}
+		p = p[n:]
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Frames()) != 4 || e.Frames()[1].Sample != BlockSize {
+		t.Errorf("unexpected frames %v", e.Frames())
+	}
+
+	encoded, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(encoded) >= len(data) {
+		t.Errorf("no compression: %d >= %d", len(encoded), len(data))
+	}
+	info, decoded, _ := decode(t, encoded)
+	if !slices.Equal(decoded, samples) {
+		t.Fatal("decoded samples don't match")
+	}
+
+	total := binary.BigEndian.Uint64(info[10:]) & (1<<36 - 1)
+	if total != uint64(len(samples)/2) {
+		t.Errorf("total samples %d", total)
+	}
+	le := make([]byte, len(data))
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(le[i*2:], uint16(s))
+	}
+	if sum := md5.Sum(le); !bytes.Equal(info[18:], sum[:]) {
+		t.Error("MD5 mismatch")
+	}
+}
+
+func TestEncoderMetadata(t *testing.T) {
+	var buf bytes.Buffer
+	block := []byte{0x80 | BlockTypePadding, 0, 0, 2, 0, 0}
+	e, err := NewEncoder(&buf, Options{Metadata: [][]byte{block, block}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Write(pcm(testSignal(10))); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, decoded, metadata := decode(t, buf.Bytes())
+	if len(decoded) != 20 || len(metadata) != 2 {
+		t.Fatalf("decoded %d samples, %d blocks", len(decoded), len(metadata))
+	}
+	if metadata[0][0] != BlockTypePadding || metadata[1][0] != 0x80|BlockTypePadding {
+		t.Error("last block flags not set")
+	}
+	// unseekable streams leave the totals unset
+	if binary.BigEndian.Uint64(info[10:])&(1<<36-1) != 0 {
+		t.Error("total samples set")
+	}
+}
+
+func TestEncoderPartialSample(t *testing.T) {
+	e, err := NewEncoder(io.Discard, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Write([]byte{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Close(); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestWriteUTF8(t *testing.T) {
+	for _, v := range []uint64{0, 0x7F, 0x80, 0x7FF, 0x800, 0xFFFF, 0x10000, 1<<36 - 1} {
+		bw := &bitWriter{}
+		writeUTF8(bw, v)
+		br := &bitReader{data: bw.bytes()}
+		if got := br.readUTF8(); got != v {
+			t.Errorf("%x: got %x", v, got)
+		}
+	}
+}
+
+func TestEncoderCueSheet(t *testing.T) {
+	toc := []audiocd.TrackPosition{
+		{TrackNum: 1, StartSector: 0, LengthSectors: 20},
+		{TrackNum: 2, StartSector: 20, LengthSectors: 30},
+	}
+	cs := audiocd.NewCueSheet(toc, "disc.flac")
+	samples := testSignal(50 * audiocd.SamplesPerSector)
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "disc.flac"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	e, err := NewEncoder(f, Options{CueSheet: cs, SeekInterval: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Write(pcm(samples)); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, decoded, metadata := decode(t, encoded)
+	if !slices.Equal(decoded, samples) {
+		t.Fatal("decoded samples don't match")
+	}
+	if len(metadata) != 2 || metadata[0][0] != BlockTypeSeekTable || metadata[1][0] != 0x80|BlockTypeCueSheet {
+		t.Fatalf("unexpected metadata blocks")
+	}
+
+	// 29400 samples at intervals of 2205 samples, so every frame
+	// appears twice, except the last which isn't reached
+	points := metadata[0][4:]
+	if len(points) != 14*seekPointSize {
+		t.Fatalf("%d seek points", len(points)/seekPointSize)
+	}
+	frames := e.Frames()
+	if len(frames) != 8 {
+		t.Fatalf("%d frames", len(frames))
+	}
+	for i, f := range frames[:7] {
+		p := points[i*seekPointSize:]
+		if binary.BigEndian.Uint64(p) != f.Sample || binary.BigEndian.Uint64(p[8:]) != uint64(f.Offset) {
+			t.Errorf("seek point %d doesn't match frame %v", i, f)
+		}
+	}
+	last := points[13*seekPointSize:]
+	if binary.BigEndian.Uint64(last) != placeholderPoint {
+		t.Error("expected a placeholder")
+	}
+	// the points index into the frames
+	for _, f := range frames {
+		off := len(encoded) - int(e.written-e.audioStart) + int(f.Offset)
+		if binary.BigEndian.Uint16(encoded[off:]) != 0xFFF8 {
+			t.Errorf("no frame at offset %d", f.Offset)
+		}
+	}
+}
+
+func TestCloseWriter(t *testing.T) {
+	samples := testSignal(BlockSize)
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "track.flac"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := NewEncoder(f, Options{CloseWriter: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Write(pcm(samples)); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("writer wasn't closed: %v", err)
+	}
+
+	encoded, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, decoded, _ := decode(t, encoded)
+	if !slices.Equal(decoded, samples) {
+		t.Fatal("decoded samples don't match")
+	}
+	// filled in before f was closed
+	total := binary.BigEndian.Uint64(info[10:]) & (1<<36 - 1)
+	if total != uint64(len(samples)/2) {
+		t.Errorf("total samples %d", total)
+	}
+}
+
+// fixtureSignal returns a signal computed with integers only, so the
+// fixture encodes the same on every platform.
+func fixtureSignal(n int) []int16 {
+	s := make([]int16, n*2)
+	noise := uint32(1)
+	for i := range n {
+		noise = noise*1664525 + 1013904223
+		s[i*2] = int16(max(i%400, 400-i%400)*80 - 24000)
+		s[i*2+1] = s[i*2]/2 + int16(noise>>24) - 128
+	}
+	// a stretch of silence in both channels
+	for i := 2 * BlockSize; i < 3*BlockSize; i++ {
+		s[i] = 0
+	}
+	return s
+}
+
+// encodeFixture encodes the stream stored in testdata/fixture.flac.
+func encodeFixture(t *testing.T) []byte {
+	samples := fixtureSignal(2*BlockSize + 1000)
+	f, err := os.Create(filepath.Join(t.TempDir(), "fixture.flac"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	e, err := NewEncoder(f, Options{
+		TotalSamples: uint64(len(samples) / 2),
+		SeekInterval: 100 * time.Millisecond,
+		Metadata:     [][]byte{{BlockTypePadding, 0, 0, 8, 0, 0, 0, 0, 0, 0, 0, 0}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Write(pcm(samples)); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, decoded, _ := decode(t, encoded); !slices.Equal(decoded, samples) {
+		t.Fatal("decoded samples don't match")
+	}
+	return encoded
+}
+
+// TestReferenceDecoder checks the fixture with the reference decoder,
+// if it's installed, and that the encoder still writes the fixture.
+// If the encoder's output changes on purpose, the fixture must be
+// written again and checked with flac -t.
+func TestReferenceDecoder(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("testdata", "fixture.flac"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(encodeFixture(t), fixture) {
+		t.Error("encoder output doesn't match testdata/fixture.flac")
+	}
+
+	flac, err := exec.LookPath("flac")
+	if err != nil {
+		t.Skip("flac isn't installed")
+	}
+	out, err := exec.Command(flac, "-t", "-s", filepath.Join("testdata", "fixture.flac")).CombinedOutput()
+	if err != nil {
+		t.Errorf("flac -t: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,130 @@
+package flacenc
+
+import (
+	"encoding/binary"
+
+	"github.com/rabidaudio/audiocd"
+)
+
+// Channel assignments of a frame
+const (
+	channelsIndependent = 0x1
+	channelsLeftSide    = 0x8
+	channelsRightSide   = 0x9
+	channelsMidSide     = 0xA
+)
+
+const (
+	left = iota
+	right
+	mid
+	side
+)
+
+// encodeFrame encodes a block of interleaved PCM data in host
+// byte order and writes it out.
+func (e *Encoder) encodeFrame(p []byte) error {
+	n := len(p) / (audiocd.Channels * audiocd.BytesPerSample)
+	for i := range n {
+		l := int32(int16(binary.NativeEndian.Uint16(p[i*4:])))
+		r := int32(int16(binary.NativeEndian.Uint16(p[i*4+2:])))
+		e.channels[left][i] = l
+		e.channels[right][i] = r
+		e.channels[mid][i] = (l + r) >> 1
+		e.channels[side][i] = l - r
+	}
+	// the MD5 is of the samples in little-endian order
+	if binary.NativeEndian.Uint16([]byte{1, 0}) == 1 {
+		e.md5.Write(p)
+	} else {
+		le := make([]byte, len(p))
+		for i := 0; i+1 < len(p); i += 2 {
+			le[i], le[i+1] = p[i+1], p[i]
+		}
+		e.md5.Write(le)
+	}
+
+	var sf [4]*subframe
+	for c := range sf {
+		bps := uint(audiocd.BitsPerSample)
+		if c == side {
+			bps++
+		}
+		sf[c] = newSubframe(e.channels[c][:n], bps, e.residual[c])
+	}
+	assignment, a, b := byte(channelsIndependent), sf[left], sf[right]
+	for _, alt := range []struct {
+		assignment byte
+		a, b       *subframe
+	}{
+		{channelsLeftSide, sf[left], sf[side]},
+		{channelsRightSide, sf[side], sf[right]},
+		{channelsMidSide, sf[mid], sf[side]},
+	} {
+		if alt.a.size+alt.b.size < a.size+b.size {
+			assignment, a, b = alt.assignment, alt.a, alt.b
+		}
+	}
+
+	bw := &bitWriter{}
+	e.frameHeader(bw, n, assignment)
+	a.encode(bw)
+	b.encode(bw)
+	bw.align()
+	frame := bw.bytes()
+	frame = binary.BigEndian.AppendUint16(frame, crc16(frame))
+
+	e.frames = append(e.frames, Frame{Sample: e.total, Offset: e.written - e.audioStart})
+	if err := e.write(frame); err != nil {
+		return err
+	}
+	e.total += uint64(n)
+	if e.minFrame == 0 || len(frame) < e.minFrame {
+		e.minFrame = len(frame)
+	}
+	e.maxFrame = max(e.maxFrame, len(frame))
+	return nil
+}
+
+// frameHeader writes the header of a frame of n samples.
+func (e *Encoder) frameHeader(bw *bitWriter, n int, assignment byte) {
+	bw.write(0xFFF8, 16) // sync code, fixed block size
+	switch {
+	case n == BlockSize:
+		bw.write(0xC, 4)
+	case n <= 256:
+		bw.write(0x6, 4) // 8-bit size at the end of the header
+	default:
+		bw.write(0x7, 4) // 16-bit size at the end of the header
+	}
+	bw.write(0x9, 4) // 44.1kHz
+	bw.write(uint64(assignment), 4)
+	bw.write(0x4, 3) // 16 bits per sample
+	bw.write(0, 1)
+	writeUTF8(bw, uint64(len(e.frames)))
+	switch {
+	case n == BlockSize:
+	case n <= 256:
+		bw.write(uint64(n-1), 8)
+	default:
+		bw.write(uint64(n-1), 16)
+	}
+	bw.write(uint64(crc8(bw.bytes())), 8)
+}
+
+// writeUTF8 writes a frame number in the extended UTF-8 coding FLAC uses.
+func writeUTF8(bw *bitWriter, v uint64) {
+	if v < 0x80 {
+		bw.write(v, 8)
+		return
+	}
+	// the number of continuation bytes, each holding 6 bits
+	n := 1
+	for v>>(6*n) >= 1<<(6-n) {
+		n++
+	}
+	bw.write(0xFF<<(7-n)&0xFF|v>>(6*n), 8)
+	for i := n - 1; i >= 0; i-- {
+		bw.write(0x80|v>>(6*i)&0x3F, 8)
+	}
+}
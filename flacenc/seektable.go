@@ -0,0 +1,63 @@
+package flacenc
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/rabidaudio/audiocd"
+)
+
+// seekPointSize is the size of a seek point in a SEEKTABLE block.
+const seekPointSize = 18
+
+// placeholderPoint marks an unused seek point.
+const placeholderPoint = 1<<64 - 1
+
+// seekTable lays out a SEEKTABLE block with evenly spaced points.
+type seekTable struct {
+	points   int
+	interval uint64 // in samples
+	last     bool   // whether it's the last metadata block
+}
+
+func newSeekTable(total uint64, interval time.Duration) seekTable {
+	samples := max(uint64(interval.Seconds()*audiocd.SampleRate), 1)
+	return seekTable{
+		points:   int((total + samples - 1) / samples),
+		interval: samples,
+	}
+}
+
+// placeholder returns the block with every point unused.
+func (st seekTable) placeholder() []byte {
+	return st.encode(nil, 0)
+}
+
+// encode returns the block with the point at each interval set to the
+// frame containing it. Points which would repeat a frame are left as
+// placeholders at the end.
+func (st seekTable) encode(frames []Frame, total uint64) []byte {
+	b := metadataHeader(BlockTypeSeekTable, st.points*seekPointSize, st.last)
+	written := 0
+	last := -1
+	for i := range st.points {
+		f := int(uint64(i) * st.interval / BlockSize)
+		if f >= len(frames) || f == last {
+			continue
+		}
+		last = f
+		end := total
+		if f+1 < len(frames) {
+			end = frames[f+1].Sample
+		}
+		b = binary.BigEndian.AppendUint64(b, frames[f].Sample)
+		b = binary.BigEndian.AppendUint64(b, uint64(frames[f].Offset))
+		b = binary.BigEndian.AppendUint16(b, uint16(end-frames[f].Sample))
+		written++
+	}
+	for range st.points - written {
+		b = binary.BigEndian.AppendUint64(b, placeholderPoint)
+		b = append(b, make([]byte, seekPointSize-8)...)
+	}
+	return b
+}
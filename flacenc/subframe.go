@@ -0,0 +1,171 @@
+package flacenc
+
+import "math/bits"
+
+// maxFixedOrder is the highest order of the fixed predictors.
+const maxFixedOrder = 4
+
+// maxRiceParam is the highest Rice parameter with 4-bit parameters;
+// 15 is the escape code.
+const maxRiceParam = 14
+
+// subframe is the encoding chosen for one channel of a frame.
+type subframe struct {
+	samples  []int32
+	bps      uint // bits per sample, 17 for the side channel
+	kind     int  // subframeConstant, subframeVerbatim or subframeFixed
+	order    int
+	residual []int32
+	rice     uint
+	size     int // in bits
+}
+
+const (
+	subframeConstant = iota
+	subframeVerbatim
+	subframeFixed
+)
+
+// newSubframe picks the smallest encoding of the samples.
+func newSubframe(samples []int32, bps uint, residual []int32) *subframe {
+	sf := &subframe{samples: samples, bps: bps}
+	if constant(samples) {
+		sf.kind = subframeConstant
+		sf.size = 8 + int(bps)
+		return sf
+	}
+
+	sf.kind = subframeVerbatim
+	sf.size = 8 + len(samples)*int(bps)
+
+	order := bestOrder(samples)
+	if order >= len(samples) {
+		return sf
+	}
+	r := fixedResidual(samples, order, residual[:len(samples)-order])
+	rice, size := riceCost(r)
+	size += 8 + order*int(bps) + 2 + 4 + 4
+	if size < sf.size {
+		sf.kind = subframeFixed
+		sf.order = order
+		sf.residual = r
+		sf.rice = rice
+		sf.size = size
+	}
+	return sf
+}
+
+func constant(samples []int32) bool {
+	for _, s := range samples[1:] {
+		if s != samples[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// bestOrder estimates which fixed predictor leaves the smallest
+// residual, by the sum of its absolute values.
+func bestOrder(samples []int32) int {
+	var sums [maxFixedOrder + 1]uint64
+	for i := maxFixedOrder; i < len(samples); i++ {
+		for order := range maxFixedOrder + 1 {
+			sums[order] += uint64(abs(predict(samples, i, order)))
+		}
+	}
+	best := 0
+	for order := range sums {
+		if sums[order] < sums[best] {
+			best = order
+		}
+	}
+	return best
+}
+
+// predict returns the residual of sample i with the fixed
+// predictor of the given order.
+func predict(x []int32, i, order int) int32 {
+	switch order {
+	case 0:
+		return x[i]
+	case 1:
+		return x[i] - x[i-1]
+	case 2:
+		return x[i] - 2*x[i-1] + x[i-2]
+	case 3:
+		return x[i] - 3*x[i-1] + 3*x[i-2] - x[i-3]
+	default:
+		return x[i] - 4*x[i-1] + 6*x[i-2] - 4*x[i-3] + x[i-4]
+	}
+}
+
+func fixedResidual(samples []int32, order int, r []int32) []int32 {
+	for i := order; i < len(samples); i++ {
+		r[i-order] = predict(samples, i, order)
+	}
+	return r
+}
+
+func abs(v int32) uint32 {
+	if v < 0 {
+		return uint32(-v)
+	}
+	return uint32(v)
+}
+
+// zigzag folds signed residuals into unsigned values for Rice coding.
+func zigzag(v int32) uint32 {
+	return uint32(v<<1) ^ uint32(v>>31)
+}
+
+// riceCost picks the Rice parameter for the residual and returns
+// it along with the size of the coded residual in bits.
+func riceCost(r []int32) (uint, int) {
+	var sum uint64
+	for _, v := range r {
+		sum += uint64(zigzag(v))
+	}
+	// the best parameter is close to log2 of the mean
+	guess := 0
+	if mean := sum / uint64(max(len(r), 1)); mean > 0 {
+		guess = bits.Len64(mean) - 1
+	}
+	best, bestSize := uint(0), -1
+	for k := max(guess-1, 0); k <= min(guess+1, maxRiceParam); k++ {
+		size := len(r) * (k + 1)
+		for _, v := range r {
+			size += int(zigzag(v) >> k)
+		}
+		if bestSize < 0 || size < bestSize {
+			best, bestSize = uint(k), size
+		}
+	}
+	return best, bestSize
+}
+
+// encode appends the subframe to bw.
+func (sf *subframe) encode(bw *bitWriter) {
+	switch sf.kind {
+	case subframeConstant:
+		bw.write(0x00, 8)
+		bw.writeSigned(sf.samples[0], sf.bps)
+	case subframeVerbatim:
+		bw.write(0x02, 8)
+		for _, s := range sf.samples {
+			bw.writeSigned(s, sf.bps)
+		}
+	case subframeFixed:
+		bw.write(uint64(0x08|sf.order)<<1, 8)
+		for _, s := range sf.samples[:sf.order] {
+			bw.writeSigned(s, sf.bps)
+		}
+		bw.write(0, 2) // 4-bit Rice parameters
+		bw.write(0, 4) // a single partition
+		bw.write(uint64(sf.rice), 4)
+		for _, v := range sf.residual {
+			u := zigzag(v)
+			bw.writeUnary(u >> sf.rice)
+			bw.write(uint64(u), sf.rice)
+		}
+	}
+}
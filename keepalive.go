@@ -0,0 +1,105 @@
+package audiocd
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// keepAlive reads from the drive in the background while the reader
+// is idle, so the disc doesn't spin down.
+type keepAlive struct {
+	interval time.Duration
+	lastRead atomic.Int64 // the time of the last read, in Unix nanoseconds
+	sector   atomic.Int64 // the sector after the last read
+	busy     sync.Mutex   // held while the reader uses the drive
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// SetKeepAlive keeps the disc spinning during slow reads, e.g. while
+// playing audio in real time, by reading a sector whenever no read
+// has happened for the interval. Without it, many drives spin down
+// after a few seconds idle and then stall the next read while they
+// spin back up. An interval of 0 stops it, which is the default.
+//
+// This requires a drive which supports MMC commands.
+func (cd *AudioCD) SetKeepAlive(interval time.Duration) error {
+	if !cd.IsOpen() {
+		return os.ErrClosed
+	}
+	cd.keepAlive.close()
+	cd.keepAlive = nil
+	if interval <= 0 {
+		return nil
+	}
+	ka := &keepAlive{interval: interval, stop: make(chan struct{}), done: make(chan struct{})}
+	ka.read(cd.driveSector)
+	go ka.run(cd.drive, interval)
+	cd.keepAlive = ka
+	return nil
+}
+
+// run touches the drive until stopped. It shares the handle on the
+// drive with the reader, so it skips its turn while the reader is
+// using the drive, e.g. in a long or stalled read.
+func (ka *keepAlive) run(drive unsafe.Pointer, interval time.Duration) {
+	defer close(ka.done)
+	cd := &AudioCD{drive: drive}
+	buf := make([]byte, BytesPerSector)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ka.stop:
+			return
+		case <-ticker.C:
+		}
+		if time.Since(time.Unix(0, ka.lastRead.Load())) < interval {
+			continue
+		}
+		if !ka.busy.TryLock() {
+			continue
+		}
+		// the sector about to be read, so it may be cached too
+		sector := int(ka.sector.Load())
+		if cd.readCD(sector, 1, readCDUserData, 0, buf) != nil {
+			// e.g. the end of the disc; the start will do
+			cd.readCD(0, 1, readCDUserData, 0, buf)
+		}
+		ka.busy.Unlock()
+	}
+}
+
+// lock keeps the keep-alive off the drive while the reader uses it.
+func (ka *keepAlive) lock() {
+	if ka != nil {
+		ka.busy.Lock()
+	}
+}
+
+func (ka *keepAlive) unlock() {
+	if ka != nil {
+		ka.busy.Unlock()
+	}
+}
+
+// read records a read up to the sector.
+func (ka *keepAlive) read(sector int) {
+	if ka == nil {
+		return
+	}
+	ka.lastRead.Store(time.Now().UnixNano())
+	ka.sector.Store(int64(sector))
+}
+
+// close stops the keep-alive and waits for it to finish with the drive.
+func (ka *keepAlive) close() {
+	if ka == nil {
+		return
+	}
+	close(ka.stop)
+	<-ka.done
+}
@@ -0,0 +1,124 @@
+package audiocd
+
+import (
+	"math"
+	"slices"
+)
+
+// TrackLevels are the peak and RMS levels of the audio of a track,
+// as a fraction of full scale.
+type TrackLevels struct {
+	TrackNum int     `json:"trackNum"`
+	Peak     float64 `json:"peak"`    // the highest absolute sample value
+	RMS      float64 `json:"rms"`     // the root mean square of the samples
+	Samples  int64   `json:"samples"` // the number of samples measured
+}
+
+// PeakDB returns the peak level in dBFS.
+func (tl TrackLevels) PeakDB() float64 {
+	return 20 * math.Log10(tl.Peak)
+}
+
+// RMSDB returns the RMS level in dBFS.
+func (tl TrackLevels) RMSDB() float64 {
+	return 20 * math.Log10(tl.RMS)
+}
+
+// LevelReport holds the levels measured by a level scan.
+// See [*AudioCD.SetLevelScan].
+type LevelReport struct {
+	Tracks []TrackLevels `json:"tracks"` // the tracks read, in order
+	Total  TrackLevels   `json:"total"`  // all of the audio read, with TrackNum 0
+}
+
+// Track returns the levels of the given track, if it was read.
+func (lr LevelReport) Track(n int) (TrackLevels, bool) {
+	for _, tl := range lr.Tracks {
+		if tl.TrackNum == n {
+			return tl, true
+		}
+	}
+	return TrackLevels{}, false
+}
+
+// SetLevelScan enables or disables measuring the peak and RMS levels
+// of each track as audio is returned by Read and WriteTo. Enabling it
+// starts a new scan. Audio which is read more than once, e.g. after
+// seeking backwards, is counted again. The levels are measured after
+// de-emphasis and the sample processor.
+func (cd *AudioCD) SetLevelScan(enabled bool) {
+	if enabled {
+		cd.levels = &levelMeter{}
+	} else {
+		cd.levels = nil
+	}
+}
+
+// LevelReport returns the levels measured since the level scan was
+// enabled. It is empty if the scan isn't enabled.
+func (cd *AudioCD) LevelReport() LevelReport {
+	if cd.levels == nil {
+		return LevelReport{}
+	}
+	return cd.levels.report()
+}
+
+// levelMeter accumulates the levels of each track.
+type levelMeter struct {
+	tracks []levelSum
+	total  levelSum
+}
+
+type levelSum struct {
+	track   int
+	peak    int // absolute, so -32768 fits
+	squares float64
+	samples int64
+}
+
+func (ls *levelSum) add(s []int16) {
+	for _, v := range s {
+		a := int(v)
+		if a < 0 {
+			a = -a
+		}
+		ls.peak = max(ls.peak, a)
+		ls.squares += float64(a) * float64(a)
+	}
+	ls.samples += int64(len(s))
+}
+
+func (ls levelSum) levels() TrackLevels {
+	tl := TrackLevels{
+		TrackNum: ls.track,
+		Peak:     float64(ls.peak) / sampleScale,
+		Samples:  ls.samples,
+	}
+	if ls.samples > 0 {
+		tl.RMS = math.Sqrt(ls.squares/float64(ls.samples)) / sampleScale
+	}
+	return tl
+}
+
+// measure adds the samples of whole sectors, starting at the given sector.
+func (lm *levelMeter) measure(toc []TrackPosition, sector int, samples []int16) {
+	const n = SamplesPerSector * Channels
+	for i := 0; i+n <= len(samples); i += n {
+		track := trackAtSector(toc, sector+i/n)
+		j := slices.IndexFunc(lm.tracks, func(ls levelSum) bool { return ls.track == track })
+		if j < 0 {
+			lm.tracks = append(lm.tracks, levelSum{track: track})
+			j = len(lm.tracks) - 1
+		}
+		lm.tracks[j].add(samples[i : i+n])
+		lm.total.add(samples[i : i+n])
+	}
+}
+
+func (lm *levelMeter) report() LevelReport {
+	lr := LevelReport{Total: lm.total.levels()}
+	for _, ls := range lm.tracks {
+		lr.Tracks = append(lr.Tracks, ls.levels())
+	}
+	return lr
+}
@@ -0,0 +1,42 @@
+package audiocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelMeter(t *testing.T) {
+	toc := []TrackPosition{
+		{TrackNum: 1, StartSector: 0, LengthSectors: 1},
+		{TrackNum: 2, StartSector: 1, LengthSectors: 1},
+	}
+	samples := make([]int16, 2*SamplesPerSector*Channels)
+	for i := range SamplesPerSector * Channels {
+		samples[i] = 16384
+		if i%2 == 1 {
+			samples[i] = -16384
+		}
+	}
+	samples[len(samples)-1] = -32768
+
+	lm := levelMeter{}
+	lm.measure(toc, 0, samples)
+	lr := lm.report()
+
+	assert.Len(t, lr.Tracks, 2)
+	t1, ok := lr.Track(1)
+	assert.True(t, ok)
+	assert.Equal(t, 0.5, t1.Peak)
+	assert.Equal(t, 0.5, t1.RMS)
+	assert.InDelta(t, -6.02, t1.PeakDB(), 0.01)
+	assert.Equal(t, int64(SamplesPerSector*Channels), t1.Samples)
+
+	t2, _ := lr.Track(2)
+	assert.Equal(t, 1.0, t2.Peak)
+	assert.Equal(t, 1.0, lr.Total.Peak)
+	assert.Equal(t, int64(len(samples)), lr.Total.Samples)
+
+	_, ok = lr.Track(3)
+	assert.False(t, ok)
+}
@@ -0,0 +1,215 @@
+package audiocd
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Dir is the data transfer direction of an MMC command.
+type Dir int
+
+const (
+	DirNone Dir = 0 // no data transfer
+	DirIn   Dir = 1 // data from the drive
+	DirOut  Dir = 2 // data to the drive
+)
+
+// mmcTimeout is the default time to wait for an MMC command.
+const mmcTimeout = 10 * time.Second
+
+// MMC issues a raw MMC command to the drive, for commands this package
+// doesn't provide. cdb is the command descriptor block, and buf holds
+// the data transferred in direction dir. If timeout is 0, a default of
+// 10 seconds is used. If the drive fails the command, the error is a
+// [*SenseError].
+//
+// Commands which change the drive's state, e.g. its position or mode
+// pages, can confuse later reads.
+func (cd *AudioCD) MMC(cdb []byte, dir Dir, buf []byte, timeout time.Duration) error {
+	if !cd.IsOpen() {
+		return os.ErrClosed
+	}
+	if len(cdb) < 6 || len(cdb) > 16 {
+		return fmt.Errorf("audiocd: mmc command must be 6 to 16 bytes, not %d", len(cdb))
+	}
+	if dir != DirNone && len(buf) == 0 {
+		return fmt.Errorf("audiocd: mmc command 0x%02x needs a buffer", cdb[0])
+	}
+	if timeout <= 0 {
+		timeout = mmcTimeout
+	}
+	return mmcCommand(cd, cdb, dir, buf, timeout)
+}
+
+// mmcCommand issues an MMC command to the drive, once a stalled read
+// or the keep-alive has returned it.
+func mmcCommand(cd *AudioCD, cdb []byte, dir Dir, buf []byte, timeout time.Duration) error {
+	err := cd.waitStalled(context.Background())
+	if err != nil {
+		return err
+	}
+	cd.keepAlive.lock()
+	defer cd.keepAlive.unlock()
+	return mmcExec(cd, cdb, dir, buf, timeout)
+}
+
+// MMC operation codes, see the SCSI Multimedia Commands spec.
+const (
+	mmcTestUnitReady    byte = 0x00
+	mmcInquiry          byte = 0x12
+	mmcStartStopUnit    byte = 0x1B
+	mmcPreventRemoval   byte = 0x1E // PREVENT ALLOW MEDIUM REMOVAL
+	mmcGetConfiguration byte = 0x46
+	mmcReadSubchannel   byte = 0x42
+	mmcReadTOC          byte = 0x43
+	mmcPlayAudioMSF     byte = 0x47
+	mmcPauseResume      byte = 0x4B
+	mmcStopPlayScan     byte = 0x4E
+	mmcReadCD           byte = 0xBE
+	mmcModeSelect       byte = 0x55
+	mmcModeSense        byte = 0x5A
+	mmcLoadUnload       byte = 0xA6 // LOAD/UNLOAD MEDIUM
+	mmcMechanismStatus  byte = 0xBD
+)
+
+// mode page codes
+const (
+	modePageCaching      byte = 0x08
+	modePageAudioControl byte = 0x0E // CD audio control, the playback volume
+	modePageCapabilities byte = 0x2A // CD/DVD capabilities and mechanical status
+)
+
+// READ SUB-CHANNEL data formats
+const (
+	subchannelFormatPosition byte = 0x01
+	subchannelFormatMCN      byte = 0x02
+	subchannelFormatISRC     byte = 0x03
+)
+
+// READ CD main channel data selection
+const (
+	readCDUserData   byte = 0x10 // the 2352 bytes of audio
+	readCDC2Pointers byte = 0x02 // a bit for each byte flagging C2 errors
+)
+
+// READ CD sub-channel data selection
+const (
+	subchannelSelectRaw byte = 0x01 // raw interleaved P-W, 96 bytes per sector
+)
+
+// subchannelSize is the size of the raw P-W subchannel data of one sector.
+const subchannelSize = 96
+
+// READ TOC/PMA/ATIP response formats
+const (
+	tocFormatFull   byte = 0x02
+	tocFormatCDText byte = 0x05
+)
+
+// readTOC issues READ TOC/PMA/ATIP with the given format and returns
+// the response, including the 4-byte header.
+func (cd *AudioCD) readTOC(format byte, track byte) ([]byte, error) {
+	cdb := []byte{mmcReadTOC, 0x02, format, 0, 0, 0, track, 0, 4, 0}
+	header := make([]byte, 4)
+	err := mmcCommand(cd, cdb, DirIn, header, mmcTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	n := int(binary.BigEndian.Uint16(header)) + 2
+	if n <= 4 {
+		return header, nil
+	}
+	data := make([]byte, n)
+	binary.BigEndian.PutUint16(cdb[7:], uint16(n))
+	err = mmcCommand(cd, cdb, DirIn, data, mmcTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// readSubchannel issues READ SUB-CHANNEL for the Q subchannel data
+// in the given format.
+func (cd *AudioCD) readSubchannel(format byte, track byte) ([]byte, error) {
+	data := make([]byte, 24)
+	cdb := []byte{mmcReadSubchannel, 0, 0x40, format, 0, 0, track, 0, byte(len(data)), 0}
+	err := mmcCommand(cd, cdb, DirIn, data, mmcTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// readCD issues READ CD for CD-DA sectors starting at the given
+// address, with the selected main channel fields followed by the
+// selected sub-channel data for each sector. buf must hold all of it.
+func (cd *AudioCD) readCD(sector, count int, fields, subchannel byte, buf []byte) error {
+	cdb := make([]byte, 12)
+	cdb[0] = mmcReadCD
+	cdb[1] = 0x04 // expected sector type: CD-DA
+	binary.BigEndian.PutUint32(cdb[2:], uint32(sector))
+	cdb[6], cdb[7], cdb[8] = byte(count>>16), byte(count>>8), byte(count)
+	cdb[9] = fields
+	cdb[10] = subchannel
+	return mmcCommand(cd, cdb, DirIn, buf, mmcTimeout)
+}
+
+// currentProfile issues GET CONFIGURATION and returns the current
+// profile, which identifies the kind of disc in the drive.
+func (cd *AudioCD) currentProfile() (uint16, error) {
+	header := make([]byte, 8)
+	cdb := []byte{mmcGetConfiguration, 0x02, 0, 0, 0, 0, 0, 0, byte(len(header)), 0}
+	err := mmcCommand(cd, cdb, DirIn, header, mmcTimeout)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint16(header[6:]), nil
+}
+
+// feature issues GET CONFIGURATION for a single feature and returns
+// its descriptor, or nil if the drive doesn't have the feature.
+func (cd *AudioCD) feature(code uint16) ([]byte, error) {
+	data := make([]byte, 64)
+	cdb := []byte{mmcGetConfiguration, 0x02, byte(code >> 8), byte(code), 0, 0, 0, 0, byte(len(data)), 0}
+	err := mmcCommand(cd, cdb, DirIn, data, mmcTimeout)
+	if err != nil {
+		return nil, err
+	}
+	n := min(int(binary.BigEndian.Uint32(data))+4, len(data))
+	if n < 12 || binary.BigEndian.Uint16(data[8:]) != code {
+		return nil, nil
+	}
+	return data[8:min(12+int(data[11]), n)], nil
+}
+
+// modeSense issues MODE SENSE (10) for the current values of a mode
+// page, without block descriptors. It returns the page, starting
+// with its code.
+func (cd *AudioCD) modeSense(page byte) ([]byte, error) {
+	data := make([]byte, 256)
+	cdb := []byte{mmcModeSense, 0x08, page, 0, 0, 0, 0, byte(len(data) >> 8), byte(len(data)), 0}
+	err := mmcCommand(cd, cdb, DirIn, data, mmcTimeout)
+	if err != nil {
+		return nil, err
+	}
+	n := min(int(binary.BigEndian.Uint16(data))+2, len(data))
+	offset := 8 + int(binary.BigEndian.Uint16(data[6:]))
+	if n < offset+2 || n < offset+2+int(data[offset+1]) {
+		return nil, fmt.Errorf("audiocd: short mode page 0x%02x", page)
+	}
+	return data[offset : offset+2+int(data[offset+1])], nil
+}
+
+// modeSelect issues MODE SELECT (10) to set a mode page, as returned
+// by modeSense.
+func (cd *AudioCD) modeSelect(page []byte) error {
+	data := make([]byte, 8+len(page))
+	copy(data[8:], page)
+	data[8] &^= 0x80 // the parameters saveable bit is reserved
+	cdb := []byte{mmcModeSelect, 0x10, 0, 0, 0, 0, 0, byte(len(data) >> 8), byte(len(data)), 0}
+	return mmcCommand(cd, cdb, DirOut, data, mmcTimeout)
+}
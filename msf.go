@@ -0,0 +1,42 @@
+package audiocd
+
+import (
+	"fmt"
+	"time"
+)
+
+// MSF is a length or position on the disc in minutes, seconds and
+// frames (sectors, 1/75th of a second), the unit used by the Red Book
+// and cue sheets, e.g. "04:23:10".
+//
+// MSF values here count from sector 0. Absolute addresses on the disc
+// itself include the two second pregap before sector 0.
+type MSF struct {
+	Minutes int
+	Seconds int
+	Frames  int
+}
+
+// SectorsToMSF converts a number of sectors to minutes, seconds and frames.
+func SectorsToMSF(sectors int) MSF {
+	return MSF{
+		Minutes: sectors / (60 * SectorsPerSecond),
+		Seconds: (sectors / SectorsPerSecond) % 60,
+		Frames:  sectors % SectorsPerSecond,
+	}
+}
+
+// Sectors converts the time back to a number of sectors.
+func (m MSF) Sectors() int {
+	return (m.Minutes*60+m.Seconds)*SectorsPerSecond + m.Frames
+}
+
+// String formats the time as "MM:SS:FF".
+func (m MSF) String() string {
+	return fmt.Sprintf("%02d:%02d:%02d", m.Minutes, m.Seconds, m.Frames)
+}
+
+// SectorsToDuration converts a number of sectors to a playing time.
+func SectorsToDuration(sectors int) time.Duration {
+	return time.Duration(sectors) * time.Second / SectorsPerSecond
+}
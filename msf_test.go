@@ -0,0 +1,43 @@
+package audiocd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMSF(t *testing.T) {
+	msf := SectorsToMSF(19735)
+	assert.Equal(t, MSF{Minutes: 4, Seconds: 23, Frames: 10}, msf)
+	assert.Equal(t, "04:23:10", msf.String())
+	assert.Equal(t, 19735, msf.Sectors())
+
+	track := TrackPosition{TrackNum: 2, StartSector: 6290, LengthSectors: 17021}
+	assert.Equal(t, 83*time.Second+866666666*time.Nanosecond, track.StartTime())
+	assert.Equal(t, "03:46:71", track.LengthMSF().String())
+	assert.Equal(t, SectorsToDuration(17021), track.Duration())
+	assert.Equal(t, "01:23:65", track.StartMSF().String())
+}
+
+func TestTimeToOffset(t *testing.T) {
+	assert.Equal(t, int64(75*BytesPerSector), timeToOffset(time.Second))
+	assert.Equal(t, int64(4), timeToOffset(23*time.Microsecond)) // 1.01 samples
+	assert.Equal(t, int64(0), timeToOffset(11*time.Microsecond)) // 0.49 samples
+}
+
+func TestPositionAt(t *testing.T) {
+	toc := []TrackPosition{
+		{TrackNum: 1, StartSector: 0, LengthSectors: 6290},
+		{TrackNum: 2, StartSector: 6290, LengthSectors: 17021},
+	}
+	pos := positionAt(toc, 6300*BytesPerSector+8)
+	assert.Equal(t, Position{
+		Offset: 6300*BytesPerSector + 8,
+		Sector: 6300,
+		Sample: 6300*SamplesPerSector + 2,
+		Time:   84*time.Second + 45351*time.Nanosecond,
+		MSF:    MSF{Minutes: 1, Seconds: 24, Frames: 0},
+		Track:  2,
+	}, pos)
+}
@@ -0,0 +1,270 @@
+// Package musicbrainz looks up release metadata for an audio CD
+// from the [MusicBrainz] web service, using the disc's table of contents.
+//
+// Example:
+//
+//	client := musicbrainz.Client{UserAgent: "MyRipper/1.0 ( me@example.com )"}
+//	releases, err := client.LookupTOC(ctx, cd.TOC())
+//
+// [MusicBrainz]: https://musicbrainz.org/doc/MusicBrainz_API
+package musicbrainz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rabidaudio/audiocd"
+)
+
+// DefaultBaseURL is the location of the MusicBrainz web service.
+const DefaultBaseURL = "https://musicbrainz.org/ws/2/"
+
+// DefaultCoverArtURL is the location of the Cover Art Archive.
+const DefaultCoverArtURL = "https://coverartarchive.org/"
+
+var (
+	// ErrNotFound is returned when no release matches the disc.
+	ErrNotFound = errors.New("musicbrainz: no matching release")
+	// ErrNoCoverArt is returned when the release has no front cover.
+	ErrNoCoverArt = errors.New("musicbrainz: release has no front cover art")
+)
+
+// Client queries the MusicBrainz web service. The zero value is ready
+// to use, but MusicBrainz asks that applications identify themselves
+// with a meaningful UserAgent.
+type Client struct {
+	HTTPClient  *http.Client // the client to make requests with. If nil, http.DefaultClient is used
+	BaseURL     string       // the web service root. If empty, DefaultBaseURL is used
+	CoverArtURL string       // the Cover Art Archive root. If empty, DefaultCoverArtURL is used
+	UserAgent   string       // e.g. "MyRipper/1.0 ( me@example.com )"
+}
+
+// Release is a MusicBrainz release containing the disc.
+type Release struct {
+	ID         string // the release MBID
+	Title      string
+	Artist     string
+	Date       string // release date, e.g. "1999-03-02", "1999" or empty
+	Country    string
+	Barcode    string
+	DiscNumber int // the position of the disc within the release
+	DiscCount  int // the number of discs in the release
+	Tracks     []Track
+}
+
+// Track is a track on the disc.
+type Track struct {
+	Number      int // track number on the disc, starting at 1
+	Title       string
+	Artist      string
+	Length      time.Duration
+	RecordingID string // the recording MBID
+}
+
+// LookupTOC finds the releases matching the disc with the given table
+// of contents. Releases with the exact disc id are returned if any are
+// known, otherwise MusicBrainz falls back to a fuzzy match on the
+// track lengths.
+//
+// Returns [ErrNotFound] if there are no matches.
+func (c *Client) LookupTOC(ctx context.Context, toc []audiocd.TrackPosition) ([]Release, error) {
+	discID := audiocd.MusicBrainzDiscID(toc)
+	if discID == "" {
+		return nil, fmt.Errorf("musicbrainz: empty table of contents")
+	}
+
+	// MusicBrainz expects literal + separators
+	query := fmt.Sprintf("toc=%s&inc=artist-credits+recordings&fmt=json",
+		strings.ReplaceAll(audiocd.MusicBrainzTOC(toc), " ", "+"))
+
+	var resp discResponse
+	err := c.get(ctx, "discid/"+url.PathEscape(discID)+"?"+query, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Releases) == 0 {
+		return nil, ErrNotFound
+	}
+
+	ntracks := 0
+	for _, t := range toc {
+		if t.IsAudio() {
+			ntracks++
+		}
+	}
+	releases := make([]Release, 0, len(resp.Releases))
+	for _, r := range resp.Releases {
+		releases = append(releases, r.release(discID, ntracks))
+	}
+	return releases, nil
+}
+
+// FrontCover fetches the front cover image of a release from the
+// Cover Art Archive, returning the image data and its MIME type,
+// e.g. "image/jpeg".
+//
+// Returns [ErrNoCoverArt] if the release has no front cover.
+func (c *Client) FrontCover(ctx context.Context, releaseID string) ([]byte, string, error) {
+	base := c.CoverArtURL
+	if base == "" {
+		base = DefaultCoverArtURL
+	}
+	res, err := c.do(ctx, strings.TrimSuffix(base, "/")+"/release/"+url.PathEscape(releaseID)+"/front", "image/*")
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, "", ErrNoCoverArt
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("musicbrainz: unexpected response: %v", res.Status)
+	}
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	mimeType, _, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mimeType, "image/") {
+		mimeType = http.DetectContentType(data)
+	}
+	return data, mimeType, nil
+}
+
+func (c *Client) do(ctx context.Context, target, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	res, err := c.do(ctx, strings.TrimSuffix(base, "/")+"/"+path, "application/json")
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("musicbrainz: unexpected response: %v", res.Status)
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+type discResponse struct {
+	Releases []releaseJSON `json:"releases"`
+}
+
+type artistCredit []struct {
+	Name       string `json:"name"`
+	JoinPhrase string `json:"joinphrase"`
+}
+
+func (ac artistCredit) String() string {
+	s := ""
+	for _, a := range ac {
+		s += a.Name + a.JoinPhrase
+	}
+	return s
+}
+
+type releaseJSON struct {
+	ID           string       `json:"id"`
+	Title        string       `json:"title"`
+	Date         string       `json:"date"`
+	Country      string       `json:"country"`
+	Barcode      string       `json:"barcode"`
+	ArtistCredit artistCredit `json:"artist-credit"`
+	Media        []struct {
+		Position int `json:"position"`
+		Discs    []struct {
+			ID string `json:"id"`
+		} `json:"discs"`
+		Tracks []struct {
+			Position     int          `json:"position"`
+			Title        string       `json:"title"`
+			Length       int          `json:"length"` // milliseconds
+			ArtistCredit artistCredit `json:"artist-credit"`
+			Recording    struct {
+				ID string `json:"id"`
+			} `json:"recording"`
+		} `json:"tracks"`
+	} `json:"media"`
+}
+
+// release converts the JSON response, picking the medium
+// with the disc id, or else the first with the same number of tracks.
+func (r releaseJSON) release(discID string, ntracks int) Release {
+	rel := Release{
+		ID:        r.ID,
+		Title:     r.Title,
+		Artist:    r.ArtistCredit.String(),
+		Date:      r.Date,
+		Country:   r.Country,
+		Barcode:   r.Barcode,
+		DiscCount: len(r.Media),
+	}
+
+	medium := -1
+	for i, m := range r.Media {
+		for _, d := range m.Discs {
+			if d.ID == discID {
+				medium = i
+			}
+		}
+	}
+	if medium < 0 {
+		for i, m := range r.Media {
+			if len(m.Tracks) == ntracks {
+				medium = i
+				break
+			}
+		}
+	}
+	if medium < 0 {
+		return rel
+	}
+
+	m := r.Media[medium]
+	rel.DiscNumber = m.Position
+	for _, t := range m.Tracks {
+		artist := t.ArtistCredit.String()
+		if artist == "" {
+			artist = rel.Artist
+		}
+		rel.Tracks = append(rel.Tracks, Track{
+			Number:      t.Position,
+			Title:       t.Title,
+			Artist:      artist,
+			Length:      time.Duration(t.Length) * time.Millisecond,
+			RecordingID: t.Recording.ID,
+		})
+	}
+	return rel
+}
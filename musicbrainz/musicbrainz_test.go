@@ -0,0 +1,104 @@
+package musicbrainz
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rabidaudio/audiocd"
+	"github.com/stretchr/testify/assert"
+)
+
+var testTOC = []audiocd.TrackPosition{
+	{TrackNum: 1, StartSector: 0, LengthSectors: 6290},
+	{TrackNum: 2, StartSector: 6290, LengthSectors: 17021},
+}
+
+const testResponse = `{
+  "id": "x",
+  "releases": [{
+    "id": "release-mbid",
+    "title": "Album",
+    "date": "1999",
+    "country": "US",
+    "barcode": "0724384497729",
+    "artist-credit": [{"name": "A", "joinphrase": " & "}, {"name": "B", "joinphrase": ""}],
+    "media": [
+      {"position": 1, "discs": [{"id": "other"}], "tracks": []},
+      {"position": 2, "discs": [{"id": "DISCID"}], "tracks": [
+        {"position": 1, "title": "One", "length": 83866, "recording": {"id": "rec1"}},
+        {"position": 2, "title": "Two", "length": 226946, "artist-credit": [{"name": "C"}], "recording": {"id": "rec2"}}
+      ]}
+    ]
+  }]
+}`
+
+func TestLookupTOC(t *testing.T) {
+	discID := audiocd.MusicBrainzDiscID(testTOC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/ws/2/discid/"+discID, r.URL.Path)
+		assert.Equal(t, "toc=1+2+23461+150+6440&inc=artist-credits+recordings&fmt=json", r.URL.RawQuery)
+		assert.Equal(t, "test/1.0", r.UserAgent())
+		w.Write([]byte(strings.ReplaceAll(testResponse, "DISCID", discID)))
+	}))
+	defer srv.Close()
+
+	client := Client{BaseURL: srv.URL + "/ws/2", UserAgent: "test/1.0", HTTPClient: srv.Client()}
+	releases, err := client.LookupTOC(context.Background(), testTOC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []Release{{
+		ID:         "release-mbid",
+		Title:      "Album",
+		Artist:     "A & B",
+		Date:       "1999",
+		Country:    "US",
+		Barcode:    "0724384497729",
+		DiscNumber: 2,
+		DiscCount:  2,
+		Tracks: []Track{
+			{Number: 1, Title: "One", Artist: "A & B", Length: 83866 * time.Millisecond, RecordingID: "rec1"},
+			{Number: 2, Title: "Two", Artist: "C", Length: 226946 * time.Millisecond, RecordingID: "rec2"},
+		},
+	}}, releases)
+}
+
+func TestLookupTOCNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	client := Client{BaseURL: srv.URL}
+	_, err := client.LookupTOC(context.Background(), testTOC)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestFrontCover(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/release/release-mbid/front":
+			http.Redirect(w, r, "/images/1.jpg", http.StatusTemporaryRedirect)
+		case "/images/1.jpg":
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Write([]byte("jpeg data"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client := Client{CoverArtURL: srv.URL}
+	data, mimeType, err := client.FrontCover(context.Background(), "release-mbid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "jpeg data", string(data))
+	assert.Equal(t, "image/jpeg", mimeType)
+
+	_, _, err = client.FrontCover(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrNoCoverArt)
+}
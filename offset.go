@@ -0,0 +1,125 @@
+package audiocd
+
+import (
+	"context"
+	"os"
+)
+
+// The drive's read offset is corrected by reading each sector from the
+// two drive sectors it spans. Audio before the start or after the end
+// of the disc, which most drives can't read, is filled with silence,
+// unless OverreadEdges is set and the drive can read it.
+
+// shiftBytes returns the SampleOffset in bytes.
+func (cd *AudioCD) shiftBytes() int {
+	return cd.SampleOffset * Channels * BytesPerSample
+}
+
+// shiftedSector returns the drive sector containing the start of the
+// given sector after offset correction, limited to the disc.
+func (cd *AudioCD) shiftedSector(sector int) int {
+	if cd.SampleOffset == 0 {
+		return sector
+	}
+	first, _ := splitOffset(sector*BytesPerSector + cd.shiftBytes())
+	return max(min(first, cd.LengthSectors()-1), 0)
+}
+
+// splitOffset splits a byte offset from the start of the disc, which
+// may be negative, into a sector and the offset within it.
+func splitOffset(offset int) (sector, within int) {
+	sector = offset / BytesPerSector
+	if offset%BytesPerSector < 0 {
+		sector--
+	}
+	return sector, offset - sector*BytesPerSector
+}
+
+// readShifted reads the sector at the read cursor into p, with the
+// SampleOffset applied.
+func (cd *AudioCD) readShifted(ctx context.Context, p []byte) error {
+	first, within := splitOffset(cd.sector*BytesPerSector + cd.shiftBytes())
+	data, err := cd.driveSectorData(ctx, first)
+	if err != nil {
+		return err
+	}
+	copy(p, data[within:])
+	if within == 0 {
+		return nil
+	}
+	data, err = cd.driveSectorData(ctx, first+1)
+	if err != nil {
+		return err
+	}
+	copy(p[BytesPerSector-within:], data[:within])
+	return nil
+}
+
+// driveSectorData returns the audio of a drive sector, reusing the
+// last one read, since consecutive sectors share a drive sector.
+func (cd *AudioCD) driveSectorData(ctx context.Context, sector int) ([]byte, error) {
+	if cd.carry != nil && cd.carrySector == sector {
+		return cd.carry, nil
+	}
+	if sector < 0 || sector >= cd.LengthSectors() {
+		return cd.overreadSector(sector), nil
+	}
+	if cd.driveSector != sector {
+		err := cd.seekDrive(sector)
+		if err != nil {
+			return nil, err
+		}
+	}
+	buf := make([]byte, BytesPerSector)
+	err := cd.readDrive(ctx, buf)
+	if err != nil {
+		return nil, err
+	}
+	cd.carry, cd.carrySector = buf, sector
+	return buf, nil
+}
+
+// Overread reports which edges of the disc the drive can read past.
+type Overread struct {
+	LeadIn  bool `json:"leadIn"`  // the drive can read the sectors before sector 0
+	LeadOut bool `json:"leadOut"` // the drive can read the lead-out after the last track
+}
+
+// DetectOverread tries reading the sectors either side of the disc's
+// audio with [*AudioCD.ReadAudioAt] to find out whether the drive can
+// overread them. The result is kept until the disc is opened again.
+func (cd *AudioCD) DetectOverread() (Overread, error) {
+	if !cd.IsOpen() {
+		return Overread{}, os.ErrClosed
+	}
+	if cd.overread != nil {
+		return *cd.overread, nil
+	}
+	buf := make([]byte, BytesPerSector)
+	var ov Overread
+	_, err := cd.ReadAudioAt(-1, buf)
+	ov.LeadIn = err == nil
+	_, err = cd.ReadAudioAt(cd.LeadOut(), buf)
+	ov.LeadOut = err == nil
+	cd.overread = &ov
+	return ov, nil
+}
+
+// overreadSector returns the audio of a sector outside the disc, read
+// from the drive if OverreadEdges is set and it can, or else silence.
+func (cd *AudioCD) overreadSector(sector int) []byte {
+	buf := make([]byte, BytesPerSector)
+	if !cd.OverreadEdges {
+		return buf
+	}
+	ov, err := cd.DetectOverread()
+	if err != nil || (sector < 0 && !ov.LeadIn) || (sector >= 0 && !ov.LeadOut) {
+		return buf
+	}
+	_, err = cd.ReadAudioAt(sector, buf)
+	if err != nil {
+		// some drives can only read part way
+		clear(buf)
+	}
+	return buf
+}
@@ -0,0 +1,25 @@
+package audiocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitOffset(t *testing.T) {
+	sector, within := splitOffset(0)
+	assert.Equal(t, 0, sector)
+	assert.Equal(t, 0, within)
+
+	sector, within = splitOffset(BytesPerSector + 24)
+	assert.Equal(t, 1, sector)
+	assert.Equal(t, 24, within)
+
+	sector, within = splitOffset(-24)
+	assert.Equal(t, -1, sector)
+	assert.Equal(t, BytesPerSector-24, within)
+
+	sector, within = splitOffset(-BytesPerSector)
+	assert.Equal(t, -1, sector)
+	assert.Equal(t, 0, within)
+}
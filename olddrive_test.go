@@ -2,9 +2,16 @@ package audiocd
 
 import (
 	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
 	"io"
 	"os"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -160,3 +167,628 @@ func TestRipEvery10s(t *testing.T) {
 	err = os.WriteFile("steps.cdda", buf.Bytes(), 0777)
 	failIfErr(t, err)
 }
+
+func TestTrackReader(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	track, err := drive.Track(5)
+	failIfErr(t, err)
+
+	data, err := io.ReadAll(track)
+	failIfErr(t, err)
+	assert.Equal(t, 11903*BytesPerSector, len(data))
+
+	_, err = drive.Track(6)
+	assert.Equal(t, ErrInvalidTrackNumber, err)
+}
+
+func TestTrackReaderWriteTo(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	track, err := drive.Track(5)
+	failIfErr(t, err)
+	_, err = track.Seek(100, io.SeekStart)
+	failIfErr(t, err)
+
+	buf := bytes.Buffer{}
+	n, err := track.WriteTo(&buf)
+	failIfErr(t, err)
+	assert.Equal(t, int64(11903*BytesPerSector-100), n)
+	assert.Equal(t, int(n), buf.Len())
+}
+
+func TestSectors(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	count := 0
+	for sector, err := range drive.Sectors(100, 175) {
+		failIfErr(t, err)
+		assert.Equal(t, 100+count, sector.Number)
+		assert.Len(t, sector.Data, BytesPerSector)
+		count++
+	}
+	assert.Equal(t, 75, count)
+}
+
+func TestReadSectors(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	_, err = drive.SeekToSector(6290)
+	failIfErr(t, err)
+
+	buf := make([]byte, 2*BytesPerSector)
+	n, err := drive.ReadSectors(100, buf)
+	failIfErr(t, err)
+	assert.Equal(t, len(buf), n)
+
+	// the read position is unaffected
+	assert.Equal(t, 6290, drive.Position().Sector)
+	data := make([]byte, BytesPerSector)
+	_, err = io.ReadFull(&drive, data)
+	failIfErr(t, err)
+
+	track, err := drive.Track(2)
+	failIfErr(t, err)
+	first := make([]byte, BytesPerSector)
+	_, err = io.ReadFull(track, first)
+	failIfErr(t, err)
+	assert.Equal(t, first, data)
+}
+
+func TestRip(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	var buf bytes.Buffer
+	report, err := drive.Rip(RipOptions{
+		Tracks: []int{2},
+		Output: func(t TrackPosition) (io.Writer, error) {
+			return &buf, nil
+		},
+	})
+	failIfErr(t, err)
+
+	assert.Len(t, report.Tracks, 1)
+	tr := report.Tracks[0]
+	assert.Equal(t, 2, tr.TrackNum)
+	assert.Equal(t, int64(buf.Len()), tr.Bytes)
+	assert.Equal(t, int64(drive.TOC()[1].LengthSectors)*BytesPerSector, tr.Bytes)
+	assert.Equal(t, crc32.ChecksumIEEE(buf.Bytes()), tr.CRC32)
+	assert.Len(t, tr.MD5, 32)
+	assert.Zero(t, tr.Errors)
+}
+
+func TestCopyTrack(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	var buf bytes.Buffer
+	last := 0
+	n, err := drive.CopyTrack(context.Background(), &buf, 1, func(done, total int) {
+		assert.Greater(t, done, last)
+		assert.Equal(t, drive.TOC()[0].LengthSectors, total)
+		last = done
+	})
+	failIfErr(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+	assert.Equal(t, drive.TOC()[0].LengthSectors, last)
+
+	// a cancelled copy stops immediately
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = drive.CopyTrack(ctx, io.Discard, 1, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRipRange(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	var buf bytes.Buffer
+	n, err := drive.RipRange(6280, 6300, &buf)
+	failIfErr(t, err)
+	assert.Equal(t, int64(20*BytesPerSector), n)
+	assert.Equal(t, 6300, drive.Position().Sector)
+
+	// the data matches a sector read
+	sector := make([]byte, BytesPerSector)
+	_, err = drive.ReadSectors(6290, sector)
+	failIfErr(t, err)
+	assert.Equal(t, sector, buf.Bytes()[10*BytesPerSector:11*BytesPerSector])
+
+	_, err = drive.RipRange(-1, 10, &buf)
+	assert.Error(t, err)
+}
+
+func TestReadModeSecure(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1", ReadMode: ReadModeSecure}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+	failIfErr(t, drive.SetSecureMode(3, 2))
+
+	secure := make([]byte, 2*BytesPerSector)
+	_, err = drive.ReadSectors(100, secure)
+	failIfErr(t, err)
+	assert.Empty(t, drive.SuspiciousSectors())
+
+	drive.ReadMode = ReadModeParanoia
+	paranoid := make([]byte, 2*BytesPerSector)
+	_, err = drive.ReadSectors(100, paranoid)
+	failIfErr(t, err)
+	assert.Equal(t, paranoid, secure)
+}
+
+func TestReadCache(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	failIfErr(t, drive.SetReadCache(false))
+	enabled, err := drive.ReadCacheEnabled()
+	failIfErr(t, err)
+	assert.False(t, enabled)
+
+	failIfErr(t, drive.SetReadCache(true))
+	enabled, err = drive.ReadCacheEnabled()
+	failIfErr(t, err)
+	assert.True(t, enabled)
+
+	assert.Equal(t, 100+DefaultCacheDefeatSectors, drive.cacheDefeatSector(100))
+	assert.Equal(t, drive.LengthSectors()-1-DefaultCacheDefeatSectors, drive.cacheDefeatSector(drive.LengthSectors()-1))
+	drive.SetCacheDefeat(-1)
+	assert.Equal(t, -1, drive.cacheDefeatSector(100))
+}
+
+func TestSampleOffset(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	raw := make([]byte, 3*BytesPerSector)
+	_, err = drive.ReadAudioAt(99, raw)
+	failIfErr(t, err)
+
+	drive.SampleOffset = 6
+	shifted := make([]byte, BytesPerSector)
+	_, err = drive.ReadSectors(100, shifted)
+	failIfErr(t, err)
+	assert.Equal(t, raw[BytesPerSector+24:2*BytesPerSector+24], shifted)
+
+	drive.SampleOffset = -6
+	_, err = drive.ReadSectors(100, shifted)
+	failIfErr(t, err)
+	assert.Equal(t, raw[BytesPerSector-24:2*BytesPerSector-24], shifted)
+
+	// the start of the disc is padded with silence
+	_, err = drive.ReadSectors(0, shifted)
+	failIfErr(t, err)
+	assert.Equal(t, make([]byte, 24), shifted[:24])
+}
+
+func TestDetectReadOffset(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	drive.SampleOffset = 6
+	// the checksum is of the disc audio whatever the output settings
+	drive.ByteOrder = binary.BigEndian
+	drive.Deemphasis = true
+	checksum, err := drive.AccurateRipFrame450(2)
+	failIfErr(t, err)
+
+	offset, err := drive.DetectReadOffset(2, []uint32{checksum}, 0)
+	failIfErr(t, err)
+	assert.Equal(t, 6, offset)
+}
+
+func TestOverread(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1", SampleOffset: -6, OverreadEdges: true}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	ov, err := drive.DetectOverread()
+	failIfErr(t, err)
+	if !ov.LeadIn {
+		t.Skip("drive can't overread the lead-in")
+	}
+
+	raw := make([]byte, BytesPerSector)
+	_, err = drive.ReadAudioAt(-1, raw)
+	failIfErr(t, err)
+
+	shifted := make([]byte, BytesPerSector)
+	_, err = drive.ReadSectors(0, shifted)
+	failIfErr(t, err)
+	assert.Equal(t, raw[BytesPerSector-24:], shifted[:24])
+}
+
+func TestDriveFeatures(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	f, err := drive.DriveFeatures()
+	failIfErr(t, err)
+	assert.True(t, f.AccurateStream)
+	assert.Greater(t, f.CacheKB, 0)
+}
+
+func TestReadModeCdio(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1", ReadMode: ReadModeCdio}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	cdio := make([]byte, 2*BytesPerSector)
+	_, err = drive.ReadSectors(100, cdio)
+	if errors.Is(err, ErrCdioUnsupported) {
+		t.Skip("built without the cdio tag")
+	}
+	failIfErr(t, err)
+
+	drive.ReadMode = ReadModeParanoia
+	paranoid := make([]byte, 2*BytesPerSector)
+	_, err = drive.ReadSectors(100, paranoid)
+	failIfErr(t, err)
+	assert.Equal(t, paranoid, cdio)
+}
+
+func TestReadModeStitch(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1", ReadMode: ReadModeStitch}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	stitched := make([]byte, 10*BytesPerSector)
+	_, err = drive.ReadSectors(100, stitched)
+	failIfErr(t, err)
+	assert.Empty(t, drive.SuspiciousSectors())
+
+	drive.ReadMode = ReadModeParanoia
+	paranoid := make([]byte, 10*BytesPerSector)
+	_, err = drive.ReadSectors(100, paranoid)
+	failIfErr(t, err)
+	assert.Equal(t, paranoid, stitched)
+}
+
+func TestRipTestAndCopy(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	var buf bytes.Buffer
+	report, err := drive.Rip(RipOptions{
+		Tracks:      []int{2},
+		TestAndCopy: true,
+		Output: func(t TrackPosition) (io.Writer, error) {
+			return &buf, nil
+		},
+	})
+	failIfErr(t, err)
+
+	tr := report.Tracks[0]
+	assert.True(t, tr.Tested)
+	assert.Equal(t, crc32.ChecksumIEEE(buf.Bytes()), tr.TestCRC32)
+	assert.Empty(t, report.Mismatches())
+}
+
+func TestSecureC2Only(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1", ReadMode: ReadModeSecure}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+	failIfErr(t, drive.SetSecureOptions(SecureOptions{Reads: 4, Quorum: 2, C2Only: true}))
+
+	secure := make([]byte, 10*BytesPerSector)
+	_, err = drive.ReadSectors(100, secure)
+	failIfErr(t, err)
+
+	drive.ReadMode = ReadModeParanoia
+	paranoid := make([]byte, 10*BytesPerSector)
+	_, err = drive.ReadSectors(100, paranoid)
+	failIfErr(t, err)
+	assert.Equal(t, paranoid, secure)
+}
+
+func TestListDrives(t *testing.T) {
+	drives, err := ListDrives()
+	failIfErr(t, err)
+
+	i := slices.IndexFunc(drives, func(d DriveInfo) bool { return d.Device == "/dev/sr1" })
+	if assert.GreaterOrEqual(t, i, 0) {
+		assert.NotEmpty(t, drives[i].Vendor)
+		assert.NotEmpty(t, drives[i].Model)
+	}
+}
+
+func TestFindAudioCDs(t *testing.T) {
+	drives, err := FindAudioCDs()
+	failIfErr(t, err)
+	assert.True(t, slices.ContainsFunc(drives, func(d DriveInfo) bool { return d.Device == "/dev/sr1" }))
+}
+
+func TestSpeed(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	max, err := drive.MaxSpeed()
+	failIfErr(t, err)
+	assert.Greater(t, max, 4)
+
+	failIfErr(t, drive.SetSpeed(4))
+	speed, err := drive.Speed()
+	failIfErr(t, err)
+	assert.LessOrEqual(t, speed, 4)
+}
+
+func TestLockDoor(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	failIfErr(t, drive.LockDoor(true))
+	failIfErr(t, drive.LockDoor(false))
+}
+
+func TestLoadMedia(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	err := drive.LoadMedia(ctx)
+	failIfErr(t, err)
+	defer drive.Close()
+
+	assert.True(t, drive.IsOpen())
+	assert.Greater(t, drive.TrackCount(), 0)
+}
+
+func TestWatcher(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	w := Watcher{Devices: []string{"/dev/sr1"}}
+	e, ok := <-w.Watch(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, Event{"/dev/sr1", EventDiscInserted}, e)
+}
+
+func TestKeepAlive(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	failIfErr(t, drive.SetKeepAlive(100*time.Millisecond))
+	time.Sleep(500 * time.Millisecond)
+	buf := make([]byte, BytesPerSector)
+	_, err = io.ReadFull(&drive, buf)
+	failIfErr(t, err)
+	failIfErr(t, drive.SetKeepAlive(0))
+}
+
+func TestReset(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	_, err = drive.SeekToSector(1000)
+	failIfErr(t, err)
+	before := make([]byte, BytesPerSector)
+	_, err = drive.ReadSectors(1000, before)
+	failIfErr(t, err)
+
+	failIfErr(t, drive.Reset())
+	assert.True(t, drive.IsOpen())
+	assert.Equal(t, int64(1000*BytesPerSector), drive.Position().Offset)
+	after := make([]byte, BytesPerSector)
+	_, err = io.ReadFull(&drive, after)
+	failIfErr(t, err)
+	assert.Equal(t, before, after)
+}
+
+// TestResetWhileStalled is most useful run with -race, as the
+// abandoned read carries on alongside the reset drive.
+func TestResetWhileStalled(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	before := make([]byte, 4*BytesPerSector)
+	_, err = drive.ReadSectors(1000, before)
+	failIfErr(t, err)
+
+	_, err = drive.SeekToSector(1000)
+	failIfErr(t, err)
+	drive.SetReadTimeout(time.Nanosecond)
+	_, err = drive.Read(make([]byte, BytesPerSector))
+	assert.ErrorIs(t, err, ErrReadTimeout)
+
+	failIfErr(t, drive.Reset())
+	drive.SetReadTimeout(0)
+	after := make([]byte, len(before))
+	_, err = io.ReadFull(&drive, after)
+	failIfErr(t, err)
+	assert.Equal(t, before, after)
+}
+
+func TestMMC(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	// INQUIRY
+	buf := make([]byte, 36)
+	failIfErr(t, drive.MMC([]byte{0x12, 0, 0, 0, byte(len(buf)), 0}, DirIn, buf, 0))
+	vendor, _, _ := parseInquiry(buf)
+	assert.NotEmpty(t, vendor)
+
+	// TEST UNIT READY
+	failIfErr(t, drive.MMC(make([]byte, 6), DirNone, nil, time.Second))
+	assert.Error(t, drive.MMC([]byte{0x00}, DirNone, nil, 0))
+}
+
+func TestAccessMode(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1", AccessMode: AccessModeIOCTL}
+	err := drive.Open()
+	failIfErr(t, err)
+	assert.Equal(t, InterfaceType(1) /*COOKED_IOCTL*/, drive.InterfaceType())
+	ioctl := make([]byte, BytesPerSector)
+	_, err = drive.ReadSectors(1000, ioctl)
+	failIfErr(t, err)
+	drive.Close()
+
+	drive.AccessMode = AccessModeSCSI
+	err = drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+	assert.Equal(t, InterfaceType(3) /*SGIO_SCSI*/, drive.InterfaceType())
+	scsi := make([]byte, BytesPerSector)
+	_, err = drive.ReadSectors(1000, scsi)
+	failIfErr(t, err)
+	assert.Equal(t, ioctl, scsi)
+}
+
+func TestDefaultDevice(t *testing.T) {
+	device, err := DefaultDevice()
+	failIfErr(t, err)
+	assert.True(t, strings.HasPrefix(device, "/dev/"))
+}
+
+func TestSlots(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	slots, err := drive.Slots()
+	failIfErr(t, err)
+	assert.Equal(t, 1, slots)
+	slot, err := drive.Slot()
+	failIfErr(t, err)
+	assert.Equal(t, 0, slot)
+	assert.Error(t, drive.SelectSlot(context.Background(), 1))
+}
+
+func TestExclusive(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1", Exclusive: true}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	other := AudioCD{Device: "/dev/sr1", Exclusive: true}
+	err = other.Open()
+	assert.ErrorIs(t, err, ErrDriveBusy)
+	assert.Equal(t, ErrorTransient, Classify(err))
+
+	// non-exclusive opens aren't kept out
+	shared := AudioCD{Device: "/dev/sr1"}
+	failIfErr(t, shared.Open())
+	shared.Close()
+}
+
+func TestBusyTimeout(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1", Exclusive: true}
+	err := drive.Open()
+	failIfErr(t, err)
+	go func() {
+		time.Sleep(500 * time.Millisecond)
+		drive.Close()
+	}()
+
+	other := AudioCD{Device: "/dev/sr1", Exclusive: true, BusyTimeout: 5 * time.Second}
+	err = other.Open()
+	failIfErr(t, err)
+	other.Close()
+}
+
+func TestPlayback(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	failIfErr(t, drive.PlayMSF(MSF{0, 10, 0}, MSF{0, 20, 0}))
+	time.Sleep(time.Second)
+	failIfErr(t, drive.Pause())
+	failIfErr(t, drive.Resume())
+	failIfErr(t, drive.Stop())
+	assert.Error(t, drive.PlayMSF(MSF{0, 20, 0}, MSF{0, 10, 0}))
+}
+
+func TestVolume(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	v, err := drive.Volume()
+	failIfErr(t, err)
+	defer drive.SetVolume(v)
+
+	failIfErr(t, drive.SetVolume(Volume{128, 64, 0, 0}))
+	nv, err := drive.Volume()
+	failIfErr(t, err)
+	assert.Equal(t, uint8(128), nv[0])
+	assert.Equal(t, uint8(64), nv[1])
+}
+
+func TestIdentify(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	info, err := drive.Identify()
+	failIfErr(t, err)
+	assert.Equal(t, "/dev/sr1", info.Device)
+	assert.NotEmpty(t, info.Vendor)
+	assert.Contains(t, drive.Model(), info.Model)
+	assert.Equal(t, strings.TrimSpace(info.Model), info.Model)
+}
+
+func TestEject(t *testing.T) {
+	drive := AudioCD{Device: "/dev/sr1"}
+	err := drive.Open()
+	failIfErr(t, err)
+	defer drive.Close()
+
+	failIfErr(t, drive.Eject())
+	assert.False(t, drive.IsOpen())
+	assert.Equal(t, "/dev/sr1", drive.Device)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	failIfErr(t, drive.LoadMedia(ctx))
+	assert.True(t, drive.IsOpen())
+}
@@ -0,0 +1,114 @@
+package audiocd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// PlayMSF makes the drive play the disc from start up to end through
+// its analog output or the host's CD audio path, without reading the
+// audio. It returns once playback has started. Use [*AudioCD.Pause],
+// [*AudioCD.Resume] and [*AudioCD.Stop] to control it.
+//
+// This requires a drive which supports MMC commands.
+func (cd *AudioCD) PlayMSF(start, end MSF) error {
+	if !cd.IsOpen() {
+		return os.ErrClosed
+	}
+	if start.Sectors() < 0 || start.Sectors() >= end.Sectors() || end.Sectors() > cd.LengthSectors() {
+		return fmt.Errorf("audiocd: can't play from %v to %v", start, end)
+	}
+	return mmcCommand(cd, playMSFCommand(start, end), DirNone, nil, mmcTimeout)
+}
+
+// playMSFCommand builds PLAY AUDIO MSF, which takes addresses on the
+// disc, counting the two second pregap.
+func playMSFCommand(start, end MSF) []byte {
+	s := SectorsToMSF(start.Sectors() + discIDPregap)
+	e := SectorsToMSF(end.Sectors() + discIDPregap)
+	return []byte{mmcPlayAudioMSF, 0, 0,
+		byte(s.Minutes), byte(s.Seconds), byte(s.Frames),
+		byte(e.Minutes), byte(e.Seconds), byte(e.Frames), 0}
+}
+
+// Pause pauses playback started by [*AudioCD.PlayMSF].
+func (cd *AudioCD) Pause() error {
+	return cd.pauseResume(false)
+}
+
+// Resume continues playback paused by [*AudioCD.Pause].
+func (cd *AudioCD) Resume() error {
+	return cd.pauseResume(true)
+}
+
+func (cd *AudioCD) pauseResume(resume bool) error {
+	if !cd.IsOpen() {
+		return os.ErrClosed
+	}
+	cdb := []byte{mmcPauseResume, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	if resume {
+		cdb[8] = 0x01
+	}
+	return mmcCommand(cd, cdb, DirNone, nil, mmcTimeout)
+}
+
+// Stop ends playback started by [*AudioCD.PlayMSF].
+func (cd *AudioCD) Stop() error {
+	if !cd.IsOpen() {
+		return os.ErrClosed
+	}
+	cdb := []byte{mmcStopPlayScan, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	return mmcCommand(cd, cdb, DirNone, nil, mmcTimeout)
+}
+
+// Volume is the playback volume of the drive's four output ports,
+// from 0 (muted) to 255. Stereo drives use the first two for the
+// left and right channels.
+type Volume [4]uint8
+
+// Volume returns the drive's volume for [*AudioCD.PlayMSF]. It doesn't
+// affect audio read from the disc.
+//
+// This requires a drive which supports MMC commands.
+func (cd *AudioCD) Volume() (Volume, error) {
+	page, err := cd.audioControlPage()
+	if err != nil {
+		return Volume{}, err
+	}
+	var v Volume
+	for i := range v {
+		v[i] = page[9+2*i]
+	}
+	return v, nil
+}
+
+// SetVolume sets the drive's volume for [*AudioCD.PlayMSF].
+//
+// This requires a drive which supports MMC commands.
+func (cd *AudioCD) SetVolume(v Volume) error {
+	page, err := cd.audioControlPage()
+	if err != nil {
+		return err
+	}
+	for i, level := range v {
+		page[9+2*i] = level
+	}
+	return cd.modeSelect(page)
+}
+
+// audioControlPage reads the CD audio control mode page, which has
+// the channel selection and volume of each port from byte 8.
+func (cd *AudioCD) audioControlPage() ([]byte, error) {
+	if !cd.IsOpen() {
+		return nil, os.ErrClosed
+	}
+	page, err := cd.modeSense(modePageAudioControl)
+	if err != nil {
+		return nil, err
+	}
+	if len(page) < 16 {
+		return nil, errors.New("audiocd: audio control mode page too short")
+	}
+	return page, nil
+}
@@ -0,0 +1,15 @@
+package audiocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlayMSFCommand(t *testing.T) {
+	cdb := playMSFCommand(MSF{0, 0, 0}, MSF{4, 23, 10})
+	assert.Equal(t, []byte{0x47, 0, 0, 0, 2, 0, 4, 25, 10, 0}, cdb)
+
+	cdb = playMSFCommand(MSF{1, 59, 74}, MSF{2, 0, 0})
+	assert.Equal(t, []byte{0x47, 0, 0, 2, 1, 74, 2, 2, 0, 0}, cdb)
+}
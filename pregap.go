@@ -0,0 +1,165 @@
+package audiocd
+
+import (
+	"fmt"
+	"os"
+)
+
+// ScanPregaps returns the table of contents with the PregapSectors of
+// each track filled in. The table of contents only records where index 1
+// of each track starts, so the pregaps (index 0) are found by searching
+// the Q subchannel of the preceding track, which takes a few dozen
+// reads per track. Pregap sectors are still counted in the LengthSectors
+// of the preceding track.
+//
+// Pregaps are only scanned between audio tracks.
+//
+// This requires a drive which supports MMC commands and can read
+// the raw subchannel.
+func (cd *AudioCD) ScanPregaps() ([]TrackPosition, error) {
+	if !cd.IsOpen() {
+		return nil, os.ErrClosed
+	}
+	toc := cd.TOC()
+	for i := 1; i < len(toc); i++ {
+		prev, t := toc[i-1], toc[i]
+		if !prev.IsAudio() || !t.IsAudio() {
+			continue
+		}
+		start, err := searchSectors(prev.StartSector+1, t.StartSector, t.TrackNum*100, cd.qKeyAt)
+		if err != nil {
+			return nil, err
+		}
+		toc[i].PregapSectors = t.StartSector - start
+	}
+	return toc, nil
+}
+
+// searchSectors finds the first sector in [lo, hi] whose key is at
+// least target, assuming keys never decrease and the key of hi is at
+// least target.
+func searchSectors(lo, hi, target int, keyAt func(int) (int, error)) (int, error) {
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		key, err := keyAt(mid)
+		if err != nil {
+			return 0, err
+		}
+		if key >= target {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo, nil
+}
+
+// TrackIndexes scans the Q subchannel of a track (starting at 1) for its
+// index points: index 0 if the track has a pregap, index 1 at the start
+// of the track and any further index marks, which are common on live
+// albums and classical discs. The result can be used as the Indexes of
+// a [CueTrack].
+//
+// Like [*AudioCD.ScanPregaps], this requires a drive which supports MMC
+// commands and can read the raw subchannel.
+func (cd *AudioCD) TrackIndexes(track int) ([]CueIndex, error) {
+	if !cd.IsOpen() {
+		return nil, os.ErrClosed
+	}
+	toc := cd.TOC()
+	if track < 1 || track > len(toc) {
+		return nil, ErrInvalidTrackNumber
+	}
+	t := toc[track-1]
+	if !t.IsAudio() {
+		return nil, fmt.Errorf("audiocd: track %d is not an audio track", track)
+	}
+
+	pregap := t.PregapSectors
+	if track > 1 && toc[track-2].IsAudio() {
+		start, err := searchSectors(toc[track-2].StartSector+1, t.StartSector, t.TrackNum*100, cd.qKeyAt)
+		if err != nil {
+			return nil, err
+		}
+		pregap = t.StartSector - start
+	}
+	return scanIndexes(t, pregap, cd.qKeyAt)
+}
+
+// scanIndexes finds the index points within a track by bisecting the
+// track wherever the index differs at either end. Since the index
+// never decreases, this finds every index mark in a few reads each.
+func scanIndexes(t TrackPosition, pregap int, keyAt func(int) (int, error)) ([]CueIndex, error) {
+	var indexes []CueIndex
+	if pregap > 0 {
+		indexes = append(indexes, CueIndex{Number: 0, Sector: t.StartSector - pregap})
+	}
+	indexes = append(indexes, CueIndex{Number: 1, Sector: t.StartSector})
+
+	lo, hi := t.StartSector, t.StartSector+t.LengthSectors-1
+	loKey, err := keyAt(lo)
+	if err != nil {
+		return nil, err
+	}
+	hiKey, err := keyAt(hi)
+	if err != nil {
+		return nil, err
+	}
+
+	var bisect func(lo, loKey, hi, hiKey int) error
+	bisect = func(lo, loKey, hi, hiKey int) error {
+		if loKey == hiKey {
+			return nil
+		}
+		if hi-lo == 1 {
+			if hiKey/100 == t.TrackNum {
+				indexes = append(indexes, CueIndex{Number: hiKey % 100, Sector: hi})
+			}
+			return nil
+		}
+		mid := lo + (hi-lo)/2
+		midKey, err := keyAt(mid)
+		if err != nil {
+			return err
+		}
+		err = bisect(lo, loKey, mid, midKey)
+		if err != nil {
+			return err
+		}
+		return bisect(mid, midKey, hi, hiKey)
+	}
+	err = bisect(lo, loKey, hi, hiKey)
+	if err != nil {
+		return nil, err
+	}
+	return indexes, nil
+}
+
+// HiddenTrack reports whether the disc has Hidden Track One Audio
+// (HTOA): audio in the pregap of the first track, before its index 1.
+// Every disc has a two second pregap before sector 0, but when the
+// first track starts after sector 0, the sectors in between can hold
+// a hidden track.
+//
+// The hidden track is returned as a pseudo-track with TrackNum 0
+// covering the sectors before the first track. It can be read by
+// seeking to its StartSector. [*AudioCD.TrackAtSector] reports
+// 0 for these sectors.
+func (cd *AudioCD) HiddenTrack() (TrackPosition, bool) {
+	if !cd.IsOpen() {
+		return TrackPosition{}, false
+	}
+	return hiddenTrack(cd.TOC())
+}
+
+func hiddenTrack(toc []TrackPosition) (TrackPosition, bool) {
+	if len(toc) == 0 || !toc[0].IsAudio() || toc[0].StartSector <= 0 {
+		return TrackPosition{}, false
+	}
+	return TrackPosition{
+		Flags:         toc[0].Flags,
+		TrackNum:      0,
+		StartSector:   0,
+		LengthSectors: toc[0].StartSector,
+	}, true
+}
@@ -0,0 +1,68 @@
+package audiocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchSectors(t *testing.T) {
+	// track 1 until sector 999, track 2 pregap from 1000, index 1 at 1150
+	keyAt := func(sector int) (int, error) {
+		switch {
+		case sector < 1000:
+			return 101, nil
+		case sector < 1150:
+			return 200, nil
+		default:
+			return 201, nil
+		}
+	}
+	start, err := searchSectors(1, 1150, 200, keyAt)
+	failIfErr(t, err)
+	assert.Equal(t, 1000, start)
+}
+
+func TestScanIndexes(t *testing.T) {
+	// track 3 from 5000 with index 2 at 6000 and index 3 at 6001,
+	// followed by the pregap of track 4 at 9850
+	reads := 0
+	keyAt := func(sector int) (int, error) {
+		reads++
+		switch {
+		case sector < 6000:
+			return 301, nil
+		case sector < 6001:
+			return 302, nil
+		case sector < 9850:
+			return 303, nil
+		default:
+			return 400, nil
+		}
+	}
+	track := TrackPosition{TrackNum: 3, StartSector: 5000, LengthSectors: 5000}
+	indexes, err := scanIndexes(track, 150, keyAt)
+	failIfErr(t, err)
+	assert.Equal(t, []CueIndex{
+		{Number: 0, Sector: 4850},
+		{Number: 1, Sector: 5000},
+		{Number: 2, Sector: 6000},
+		{Number: 3, Sector: 6001},
+	}, indexes)
+	assert.Less(t, reads, 100)
+}
+
+func TestHiddenTrack(t *testing.T) {
+	toc := []TrackPosition{
+		{TrackNum: 1, StartSector: 33, LengthSectors: 6257, Flags: 0x02},
+		{TrackNum: 2, StartSector: 6290, LengthSectors: 17021},
+	}
+	htoa, ok := hiddenTrack(toc)
+	assert.True(t, ok)
+	assert.Equal(t, TrackPosition{TrackNum: 0, StartSector: 0, LengthSectors: 33, Flags: 0x02}, htoa)
+	assert.Equal(t, 0, trackAtSector(toc, 10))
+
+	toc[0].StartSector = 0
+	_, ok = hiddenTrack(toc)
+	assert.False(t, ok)
+}
@@ -0,0 +1,141 @@
+package audiocd
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"os"
+)
+
+// readCDMaxSectors is the number of sectors requested per READ CD
+// command, to stay below the transfer limit of most host adapters.
+const readCDMaxSectors = 24
+
+// ReadAudioAt reads whole sectors of audio starting at the given sector
+// directly from the drive, bypassing error correction and the read
+// cursor. Unlike [*AudioCD.Read], it can address sectors outside the
+// tracks: negative sectors are in the pregap of the first track before
+// sector 0, which leads into the lead-in, and sectors from
+// [*AudioCD.LeadOut] on are in the lead-out. Offset-corrected rips of
+// the first and last tracks need a few of these sectors.
+//
+// Many drives refuse to read the lead-in or lead-out, in which case
+// the drive's error is returned. p must be a multiple of
+// [BytesPerSector] in length. PCM data is in host byte order.
+//
+// This requires a drive which supports MMC commands.
+func (cd *AudioCD) ReadAudioAt(sector int, p []byte) (int, error) {
+	return cd.readCDSectors(sector, p, readCDUserData, 0, BytesPerSector)
+}
+
+// SubchannelBytesPerSector is the size of the raw P-W subchannel data
+// of one sector. Each byte holds one bit of each of the eight channels,
+// P in the most significant bit to W in the least.
+const SubchannelBytesPerSector = subchannelSize
+
+// RawBytesPerSector is the size of a sector read by
+// [*AudioCD.ReadRawSectors]: the audio followed by the subchannel data.
+const RawBytesPerSector = BytesPerSector + SubchannelBytesPerSector
+
+// ReadRawSectors reads whole sectors starting at the given sector
+// directly from the drive like [*AudioCD.ReadAudioAt], with the raw P-W
+// subchannel data of each sector following its audio. The subchannels
+// carry the position (Q) and, on karaoke discs, CD+G graphics (R-W).
+// p must be a multiple of [RawBytesPerSector] in length.
+//
+// This requires a drive which supports MMC commands and can read
+// the raw subchannel.
+func (cd *AudioCD) ReadRawSectors(sector int, p []byte) (int, error) {
+	return cd.readCDSectors(sector, p, readCDUserData, subchannelSelectRaw, RawBytesPerSector)
+}
+
+func (cd *AudioCD) readCDSectors(sector int, p []byte, fields, subchannel byte, sectorSize int) (int, error) {
+	if !cd.IsOpen() {
+		return 0, os.ErrClosed
+	}
+	if len(p)%sectorSize != 0 {
+		return 0, fmt.Errorf("audiocd: must read complete sectors")
+	}
+	n := 0
+	for n < len(p) {
+		count := min((len(p)-n)/sectorSize, readCDMaxSectors)
+		buf := p[n : n+count*sectorSize]
+		err := cd.readCD(sector, count, fields, subchannel, buf)
+		if err != nil {
+			return n, err
+		}
+		for i := 0; i < len(buf); i += sectorSize {
+			cd.driveToNative(buf[i : i+BytesPerSector])
+		}
+		sector += count
+		n += len(buf)
+	}
+	return n, nil
+}
+
+// C2BytesPerSector is the size of the C2 error pointers of one sector:
+// one bit for each byte of audio.
+const C2BytesPerSector = BytesPerSector / 8
+
+// C2Pointers are the C2 error pointers of one or more sectors. The
+// drive sets a bit for each byte of audio it couldn't correct with
+// the CD's error correction codes, and interpolated or guessed.
+type C2Pointers []byte
+
+// Flagged reports whether byte i of the audio data, as returned
+// alongside the pointers, was flagged as an error.
+func (c C2Pointers) Flagged(i int) bool {
+	if !nativeLittleEndian {
+		// the pointers follow the disc's byte order
+		i ^= 1
+	}
+	return c[i/8]&(0x80>>(i%8)) != 0
+}
+
+// Count returns the number of bytes flagged as errors.
+func (c C2Pointers) Count() int {
+	n := 0
+	for _, b := range c {
+		n += bits.OnesCount8(b)
+	}
+	return n
+}
+
+// ReadSectorsC2 reads whole sectors starting at the given sector
+// directly from the drive like [*AudioCD.ReadAudioAt], along with
+// the C2 error pointers, so the caller knows exactly which bytes the
+// drive couldn't read reliably. p must be a multiple of
+// [BytesPerSector] in length, and c2 must hold [C2BytesPerSector]
+// bytes for each sector.
+//
+// This requires a drive which supports MMC commands and reports
+// C2 errors.
+func (cd *AudioCD) ReadSectorsC2(sector int, p []byte, c2 C2Pointers) (int, error) {
+	if len(p)%BytesPerSector != 0 {
+		return 0, fmt.Errorf("audiocd: must read complete sectors")
+	}
+	count := len(p) / BytesPerSector
+	if len(c2) < count*C2BytesPerSector {
+		return 0, fmt.Errorf("audiocd: C2 pointer buffer too small")
+	}
+	const sectorSize = BytesPerSector + C2BytesPerSector
+	buf := make([]byte, count*sectorSize)
+	n, err := cd.readCDSectors(sector, buf, readCDUserData|readCDC2Pointers, 0, sectorSize)
+	for i := range n / sectorSize {
+		raw := buf[i*sectorSize : (i+1)*sectorSize]
+		copy(p[i*BytesPerSector:], raw[:BytesPerSector])
+		copy(c2[i*C2BytesPerSector:], raw[BytesPerSector:])
+	}
+	return n / sectorSize * BytesPerSector, err
+}
+
+// nativeLittleEndian reports whether the host is little-endian,
+// like the PCM data on the disc.
+var nativeLittleEndian = binary.NativeEndian.Uint16([]byte{1, 0}) == 1
+
+// swapBytes reverses the byte order of 16-bit samples in place.
+func swapBytes(p []byte) {
+	for i := 0; i+1 < len(p); i += 2 {
+		p[i], p[i+1] = p[i+1], p[i]
+	}
+}
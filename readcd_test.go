@@ -0,0 +1,22 @@
+package audiocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestC2Pointers(t *testing.T) {
+	c2 := make(C2Pointers, C2BytesPerSector)
+	c2[0] = 0x80 // the first byte on disc
+	c2[293] = 0x03
+
+	first := 0
+	if !nativeLittleEndian {
+		first = 1
+	}
+	assert.True(t, c2.Flagged(first))
+	assert.False(t, c2.Flagged(first^1))
+	assert.True(t, c2.Flagged(BytesPerSector-1))
+	assert.Equal(t, 3, c2.Count())
+}
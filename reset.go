@@ -0,0 +1,63 @@
+package audiocd
+
+import (
+	"errors"
+	"os"
+)
+
+// Reset recovers a drive which stopped responding, e.g. after a read
+// timed out (see [*AudioCD.SetReadTimeout]), without reopening the
+// AudioCD. It resets the drive, if the process is allowed to (this
+// needs CAP_SYS_ADMIN on Linux), then replaces the handle on it. A
+// stalled read is abandoned, and frees the old handle whenever it
+// returns. The read position, buffered data and settings are kept.
+func (cd *AudioCD) Reset() error {
+	if cd.drive == nil {
+		return os.ErrClosed
+	}
+	path := cd.Device
+	if path == "" {
+		path = devicePath(cd.drive)
+	}
+	err := resetDevice(path)
+	if err != nil && !errors.Is(err, ErrPermission) {
+		return err
+	}
+
+	keepAlive := cd.keepAlive
+	keepAlive.close()
+	cd.keepAlive = nil
+	cd.release()
+	tmp := &AudioCD{Device: path, AccessMode: cd.AccessMode, Exclusive: cd.Exclusive, LogMode: cd.LogMode, Logger: cd.Logger}
+	err = openDrive(tmp)
+	if err != nil {
+		return err
+	}
+	cd.drive, cd.paranoia, cd.claim = tmp.drive, tmp.paranoia, tmp.claim
+
+	speed := cd.speed
+	if speed == 0 {
+		speed = FullSpeed
+	}
+	err = cd.SetSpeed(speed)
+	if err != nil {
+		return err
+	}
+	cd.SetParanoiaMode(cd.paranoiaMode)
+	if cd.endianKnown {
+		setDriveBigEndian(cd, cd.bigEndian)
+	}
+	if cd.doorLocked {
+		err = cd.LockDoor(true)
+		if err != nil {
+			return err
+		}
+	}
+	if keepAlive != nil {
+		err = cd.SetKeepAlive(keepAlive.interval)
+		if err != nil {
+			return err
+		}
+	}
+	return cd.seek(cd.sector)
+}
@@ -0,0 +1,236 @@
+package audiocd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// RipOptions configures [*AudioCD.Rip].
+type RipOptions struct {
+	Tracks  []int                                    // the tracks to rip. If empty, all audio tracks are ripped
+	Output  func(t TrackPosition) (io.Writer, error) // called to open the writer for each track. If it's an [io.Closer], it's closed once the track is done
+	Retries int                                      // number of times to retry a failed read before giving up on the track. Fatal errors, see [Classify], aren't retried
+
+	// TestAndCopy reads each track twice, first only to compute its
+	// CRC and then to write it out, so the CRCs can be compared with
+	// [TrackReport.TestCopyMatch]. It's a cheaper check than paranoia
+	// that the audio reads the same each time.
+	TestAndCopy bool
+}
+
+// RipReport describes the result of [*AudioCD.Rip].
+type RipReport struct {
+	Tracks []TrackReport `json:"tracks"`
+}
+
+// TrackReport describes the result of ripping a single track.
+type TrackReport struct {
+	TrackNum int   `json:"trackNum"`
+	Bytes    int64 `json:"bytes"`  // the number of bytes of audio written
+	Errors   int   `json:"errors"` // the number of failed reads which were retried
+
+	// checksums of the audio in little-endian order, as on the disc
+	CRC32       uint32 `json:"crc32"`       // the IEEE CRC-32, as EAC's copy CRC
+	CRC32NoNull uint32 `json:"crc32NoNull"` // the CRC-32 skipping samples which are 0, as EAC's CRC without null samples
+	MD5         string `json:"md5"`         // hex encoded
+	SHA1        string `json:"sha1"`        // hex encoded
+
+	Tested    bool   `json:"tested,omitempty"`    // whether the track was read in a test pass first, see RipOptions.TestAndCopy
+	TestCRC32 uint32 `json:"testCRC32,omitempty"` // the CRC-32 of the test pass
+
+	AccurateRipV1 uint32 `json:"accurateRipV1"` // the AccurateRip v1 checksum, to check against the database
+	AccurateRipV2 uint32 `json:"accurateRipV2"` // the AccurateRip v2 checksum
+
+	// the result of checking the checksums against the AccurateRip
+	// database, filled in by the accuraterip package
+	AccurateRipConfidence int `json:"accurateRipConfidence,omitempty"` // the number of matching submissions
+	AccurateRipTotal      int `json:"accurateRipTotal,omitempty"`      // the number of submissions for the track
+	AccurateRipOffset     int `json:"accurateRipOffset,omitempty"`     // if the match is of a pressing at another offset, its offset in samples from the rip's
+
+	ErrorSectors      []int `json:"errorSectors,omitempty"`      // the disc sectors at which reads failed
+	SuspiciousSectors []int `json:"suspiciousSectors,omitempty"` // the disc sectors which the read mode reported as suspicious, see [*AudioCD.SuspiciousSectors]
+
+	SectorErrors map[int]SectorError `json:"sectorErrors,omitempty"` // the problems reading each sector, by disc sector
+}
+
+// TestCopyMatch reports whether the test pass of a track read the same
+// audio as the copy. It's true if the track wasn't tested.
+func (tp TrackReport) TestCopyMatch() bool {
+	return !tp.Tested || tp.TestCRC32 == tp.CRC32
+}
+
+// Mismatches returns the numbers of the tracks whose test and copy
+// passes read different audio.
+func (r *RipReport) Mismatches() []int {
+	var tracks []int
+	for _, t := range r.Tracks {
+		if !t.TestCopyMatch() {
+			tracks = append(tracks, t.TrackNum)
+		}
+	}
+	return tracks
+}
+
+// ripChunkSectors is the number of sectors read at once by Rip.
+const ripChunkSectors = SectorsPerSecond
+
+// Rip reads the audio tracks of the disc in turn, writing each to the
+// writer returned by opts.Output. It returns a report with the checksums
+// and the number of read errors of each track, which are computed as
+// the data streams. If a track can't be
+// read or written, Rip stops and returns the report for the tracks
+// done so far along with the error.
+//
+// The data written is the same as returned by [*AudioCD.Read], so the
+// ByteOrder and other settings of cd apply. The checksums are of the
+// audio as read from the disc, before Deemphasis or a processor are
+// applied, so they can be checked against other rips and the
+// AccurateRip database.
+func (cd *AudioCD) Rip(opts RipOptions) (*RipReport, error) {
+	if !cd.IsOpen() {
+		return nil, os.ErrClosed
+	}
+	if opts.Output == nil {
+		return nil, errors.New("audiocd: no output for rip")
+	}
+	toc := cd.TOC()
+	tracks := opts.Tracks
+	if len(tracks) == 0 {
+		for _, t := range toc {
+			if t.IsAudio() {
+				tracks = append(tracks, t.TrackNum)
+			}
+		}
+	}
+
+	report := &RipReport{}
+	buf := make([]byte, ripChunkSectors*BytesPerSector)
+	for _, n := range tracks {
+		tr, err := cd.Track(n)
+		if err != nil {
+			return report, err
+		}
+		var test TrackReport
+		if opts.TestAndCopy {
+			test, err = cd.ripTrack(tr, io.Discard, newTrackChecksums(), buf, opts.Retries)
+			if err != nil {
+				report.Tracks = append(report.Tracks, test)
+				return report, fmt.Errorf("audiocd: track %d: test: %w", n, err)
+			}
+			if _, err = tr.Seek(0, io.SeekStart); err != nil {
+				return report, err
+			}
+		}
+		w, err := opts.Output(tr.TrackPosition())
+		if err != nil {
+			return report, err
+		}
+		sums := newTrackChecksums()
+		if n > 0 {
+			// AccurateRip doesn't cover the hidden track
+			first, last := audioEdges(toc)
+			sums.ar = newAccurateRipSum(n == first, n == last, tr.TrackPosition().LengthSectors*SamplesPerSector)
+		}
+		suspicious, substituted := len(cd.suspicious), len(cd.substituted)
+		tp, err := cd.ripTrack(tr, w, sums, buf, opts.Retries)
+		if opts.TestAndCopy {
+			tp.Tested, tp.TestCRC32 = true, test.CRC32
+			tp.Errors += test.Errors
+			tp.ErrorSectors = append(test.ErrorSectors, tp.ErrorSectors...)
+			tp.mergeSectorErrors(test.SectorErrors)
+		}
+		tp.SuspiciousSectors = append([]int(nil), cd.suspicious[suspicious:]...)
+		for _, sector := range tp.SuspiciousSectors {
+			tp.markSector(tr.TrackPosition(), sector, SeveritySuspicious, nil)
+		}
+		for _, sector := range cd.substituted[substituted:] {
+			tp.markSector(tr.TrackPosition(), sector, SeveritySubstituted, nil)
+		}
+		if c, ok := w.(io.Closer); ok {
+			if cerr := c.Close(); err == nil {
+				err = cerr
+			}
+		}
+		report.Tracks = append(report.Tracks, tp)
+		if err != nil {
+			return report, fmt.Errorf("audiocd: track %d: %w", n, err)
+		}
+	}
+	return report, nil
+}
+
+// ripTrack copies the track to w, retrying failed reads. The checksums
+// are computed from the disc audio of the data read, before any processing.
+func (cd *AudioCD) ripTrack(tr *TrackReader, w io.Writer, sums *trackChecksums, buf []byte, retries int) (tp TrackReport, err error) {
+	tp.TrackNum = tr.TrackPosition().TrackNum
+	if err = cd.setTap(sums); err != nil {
+		return tp, err
+	}
+	defer func() {
+		cd.setTap(nil)
+		sums.report(&tp)
+	}()
+	failures := 0
+	for {
+		n, err := tr.Read(buf)
+		if n > 0 {
+			nw, werr := w.Write(buf[:n])
+			tp.Bytes += int64(nw)
+			if werr != nil {
+				return tp, werr
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			sector := tr.track.StartSector + int(tr.offset/BytesPerSector)
+			tp.ErrorSectors = append(tp.ErrorSectors, sector)
+			if failures >= retries || Classify(err) == ErrorFatal {
+				tp.markSector(tr.track, sector, SeverityFailed, err)
+				return tp, err
+			}
+			// try again from where the read stopped
+			failures++
+			tp.Errors++
+			tp.markSector(tr.track, sector, SeverityRetried, err)
+			continue
+		}
+		failures = 0
+	}
+	return tp, nil
+}
+
+// RipRange writes the audio of the sectors in [startSector, endSector)
+// to w, e.g. to extract a pregap, a hidden track or part of a track.
+// The range may span several tracks. It returns the number of bytes
+// written, and leaves the read cursor after the range.
+func (cd *AudioCD) RipRange(startSector, endSector int, w io.Writer) (int64, error) {
+	if !cd.IsOpen() {
+		return 0, os.ErrClosed
+	}
+	if startSector < 0 || endSector > cd.LengthSectors() || startSector > endSector {
+		return 0, fmt.Errorf("audiocd: sectors %d-%d are outside of the disc", startSector, endSector-1)
+	}
+	_, err := cd.SeekToSector(startSector)
+	if err != nil {
+		return 0, err
+	}
+	return cd.writeTo(w, int64(endSector-startSector)*BytesPerSector)
+}
+
+// audioEdges returns the numbers of the first and last audio tracks.
+func audioEdges(toc []TrackPosition) (first, last int) {
+	for _, t := range toc {
+		if !t.IsAudio() {
+			continue
+		}
+		if first == 0 {
+			first = t.TrackNum
+		}
+		last = t.TrackNum
+	}
+	return first, last
+}
@@ -0,0 +1,182 @@
+package audiocd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// logChecksumPrefix starts the checksum line of a signed log.
+const logChecksumPrefix = "SHA-256 hash: "
+
+var (
+	// ErrLogChecksumMissing is returned when validating a log
+	// which does not end with a checksum line.
+	ErrLogChecksumMissing = errors.New("audiocd: log has no checksum")
+	// ErrLogChecksumMismatch is returned when validating a log
+	// which has been modified since it was signed.
+	ErrLogChecksumMismatch = errors.New("audiocd: log checksum does not match")
+)
+
+// LogChecksum computes the self-checksum of a rip log, as used by [whipper].
+// It is the upper-case hex SHA-256 of the log text, excluding
+// the final newline.
+//
+// The EAC log checksum uses an unpublished key and can't be
+// reproduced.
+//
+// [whipper]: https://github.com/whipper-team/whipper
+func LogChecksum(log []byte) string {
+	return logChecksum(bytes.TrimSuffix(log, []byte("\n")))
+}
+
+func logChecksum(body []byte) string {
+	return strings.ToUpper(fmt.Sprintf("%x", sha256.Sum256(body)))
+}
+
+// SignLog appends the checksum line to a rip log:
+//
+//	SHA-256 hash: 6D5A...
+func SignLog(log []byte) []byte {
+	sum := LogChecksum(log)
+	signed := bytes.Clone(bytes.TrimSuffix(log, []byte("\n")))
+	signed = append(signed, '\n')
+	signed = append(signed, logChecksumPrefix...)
+	signed = append(signed, sum...)
+	return append(signed, '\n')
+}
+
+// VerifyLog checks the checksum line at the end of a signed rip log.
+// It returns [ErrLogChecksumMissing] if there is no checksum, or
+// [ErrLogChecksumMismatch] if the log has been altered.
+func VerifyLog(log []byte) error {
+	trimmed := bytes.TrimRight(log, "\r\n")
+	i := bytes.LastIndexByte(trimmed, '\n')
+	if i < 0 || !bytes.HasPrefix(trimmed[i+1:], []byte(logChecksumPrefix)) {
+		return ErrLogChecksumMissing
+	}
+	expected := strings.TrimSpace(string(trimmed[i+1+len(logChecksumPrefix):]))
+
+	body := bytes.ReplaceAll(trimmed[:i], []byte("\r\n"), []byte("\n"))
+	if !strings.EqualFold(logChecksum(body), expected) {
+		return ErrLogChecksumMismatch
+	}
+	return nil
+}
+
+// RipLog describes a rip, to be written out as a human-readable log
+// with [*RipLog.Text] or as JSON.
+type RipLog struct {
+	Program    string     `json:"program"`    // the ripping software, e.g. "MyRipper 1.0"
+	Date       time.Time  `json:"date"`       // when the rip was made
+	Drive      string     `json:"drive"`      // the drive model
+	ReadOffset int        `json:"readOffset"` // the read offset correction, in samples
+	ReadMode   string     `json:"readMode"`   // e.g. "Paranoia" or "Secure"
+	Disc       DiscInfo   `json:"disc"`
+	Tracks     []TrackLog `json:"tracks"`
+}
+
+// TrackLog is the log of a single ripped track.
+type TrackLog struct {
+	TrackReport
+	Path        string `json:"path,omitempty"`        // the file the track was written to
+	AccurateRip string `json:"accurateRip,omitempty"` // the result of the AccurateRip check, e.g. "Accurately ripped (confidence 12)"
+}
+
+// NewRipLog starts a log for a rip of the disc, with the drive and
+// disc information filled in and the tracks from the report.
+func (cd *AudioCD) NewRipLog(program string, report *RipReport) (*RipLog, error) {
+	info, err := cd.DiscInfo()
+	if err != nil {
+		return nil, err
+	}
+	rl := &RipLog{
+		Program:    program,
+		Date:       time.Now(),
+		Drive:      info.Model,
+		ReadOffset: cd.SampleOffset,
+		ReadMode:   cd.ReadMode.String(),
+		Disc:       info,
+	}
+	if report != nil {
+		for _, tr := range report.Tracks {
+			rl.Tracks = append(rl.Tracks, TrackLog{TrackReport: tr})
+		}
+	}
+	return rl, nil
+}
+
+// Text formats the log in the style of an EAC log, signed with
+// [SignLog].
+func (rl *RipLog) Text() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s extraction logfile from %s\n\n", rl.Program, rl.Date.Format("2. January 2006, 15:04"))
+	if rl.Disc.CDText != nil && rl.Disc.CDText.Title != "" {
+		fmt.Fprintf(&b, "%s / %s\n\n", rl.Disc.CDText.Performer, rl.Disc.CDText.Title)
+	}
+	fmt.Fprintf(&b, "Used drive  : %s\n", rl.Drive)
+	fmt.Fprintf(&b, "Read mode   : %s\n", rl.ReadMode)
+	fmt.Fprintf(&b, "Read offset correction : %d\n\n", rl.ReadOffset)
+
+	fmt.Fprintf(&b, "TOC of the extracted CD\n\n")
+	fmt.Fprintf(&b, "     Track |   Start  |  Length  | Start sector | End sector\n")
+	fmt.Fprintf(&b, "    ---------------------------------------------------------\n")
+	for _, t := range rl.Disc.TOC {
+		fmt.Fprintf(&b, "       %2d  | %s | %s |    %6d    |   %6d\n", t.TrackNum,
+			logTime(t.StartSector), logTime(t.LengthSectors), t.StartSector, t.StartSector+t.LengthSectors-1)
+	}
+	fmt.Fprintf(&b, "\n")
+	if id := rl.Disc.MusicBrainzID; id != "" {
+		fmt.Fprintf(&b, "MusicBrainz disc id : %s\n", id)
+	}
+	fmt.Fprintf(&b, "CDDB disc id        : %s\n\n", rl.Disc.CDDBID)
+
+	failed := 0
+	for _, t := range rl.Tracks {
+		fmt.Fprintf(&b, "Track %2d\n\n", t.TrackNum)
+		if t.Path != "" {
+			fmt.Fprintf(&b, "     Filename %s\n\n", t.Path)
+		}
+		if t.Tested {
+			fmt.Fprintf(&b, "     Test CRC %08X\n", t.TestCRC32)
+		}
+		fmt.Fprintf(&b, "     Copy CRC %08X\n", t.CRC32)
+		fmt.Fprintf(&b, "     Read errors %d\n", t.Errors)
+		if len(t.ErrorSectors) > 0 {
+			positions := make([]string, len(t.ErrorSectors))
+			for i, s := range t.ErrorSectors {
+				positions[i] = logTime(s)
+			}
+			fmt.Fprintf(&b, "     Error positions %s\n", strings.Join(positions, ", "))
+		}
+		if t.AccurateRip != "" {
+			fmt.Fprintf(&b, "     %s\n", t.AccurateRip)
+		}
+		if t.Tested && t.TestCopyMatch() {
+			fmt.Fprintf(&b, "     Copy OK\n")
+		} else if t.Tested {
+			fmt.Fprintf(&b, "     Test and copy CRCs differ\n")
+			failed++
+		}
+		fmt.Fprintf(&b, "\n")
+		failed += len(t.ErrorSectors)
+	}
+
+	if failed == 0 {
+		fmt.Fprintf(&b, "No errors occurred\n\n")
+	} else {
+		fmt.Fprintf(&b, "There were errors\n\n")
+	}
+	fmt.Fprintf(&b, "End of status report\n")
+	return SignLog(b.Bytes())
+}
+
+// logTime formats a sector count as minutes, seconds and frames,
+// e.g. " 3:25.12".
+func logTime(sectors int) string {
+	m := SectorsToMSF(sectors)
+	return fmt.Sprintf("%2d:%02d.%02d", m.Minutes, m.Seconds, m.Frames)
+}
@@ -0,0 +1,58 @@
+package audiocd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignLog(t *testing.T) {
+	log := []byte("Log created by: audiocd\n\nStatus report\n  EOF\n\n")
+	signed := SignLog(log)
+
+	assert.Equal(t, "Log created by: audiocd\n\nStatus report\n  EOF\n\nSHA-256 hash: "+LogChecksum(log)+"\n", string(signed))
+	assert.NoError(t, VerifyLog(signed))
+
+	tampered := append([]byte("X"), signed...)
+	assert.ErrorIs(t, VerifyLog(tampered), ErrLogChecksumMismatch)
+	assert.ErrorIs(t, VerifyLog(log), ErrLogChecksumMissing)
+}
+
+func TestRipLog(t *testing.T) {
+	toc := []TrackPosition{
+		{TrackNum: 1, StartSector: 0, LengthSectors: 6290},
+		{TrackNum: 2, StartSector: 6290, LengthSectors: 17021},
+	}
+	rl := &RipLog{
+		Program:    "audiocd",
+		Date:       time.Date(2024, 3, 2, 15, 4, 0, 0, time.UTC),
+		Drive:      "TEST DRIVE",
+		ReadOffset: 6,
+		ReadMode:   "Paranoia",
+		Disc:       newDiscInfo("TEST DRIVE", toc),
+		Tracks: []TrackLog{
+			{TrackReport: TrackReport{TrackNum: 1, CRC32: 0x1A2B3C4D, Tested: true, TestCRC32: 0x1A2B3C4D}, Path: "01.flac"},
+			{TrackReport: TrackReport{TrackNum: 2, CRC32: 0xDEADBEEF, Errors: 1, ErrorSectors: []int{6365}},
+				AccurateRip: "Accurately ripped (confidence 3)"},
+		},
+	}
+	text := rl.Text()
+	assert.NoError(t, VerifyLog(text))
+	s := string(text)
+	assert.True(t, strings.HasPrefix(s, "audiocd extraction logfile from 2. March 2024, 15:04\n"))
+	assert.Contains(t, s, "Read offset correction : 6\n")
+	assert.Contains(t, s, "        1  |  0:00.00 |  1:23.65 |         0    |     6289\n")
+	assert.Contains(t, s, "     Filename 01.flac\n\n     Test CRC 1A2B3C4D\n     Copy CRC 1A2B3C4D\n     Read errors 0\n     Copy OK\n")
+	assert.Contains(t, s, "     Error positions  1:24.65\n     Accurately ripped (confidence 3)\n")
+	assert.Contains(t, s, "There were errors\n")
+
+	data, err := json.Marshal(rl)
+	failIfErr(t, err)
+	var decoded RipLog
+	failIfErr(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, rl.Tracks, decoded.Tracks)
+	assert.Contains(t, string(data), `"crc32":3735928559`)
+}
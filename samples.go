@@ -0,0 +1,119 @@
+package audiocd
+
+import (
+	"io"
+	"unsafe"
+)
+
+// ReadSamples reads interleaved 16-bit PCM samples (left, right, left,
+// ...) from the disc into s. It returns the number of samples read.
+// ByteOrder doesn't apply, since the samples are decoded.
+func (cd *AudioCD) ReadSamples(s []int16) (int, error) {
+	return readSamples(cd, s, cd.swapsBytes())
+}
+
+// ReadSamples reads interleaved 16-bit PCM samples from the track.
+// See [*AudioCD.ReadSamples].
+func (tr *TrackReader) ReadSamples(s []int16) (int, error) {
+	return readSamples(tr, s, tr.cd.swapsBytes())
+}
+
+// SetSampleProcessor sets a function to be called with each block
+// of samples read by Read and WriteTo, before they are returned. It
+// may modify the samples in place, e.g. to apply gain or dither, or
+// only inspect them. Samples are interleaved, in host byte order and
+// after de-emphasis. Blocks are whole sectors in disc order, but
+// seeking breaks the sequence. Set it to nil to remove it.
+func (cd *AudioCD) SetSampleProcessor(fn func(samples []int16)) {
+	cd.processor = fn
+}
+
+// readSamples reads into s as bytes. If swap is set, the data
+// is converted back to host byte order.
+func readSamples(r io.Reader, s []int16, swap bool) (int, error) {
+	if len(s) == 0 {
+		return 0, nil
+	}
+	b := unsafe.Slice((*byte)(unsafe.Pointer(&s[0])), len(s)*BytesPerSample)
+	n, err := r.Read(b)
+	if n%BytesPerSample != 0 && err == nil {
+		// don't return half a sample
+		var nn int
+		nn, err = io.ReadFull(r, b[n:n+1])
+		n += nn
+	}
+	if swap {
+		swapBytes(b[:n])
+	}
+	return n / BytesPerSample, err
+}
+
+// ReadFloat32 reads interleaved samples from the disc into f,
+// normalized to the range [-1, 1). It returns the number of
+// samples read.
+func (cd *AudioCD) ReadFloat32(f []float32) (int, error) {
+	return readFloats(cd, f, cd.swapsBytes())
+}
+
+// ReadFloat64 reads interleaved samples from the disc into f,
+// normalized to the range [-1, 1). It returns the number of
+// samples read.
+func (cd *AudioCD) ReadFloat64(f []float64) (int, error) {
+	return readFloats(cd, f, cd.swapsBytes())
+}
+
+// ReadFrames reads stereo frames from the disc into frames as
+// left/right pairs, normalized to the range [-1, 1). It returns
+// the number of frames read.
+func (cd *AudioCD) ReadFrames(frames [][2]float32) (int, error) {
+	return readFrames(cd, frames, cd.swapsBytes())
+}
+
+// ReadFloat32 reads normalized interleaved samples from the track.
+// See [*AudioCD.ReadFloat32].
+func (tr *TrackReader) ReadFloat32(f []float32) (int, error) {
+	return readFloats(tr, f, tr.cd.swapsBytes())
+}
+
+// ReadFloat64 reads normalized interleaved samples from the track.
+// See [*AudioCD.ReadFloat64].
+func (tr *TrackReader) ReadFloat64(f []float64) (int, error) {
+	return readFloats(tr, f, tr.cd.swapsBytes())
+}
+
+// ReadFrames reads normalized stereo frames from the track.
+// See [*AudioCD.ReadFrames].
+func (tr *TrackReader) ReadFrames(frames [][2]float32) (int, error) {
+	return readFrames(tr, frames, tr.cd.swapsBytes())
+}
+
+// sampleScale normalizes 16-bit samples to [-1, 1).
+const sampleScale = 1 << (BitsPerSample - 1)
+
+func readFloats[F float32 | float64](r io.Reader, f []F, swap bool) (int, error) {
+	s := make([]int16, len(f))
+	n, err := readSamples(r, s, swap)
+	for i, v := range s[:n] {
+		f[i] = F(v) / sampleScale
+	}
+	return n, err
+}
+
+func readFrames(r io.Reader, frames [][2]float32, swap bool) (int, error) {
+	s := make([]int16, len(frames)*Channels)
+	n, err := readSamples(r, s, swap)
+	if n%Channels != 0 && err == nil {
+		// don't return half a frame
+		var nn int
+		nn, err = io.ReadFull(r, unsafe.Slice((*byte)(unsafe.Pointer(&s[n])), BytesPerSample))
+		if swap && nn == BytesPerSample {
+			swapBytes(unsafe.Slice((*byte)(unsafe.Pointer(&s[n])), BytesPerSample))
+		}
+		n += nn / BytesPerSample
+	}
+	for i := range n / Channels {
+		frames[i][0] = float32(s[i*2]) / sampleScale
+		frames[i][1] = float32(s[i*2+1]) / sampleScale
+	}
+	return n / Channels, err
+}
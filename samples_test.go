@@ -0,0 +1,83 @@
+package audiocd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadSamples(t *testing.T) {
+	data := make([]byte, 8)
+	for i, v := range []int16{1, -1, 32767, -32768} {
+		binary.NativeEndian.PutUint16(data[i*2:], uint16(v))
+	}
+
+	s := make([]int16, 4)
+	n, err := readSamples(bytes.NewReader(data), s, false)
+	failIfErr(t, err)
+	assert.Equal(t, 4, n)
+	assert.Equal(t, []int16{1, -1, 32767, -32768}, s)
+
+	// reads of single bytes are completed to whole samples
+	s = make([]int16, 4)
+	n, err = readSamples(iotest.OneByteReader(bytes.NewReader(data)), s, false)
+	failIfErr(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, int16(1), s[0])
+}
+
+func TestReadFloats(t *testing.T) {
+	data := make([]byte, 8)
+	for i, v := range []int16{16384, -16384, 32767, -32768} {
+		binary.NativeEndian.PutUint16(data[i*2:], uint16(v))
+	}
+
+	f := make([]float64, 4)
+	n, err := readFloats(bytes.NewReader(data), f, false)
+	failIfErr(t, err)
+	assert.Equal(t, 4, n)
+	assert.Equal(t, []float64{0.5, -0.5, 32767.0 / 32768, -1}, f)
+
+	frames := make([][2]float32, 2)
+	n, err = readFrames(iotest.OneByteReader(bytes.NewReader(data)), frames, false)
+	failIfErr(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, [2]float32{0.5, -0.5}, frames[0])
+}
+
+func TestReadSamplesByteOrder(t *testing.T) {
+	cd := AudioCD{ByteOrder: binary.BigEndian}
+	assert.Equal(t, nativeLittleEndian, cd.swapsBytes())
+	cd.ByteOrder = binary.NativeEndian
+	assert.False(t, cd.swapsBytes())
+
+	data := make([]byte, 4)
+	binary.BigEndian.PutUint16(data, 1)
+	binary.BigEndian.PutUint16(data[2:], uint16(0xFF00))
+
+	s := make([]int16, 2)
+	n, err := readSamples(bytes.NewReader(data), s, nativeLittleEndian)
+	failIfErr(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []int16{1, -256}, s)
+}
+
+func TestSampleProcessor(t *testing.T) {
+	cd := AudioCD{ByteOrder: binary.BigEndian}
+	var got []int16
+	cd.SetSampleProcessor(func(s []int16) {
+		got = append(got, s[:2]...)
+		s[0] = 2 * s[0]
+	})
+
+	p := make([]byte, BytesPerSector)
+	binary.NativeEndian.PutUint16(p, 100)
+	binary.NativeEndian.PutUint16(p[2:], uint16(0xFFFF))
+	cd.processSectors(p)
+	assert.Equal(t, []int16{100, -1}, got)
+	// the processor runs before the byte order is applied
+	assert.Equal(t, uint16(200), binary.BigEndian.Uint16(p))
+}
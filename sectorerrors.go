@@ -0,0 +1,100 @@
+package audiocd
+
+import (
+	"fmt"
+	"maps"
+)
+
+// Severity ranks how badly a read problem may affect the audio
+// of a sector.
+type Severity int
+
+const (
+	SeveritySuspicious  Severity = 1 // the sector was read, but the reads didn't agree
+	SeverityRetried     Severity = 2 // a read of the sector failed, and it was read on a retry
+	SeverityFailed      Severity = 3 // the sector couldn't be read
+	SeveritySubstituted Severity = 4 // the sector couldn't be read, and was replaced as configured by Substitute
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeveritySuspicious:
+		return "suspicious"
+	case SeverityRetried:
+		return "retried"
+	case SeverityFailed:
+		return "failed"
+	case SeveritySubstituted:
+		return "substituted"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// MarshalText encodes the severity by name, for JSON reports.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a severity encoded by MarshalText.
+func (s *Severity) UnmarshalText(b []byte) error {
+	for _, v := range []Severity{SeveritySuspicious, SeverityRetried, SeverityFailed, SeveritySubstituted} {
+		if string(b) == v.String() {
+			*s = v
+			return nil
+		}
+	}
+	return fmt.Errorf("audiocd: unknown severity %q", b)
+}
+
+// SectorError describes a problem reading a sector during a rip.
+type SectorError struct {
+	Sector   int      `json:"sector"`          // the disc sector
+	TrackNum int      `json:"trackNum"`        // the track containing the sector
+	Sample   int64    `json:"sample"`          // the first affected sample (per channel) from the start of the track
+	Severity Severity `json:"severity"`        // the worst problem with the sector
+	Err      string   `json:"error,omitempty"` // the read error, if there was one
+}
+
+// Samples returns the range of samples of the track affected, [start, end).
+func (se SectorError) Samples() (start, end int64) {
+	return se.Sample, se.Sample + SamplesPerSector
+}
+
+// markSector records a problem with a sector of the track, keeping
+// the most severe problem for each sector.
+func (tp *TrackReport) markSector(track TrackPosition, sector int, severity Severity, err error) {
+	se := SectorError{
+		Sector:   sector,
+		TrackNum: track.TrackNum,
+		Sample:   int64(sector-track.StartSector) * SamplesPerSector,
+		Severity: severity,
+	}
+	if err != nil {
+		se.Err = err.Error()
+	}
+	tp.mergeSectorErrors(map[int]SectorError{sector: se})
+}
+
+// mergeSectorErrors adds problems to the track's, keeping the most
+// severe problem for each sector.
+func (tp *TrackReport) mergeSectorErrors(errs map[int]SectorError) {
+	for sector, se := range errs {
+		if prev, ok := tp.SectorErrors[sector]; ok && prev.Severity > se.Severity {
+			continue
+		}
+		if tp.SectorErrors == nil {
+			tp.SectorErrors = make(map[int]SectorError)
+		}
+		tp.SectorErrors[sector] = se
+	}
+}
+
+// SectorErrors returns the problem sectors of all the tracks of the rip.
+func (r *RipReport) SectorErrors() map[int]SectorError {
+	all := make(map[int]SectorError)
+	for _, t := range r.Tracks {
+		maps.Copy(all, t.SectorErrors)
+	}
+	return all
+}
@@ -0,0 +1,50 @@
+package audiocd
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSectorErrors(t *testing.T) {
+	track := TrackPosition{TrackNum: 2, StartSector: 1000, LengthSectors: 500}
+
+	var tp TrackReport
+	tp.markSector(track, 1010, SeverityRetried, errors.New("read failed"))
+	tp.markSector(track, 1010, SeveritySuspicious, nil)
+	tp.markSector(track, 1020, SeveritySuspicious, nil)
+	tp.markSector(track, 1020, SeverityFailed, errors.New("read failed"))
+
+	se := tp.SectorErrors[1010]
+	assert.Equal(t, SeverityRetried, se.Severity)
+	assert.Equal(t, "read failed", se.Err)
+	assert.Equal(t, 2, se.TrackNum)
+	start, end := se.Samples()
+	assert.Equal(t, int64(10*SamplesPerSector), start)
+	assert.Equal(t, int64(11*SamplesPerSector), end)
+	assert.Equal(t, SeverityFailed, tp.SectorErrors[1020].Severity)
+
+	report := RipReport{Tracks: []TrackReport{tp, {TrackNum: 3}}}
+	assert.Len(t, report.SectorErrors(), 2)
+
+	data, err := json.Marshal(tp.SectorErrors[1020])
+	failIfErr(t, err)
+	assert.JSONEq(t, `{"sector":1020,"trackNum":2,"sample":11760,"severity":"failed","error":"read failed"}`, string(data))
+	var decoded SectorError
+	failIfErr(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, tp.SectorErrors[1020], decoded)
+}
+
+func TestMismatches(t *testing.T) {
+	report := RipReport{Tracks: []TrackReport{
+		{TrackNum: 1, CRC32: 1},
+		{TrackNum: 2, CRC32: 2, Tested: true, TestCRC32: 2},
+		{TrackNum: 3, CRC32: 3, Tested: true, TestCRC32: 4},
+	}}
+	assert.True(t, report.Tracks[0].TestCopyMatch())
+	assert.True(t, report.Tracks[1].TestCopyMatch())
+	assert.False(t, report.Tracks[2].TestCopyMatch())
+	assert.Equal(t, []int{3}, report.Mismatches())
+}
@@ -0,0 +1,46 @@
+package audiocd
+
+import (
+	"io"
+	"iter"
+	"os"
+)
+
+// Sector is a sector of audio data yielded by [*AudioCD.Sectors].
+type Sector struct {
+	Number int    // the address of the sector
+	Data   []byte // BytesPerSector bytes of PCM data, in host byte order unless ByteOrder is set
+}
+
+// Sectors iterates over the sectors in [start, end), e.g.
+//
+//	for sector, err := range cd.Sectors(t.StartSector, t.StartSector+t.LengthSectors) {
+//		if err != nil {
+//			return err
+//		}
+//		process(sector.Data)
+//	}
+//
+// The iteration stops after the first error. Data is reused between
+// iterations, so it must be copied to be retained. Iterating moves
+// the read cursor.
+func (cd *AudioCD) Sectors(start, end int) iter.Seq2[Sector, error] {
+	return func(yield func(Sector, error) bool) {
+		if !cd.IsOpen() {
+			yield(Sector{}, os.ErrClosed)
+			return
+		}
+		_, err := cd.SeekToSector(start)
+		if err != nil {
+			yield(Sector{Number: start}, err)
+			return
+		}
+		data := make([]byte, BytesPerSector)
+		for n := start; n < end; n++ {
+			_, err := io.ReadFull(cd, data)
+			if !yield(Sector{Number: n, Data: data}, err) || err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package audiocd
+
+import (
+	"bytes"
+	"fmt"
+	"hash/crc32"
+)
+
+// SecureOptions configures [ReadModeSecure].
+type SecureOptions struct {
+	Reads  int  // the most times each sector is read, at least 1
+	Quorum int  // the number of matching reads needed to accept a sector, from 1 (a single read) up to Reads
+	C2Only bool // read each sector with C2 error pointers, and only re-read the bytes the drive flagged, up to Reads-1 times, instead of comparing whole reads. Needs a drive which reports C2 errors, see [DriveFeatures]
+}
+
+// SetSecureOptions configures [ReadModeSecure]: each sector is read
+// up to opts.Reads times, defeating the drive's cache in between (see
+// [*AudioCD.SetCacheDefeat]), and a result is accepted once
+// opts.Quorum of the reads match. If no result reaches the quorum, the
+// most common one is used and the sector is reported by
+// [*AudioCD.SuspiciousSectors], as are sectors with bytes still
+// flagged after the re-reads with C2Only. The default is to read each
+// sector twice and require both to match.
+func (cd *AudioCD) SetSecureOptions(opts SecureOptions) error {
+	if opts.Reads < 1 {
+		return fmt.Errorf("audiocd: secure mode reads must be at least 1")
+	}
+	if opts.Quorum < 1 || opts.Quorum > opts.Reads {
+		return fmt.Errorf("audiocd: secure mode quorum must be 1 <= n <= %d", opts.Reads)
+	}
+	cd.secure = opts
+	return nil
+}
+
+// SetSecureMode sets the reads and quorum of [ReadModeSecure], without
+// C2Only. See [*AudioCD.SetSecureOptions].
+func (cd *AudioCD) SetSecureMode(reads, quorum int) error {
+	return cd.SetSecureOptions(SecureOptions{Reads: reads, Quorum: quorum})
+}
+
+// SuspiciousSectors returns the sectors read since the disc was opened
+// for which the reads didn't reach the quorum in [ReadModeSecure], or
+// which couldn't be lined up in [ReadModeStitch], in the order they
+// were read.
+func (cd *AudioCD) SuspiciousSectors() []int {
+	return cd.suspicious
+}
+
+// readSecure reads the sector at sector in secure mode into p.
+func (cd *AudioCD) readSecure(sector int, p []byte) error {
+	reads, quorum := cd.secure.Reads, cd.secure.Quorum
+	if reads == 0 {
+		reads, quorum = 2, 2
+	}
+	if sum, ok := cd.checkpoint.sum(sector); ok {
+		// confirmed before, so one matching read will do
+		err := cd.readCD(sector, 1, readCDUserData, 0, p)
+		if err == nil && crc32.ChecksumIEEE(p) == sum {
+			return nil
+		}
+	}
+	if cd.secure.C2Only {
+		c2, err := cd.readC2(sector, p)
+		if err == nil {
+			if c2.Count() > 0 && !cd.patchC2(sector, p, c2, reads-1) {
+				cd.suspicious = append(cd.suspicious, sector)
+			} else {
+				cd.checkpoint.confirm(sector, p)
+			}
+			return nil
+		}
+		// the drive doesn't report C2 errors
+	}
+	far := cd.cacheDefeatSector(sector)
+
+	var results [][]byte
+	var lastErr error
+	scratch := make([]byte, BytesPerSector)
+	for range reads {
+		if far >= 0 && (len(results) > 0 || lastErr != nil) {
+			// flush the drive's cache; errors are caught by the re-read
+			_ = cd.readCD(far, 1, readCDUserData, 0, scratch)
+		}
+		buf := make([]byte, BytesPerSector)
+		err := cd.readCD(sector, 1, readCDUserData, 0, buf)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		results = append(results, buf)
+		if data, ok := secureVote(results, quorum); ok {
+			copy(p, data)
+			cd.checkpoint.confirm(sector, p)
+			return nil
+		}
+	}
+	if len(results) == 0 {
+		return lastErr
+	}
+	data, _ := secureVote(results, quorum)
+	copy(p, data)
+	cd.suspicious = append(cd.suspicious, sector)
+	return nil
+}
+
+// readC2 reads the sector at sector into p along with the C2 error
+// pointers, in the disc's byte order.
+func (cd *AudioCD) readC2(sector int, p []byte) (C2Pointers, error) {
+	buf := make([]byte, BytesPerSector+C2BytesPerSector)
+	err := cd.readCD(sector, 1, readCDUserData|readCDC2Pointers, 0, buf)
+	if err != nil {
+		return nil, err
+	}
+	copy(p, buf[:BytesPerSector])
+	return C2Pointers(buf[BytesPerSector:]), nil
+}
+
+// patchC2 re-reads a sector up to reads times to replace the bytes
+// of p flagged in c2, taking each from the first re-read in which the
+// drive didn't flag it. It reports whether all of them were replaced.
+func (cd *AudioCD) patchC2(sector int, p []byte, c2 C2Pointers, reads int) bool {
+	far := cd.cacheDefeatSector(sector)
+	buf := make([]byte, BytesPerSector)
+	for range reads {
+		if far >= 0 {
+			_ = cd.readCD(far, 1, readCDUserData, 0, buf)
+		}
+		again, err := cd.readC2(sector, buf)
+		if err != nil {
+			continue
+		}
+		if patchFlagged(p, c2, buf, again) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// patchFlagged copies the bytes flagged in c2 from data where they
+// aren't flagged in dataC2, clearing their flags, and returns the
+// number of bytes still flagged. The pointers are in the disc's
+// byte order, like the data.
+func patchFlagged(p []byte, c2 C2Pointers, data []byte, dataC2 C2Pointers) int {
+	for i, b := range c2 {
+		fixed := b &^ dataC2[i]
+		if fixed == 0 {
+			continue
+		}
+		for bit := range 8 {
+			if fixed&(0x80>>bit) != 0 {
+				p[i*8+bit] = data[i*8+bit]
+			}
+		}
+		c2[i] &^= fixed
+	}
+	return c2.Count()
+}
+
+// secureVote returns the most common of the results, and whether it
+// occurs at least quorum times.
+func secureVote(results [][]byte, quorum int) ([]byte, bool) {
+	var best []byte
+	bestCount := 0
+	for i, r := range results {
+		count := 0
+		for _, other := range results[i:] {
+			if bytes.Equal(r, other) {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = r, count
+		}
+	}
+	return best, bestCount >= quorum
+}
@@ -0,0 +1,61 @@
+package audiocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecureVote(t *testing.T) {
+	a := []byte{1, 2, 3, 4}
+	b := []byte{1, 2, 3, 5}
+
+	data, ok := secureVote([][]byte{a, append([]byte{}, a...)}, 2)
+	assert.True(t, ok)
+	assert.Equal(t, a, data)
+
+	data, ok = secureVote([][]byte{a, b}, 2)
+	assert.False(t, ok)
+	assert.Equal(t, a, data)
+
+	data, ok = secureVote([][]byte{b, a, append([]byte{}, a...)}, 2)
+	assert.True(t, ok)
+	assert.Equal(t, a, data)
+
+	data, ok = secureVote([][]byte{b, a, append([]byte{}, a...)}, 3)
+	assert.False(t, ok)
+	assert.Equal(t, a, data)
+}
+
+func TestSetSecureMode(t *testing.T) {
+	var cd AudioCD
+	failIfErr(t, cd.SetSecureMode(3, 2))
+	assert.Error(t, cd.SetSecureMode(2, 3))
+	assert.Error(t, cd.SetSecureMode(2, 0))
+
+	failIfErr(t, cd.SetSecureOptions(SecureOptions{Reads: 1, Quorum: 1, C2Only: true}))
+	assert.Equal(t, SecureOptions{Reads: 1, Quorum: 1, C2Only: true}, cd.secure)
+	assert.Error(t, cd.SetSecureOptions(SecureOptions{}))
+}
+
+func TestPatchFlagged(t *testing.T) {
+	p := make([]byte, 16)
+	c2 := C2Pointers{0x81, 0x00}
+	data := make([]byte, 16)
+	for i := range data {
+		data[i] = byte(i + 1)
+	}
+
+	// byte 0 is flagged again, byte 7 is fixed
+	left := patchFlagged(p, c2, data, C2Pointers{0x80, 0x00})
+	assert.Equal(t, 1, left)
+	assert.Equal(t, C2Pointers{0x80, 0x00}, c2)
+	assert.Equal(t, byte(0), p[0])
+	assert.Equal(t, byte(8), p[7])
+	assert.Equal(t, byte(0), p[6])
+
+	left = patchFlagged(p, c2, data, C2Pointers{0x00, 0xFF})
+	assert.Zero(t, left)
+	assert.Equal(t, byte(1), p[0])
+	assert.Equal(t, byte(0), p[8])
+}
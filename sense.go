@@ -0,0 +1,80 @@
+package audiocd
+
+import "fmt"
+
+// SenseKey is the general category of an error reported by the drive.
+type SenseKey byte
+
+// Sense keys, see the SCSI Primary Commands spec.
+const (
+	SenseNoSense        SenseKey = 0x0
+	SenseRecoveredError SenseKey = 0x1 // the command succeeded after the drive recovered from an error
+	SenseNotReady       SenseKey = 0x2 // e.g. no disc, or the disc is spinning up
+	SenseMediumError    SenseKey = 0x3 // the disc couldn't be read, e.g. it's scratched
+	SenseHardwareError  SenseKey = 0x4
+	SenseIllegalRequest SenseKey = 0x5 // the command isn't supported, or addressed a bad sector
+	SenseUnitAttention  SenseKey = 0x6 // e.g. the disc was changed
+	SenseDataProtect    SenseKey = 0x7
+	SenseAbortedCommand SenseKey = 0xB
+)
+
+func (k SenseKey) String() string {
+	switch k {
+	case SenseNoSense:
+		return "no sense"
+	case SenseRecoveredError:
+		return "recovered error"
+	case SenseNotReady:
+		return "not ready"
+	case SenseMediumError:
+		return "medium error"
+	case SenseHardwareError:
+		return "hardware error"
+	case SenseIllegalRequest:
+		return "illegal request"
+	case SenseUnitAttention:
+		return "unit attention"
+	case SenseDataProtect:
+		return "data protect"
+	case SenseAbortedCommand:
+		return "aborted command"
+	default:
+		return fmt.Sprintf("sense key 0x%x", byte(k))
+	}
+}
+
+// SenseError is returned when the drive fails an MMC command, with
+// the sense data it reported. Use [errors.As] to tell apart e.g.
+// a damaged disc ([SenseMediumError]) from a drive which isn't ready
+// ([SenseNotReady]).
+type SenseError struct {
+	Op   byte     // the operation code of the failed command
+	Key  SenseKey // the category of the error
+	ASC  byte     // the additional sense code, detailing the error
+	ASCQ byte     // the additional sense code qualifier
+}
+
+func (se *SenseError) Error() string {
+	return fmt.Sprintf("audiocd: mmc command 0x%02x failed: %v, asc 0x%02x, ascq 0x%02x",
+		se.Op, se.Key, se.ASC, se.ASCQ)
+}
+
+// NoMedium reports whether the error is because there's no disc
+// in the drive.
+func (se *SenseError) NoMedium() bool {
+	return se.Key == SenseNotReady && se.ASC == 0x3A
+}
+
+func parseSense(op byte, sense []byte) error {
+	se := &SenseError{Op: op}
+	if len(sense) > 0 && sense[0]&0x7E == 0x72 {
+		// descriptor format
+		if len(sense) >= 4 {
+			se.Key, se.ASC, se.ASCQ = SenseKey(sense[1]&0x0F), sense[2], sense[3]
+		}
+	} else if len(sense) >= 14 {
+		// fixed format
+		se.Key, se.ASC, se.ASCQ = SenseKey(sense[2]&0x0F), sense[12], sense[13]
+	}
+	return se
+}
@@ -0,0 +1,26 @@
+package audiocd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSense(t *testing.T) {
+	// fixed format, no medium present
+	fixed := make([]byte, 18)
+	fixed[0], fixed[2], fixed[12] = 0x70, 0x02, 0x3A
+	err := parseSense(mmcReadCD, fixed)
+	var se *SenseError
+	assert.True(t, errors.As(err, &se))
+	assert.Equal(t, SenseNotReady, se.Key)
+	assert.True(t, se.NoMedium())
+	assert.Equal(t, "audiocd: mmc command 0xbe failed: not ready, asc 0x3a, ascq 0x00", err.Error())
+
+	// descriptor format, unrecovered read error
+	err = parseSense(mmcReadCD, []byte{0x72, 0x03, 0x11, 0x05, 0, 0, 0, 0})
+	assert.True(t, errors.As(err, &se))
+	assert.Equal(t, &SenseError{Op: mmcReadCD, Key: SenseMediumError, ASC: 0x11, ASCQ: 0x05}, se)
+	assert.False(t, se.NoMedium())
+}
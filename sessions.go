@@ -0,0 +1,105 @@
+package audiocd
+
+import (
+	"os"
+	"slices"
+)
+
+// Session describes one recording session of the disc. Enhanced CDs
+// (CD-Extra) have the audio tracks in the first session and a data
+// track in a second one; the lead-out and lead-in between the
+// sessions don't hold any audio.
+type Session struct {
+	Number      int `json:"number"`      // the session number, starting at 1
+	FirstTrack  int `json:"firstTrack"`  // the number of the first track in the session
+	LastTrack   int `json:"lastTrack"`   // the number of the last track in the session
+	StartSector int `json:"startSector"` // the start of the first track in the session
+	LeadOut     int `json:"leadOut"`     // the start of the lead-out of the session
+}
+
+// ContainsTrack reports whether the track with the given number
+// is in the session.
+func (s Session) ContainsTrack(track int) bool {
+	return track >= s.FirstTrack && track <= s.LastTrack
+}
+
+// Sessions reads the sessions of the disc from the full table of
+// contents. Most discs have a single session.
+//
+// This requires a drive which supports MMC commands.
+func (cd *AudioCD) Sessions() ([]Session, error) {
+	if !cd.IsOpen() {
+		return nil, os.ErrClosed
+	}
+	data, err := cd.readTOC(tocFormatFull, 1)
+	if err != nil {
+		return nil, err
+	}
+	return parseFullTOC(data), nil
+}
+
+// LastSessionStart returns the start sector of the first track
+// in the last session of the disc.
+//
+// This requires a drive which supports MMC commands.
+func (cd *AudioCD) LastSessionStart() (int, error) {
+	sessions, err := cd.Sessions()
+	if err != nil {
+		return 0, err
+	}
+	if len(sessions) == 0 {
+		return 0, ErrIllegalTOC
+	}
+	return sessions[len(sessions)-1].StartSector, nil
+}
+
+// full TOC points with session information
+const (
+	fullTOCFirstTrack = 0xA0
+	fullTOCLastTrack  = 0xA1
+	fullTOCLeadOut    = 0xA2
+)
+
+// parseFullTOC decodes a READ TOC/PMA/ATIP full TOC response
+// into sessions, ordered by session number.
+func parseFullTOC(data []byte) []Session {
+	const descriptorSize = 11
+	sessions := map[int]*Session{}
+	starts := map[int]int{} // track number to start sector
+
+	for i := 4; i+descriptorSize <= len(data); i += descriptorSize {
+		d := data[i : i+descriptorSize]
+		if d[1]>>4 != 1 {
+			// only mode 1 Q entries describe tracks
+			continue
+		}
+		num := int(d[0])
+		s, ok := sessions[num]
+		if !ok {
+			s = &Session{Number: num}
+			sessions[num] = s
+		}
+		pmin, psec, pframe := int(d[8]), int(d[9]), int(d[10])
+		sector := MSF{pmin, psec, pframe}.Sectors() - discIDPregap
+		switch point := d[3]; {
+		case point == fullTOCFirstTrack:
+			s.FirstTrack = pmin
+		case point == fullTOCLastTrack:
+			s.LastTrack = pmin
+		case point == fullTOCLeadOut:
+			s.LeadOut = sector
+		case point >= 1 && point <= 99:
+			starts[int(point)] = sector
+		}
+	}
+
+	result := make([]Session, 0, len(sessions))
+	for _, s := range sessions {
+		s.StartSector = starts[s.FirstTrack]
+		result = append(result, *s)
+	}
+	slices.SortFunc(result, func(a, b Session) int {
+		return a.Number - b.Number
+	})
+	return result
+}
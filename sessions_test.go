@@ -0,0 +1,33 @@
+package audiocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFullTOC(t *testing.T) {
+	// an Enhanced CD with two audio tracks and a data session
+	desc := func(session, adrControl, point, pmin, psec, pframe byte) []byte {
+		return []byte{session, adrControl, 0, point, 0, 0, 0, 0, pmin, psec, pframe}
+	}
+	data := []byte{0, 0, 1, 2}
+	data = append(data, desc(1, 0x10, 0xA0, 1, 0, 0)...)
+	data = append(data, desc(1, 0x10, 0xA1, 2, 0, 0)...)
+	data = append(data, desc(1, 0x10, 0xA2, 5, 12, 61)...)
+	data = append(data, desc(1, 0x10, 0x01, 0, 2, 0)...)
+	data = append(data, desc(1, 0x10, 0x02, 1, 25, 65)...)
+	data = append(data, desc(2, 0x14, 0xA0, 3, 0, 0)...)
+	data = append(data, desc(2, 0x14, 0xA1, 3, 0, 0)...)
+	data = append(data, desc(2, 0x14, 0xA2, 40, 1, 0)...)
+	data = append(data, desc(2, 0x14, 0x03, 7, 46, 61)...)
+	data = append(data, desc(2, 0x54, 0xB0, 0, 0, 0)...) // mode 5, ignored
+
+	sessions := parseFullTOC(data)
+	assert.Equal(t, []Session{
+		{Number: 1, FirstTrack: 1, LastTrack: 2, StartSector: 0, LeadOut: 23311},
+		{Number: 2, FirstTrack: 3, LastTrack: 3, StartSector: 34861, LeadOut: 180075 - 150},
+	}, sessions)
+	assert.True(t, sessions[0].ContainsTrack(2))
+	assert.False(t, sessions[0].ContainsTrack(3))
+}
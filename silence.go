@@ -0,0 +1,123 @@
+package audiocd
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"time"
+)
+
+// SilenceOptions configures [*AudioCD.FindSilence].
+type SilenceOptions struct {
+	Threshold int           // the largest absolute sample value counted as silence. If 0, only digital silence is found
+	MinLength time.Duration // the shortest stretch of silence to report. If 0, one second
+}
+
+// Silence is a stretch of silent audio, in samples from the
+// start of the disc, as in [Position].Sample.
+type Silence struct {
+	Start int64 `json:"start"` // the first silent sample
+	End   int64 `json:"end"`   // the sample after the last silent one
+}
+
+// Duration returns the length of the silence.
+func (s Silence) Duration() time.Duration {
+	return time.Duration(s.End-s.Start) * time.Second / SampleRate
+}
+
+// StartTime returns the time from the start of the disc to the silence.
+func (s Silence) StartTime() time.Duration {
+	return time.Duration(s.Start) * time.Second / SampleRate
+}
+
+// StartSector returns the sector containing the start of the silence.
+func (s Silence) StartSector() int {
+	return int(s.Start / SamplesPerSector)
+}
+
+// FindSilence scans the sectors in [start, end) for stretches of
+// silence, e.g. to find a hidden track after a long silence at the end
+// of the last track, or to check that the pregaps are where expected.
+// Both channels must be silent. It doesn't move the read cursor.
+func (cd *AudioCD) FindSilence(start, end int, opts SilenceOptions) ([]Silence, error) {
+	if !cd.IsOpen() {
+		return nil, os.ErrClosed
+	}
+	if start < 0 || end > cd.LengthSectors() || start > end {
+		return nil, fmt.Errorf("audiocd: sectors %d-%d are outside of the disc", start, end-1)
+	}
+
+	sf := newSilenceFinder(opts, int64(start)*SamplesPerSector)
+	buf := make([]byte, SectorsPerSecond*BytesPerSector)
+	for sector := start; sector < end; {
+		n := min(end-sector, SectorsPerSecond)
+		p := buf[:n*BytesPerSector]
+		_, err := cd.ReadSectors(sector, p)
+		if err != nil {
+			return nil, err
+		}
+		if cd.swapsBytes() {
+			swapBytes(p)
+		}
+		sf.add(p)
+		sector += n
+	}
+	return sf.finish(), nil
+}
+
+// silenceFinder finds stretches of silence in a stream of samples.
+type silenceFinder struct {
+	threshold int
+	minLength int64
+	sample    int64 // the sample number of the next sample added
+	run       int64 // the start of the current silence, or -1
+	found     []Silence
+}
+
+func newSilenceFinder(opts SilenceOptions, start int64) *silenceFinder {
+	minLength := opts.MinLength
+	if minLength <= 0 {
+		minLength = time.Second
+	}
+	return &silenceFinder{
+		threshold: opts.Threshold,
+		minLength: int64(minLength) * SampleRate / int64(time.Second),
+		sample:    start,
+		run:       -1,
+	}
+}
+
+// add processes interleaved PCM data in host byte order.
+func (sf *silenceFinder) add(p []byte) {
+	const frame = Channels * BytesPerSample
+	for i := 0; i+frame <= len(p); i += frame {
+		silent := true
+		for c := range Channels {
+			v := int(int16(binary.NativeEndian.Uint16(p[i+c*BytesPerSample:])))
+			if v > sf.threshold || v < -sf.threshold {
+				silent = false
+			}
+		}
+		if silent && sf.run < 0 {
+			sf.run = sf.sample
+		} else if !silent && sf.run >= 0 {
+			sf.end()
+		}
+		sf.sample++
+	}
+}
+
+// end closes the current silence, keeping it if it's long enough.
+func (sf *silenceFinder) end() {
+	if sf.sample-sf.run >= sf.minLength {
+		sf.found = append(sf.found, Silence{Start: sf.run, End: sf.sample})
+	}
+	sf.run = -1
+}
+
+func (sf *silenceFinder) finish() []Silence {
+	if sf.run >= 0 {
+		sf.end()
+	}
+	return sf.found
+}
@@ -0,0 +1,41 @@
+package audiocd
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSilenceFinder(t *testing.T) {
+	frames := func(n int, v int16) []byte {
+		p := make([]byte, n*Channels*BytesPerSample)
+		for i := 0; i < len(p); i += BytesPerSample {
+			binary.NativeEndian.PutUint16(p[i:], uint16(v))
+		}
+		return p
+	}
+
+	sf := newSilenceFinder(SilenceOptions{Threshold: 2, MinLength: 10 * time.Second / SampleRate}, 100)
+	sf.add(frames(5, 1000))
+	sf.add(frames(20, -2)) // silence within the threshold
+	sf.add(frames(1, 3))
+	sf.add(frames(5, 0)) // too short
+	sf.add(frames(1, -3))
+	sf.add(frames(10, 0)) // runs to the end
+	found := sf.finish()
+
+	assert.Equal(t, []Silence{
+		{Start: 105, End: 125},
+		{Start: 132, End: 142},
+	}, found)
+	assert.Equal(t, 20*time.Second/SampleRate, found[0].Duration())
+	assert.Equal(t, 0, found[0].StartSector())
+
+	// the default is digital silence of at least a second
+	sf = newSilenceFinder(SilenceOptions{}, 0)
+	sf.add(frames(SampleRate, 1))
+	sf.add(frames(SampleRate-1, 0))
+	assert.Empty(t, sf.finish())
+}
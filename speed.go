@@ -0,0 +1,44 @@
+package audiocd
+
+import "os"
+
+// kbPerSpeed is the data rate of 1x in kB/s, the unit drives report
+// speeds in.
+const kbPerSpeed = BytesPerSector * SectorsPerSecond / 1000
+
+// MaxSpeed returns the drive's fastest read speed, as a multiplier
+// for [*AudioCD.SetSpeed].
+//
+// This requires a drive which supports MMC commands.
+func (cd *AudioCD) MaxSpeed() (int, error) {
+	f, err := cd.capabilities()
+	return speedMultiplier(f.MaxReadSpeed), err
+}
+
+// Speed returns the read speed in effect, as a multiplier for
+// [*AudioCD.SetSpeed]. Drives round the requested speed to one they
+// support, and may slow down for damaged discs, so this can differ
+// from the speed set. It's 0 if the drive doesn't report it.
+//
+// This requires a drive which supports MMC commands.
+func (cd *AudioCD) Speed() (int, error) {
+	f, err := cd.capabilities()
+	return speedMultiplier(f.ReadSpeed), err
+}
+
+// capabilities reads the drive's capabilities mode page.
+func (cd *AudioCD) capabilities() (DriveFeatures, error) {
+	if !cd.IsOpen() {
+		return DriveFeatures{}, os.ErrClosed
+	}
+	page, err := cd.modeSense(modePageCapabilities)
+	if err != nil {
+		return DriveFeatures{}, err
+	}
+	return parseCapabilities(page)
+}
+
+// speedMultiplier converts a speed in kB/s to the nearest multiple of 1x.
+func speedMultiplier(kbs int) int {
+	return (kbs + kbPerSpeed/2) / kbPerSpeed
+}
@@ -0,0 +1,64 @@
+package audiocd
+
+import "bytes"
+
+// stitchTailBytes is how much of the end of each sector read in
+// ReadModeStitch is matched against the next read to align it.
+const stitchTailBytes = 64 * Channels * BytesPerSample
+
+// readStitched reads the sector at sector into p in ReadModeStitch.
+// Drives without accurate stream may start a read a few samples away
+// from the requested position. So the read starts a sector early, and
+// the end of the previous sector is found in it to locate exactly
+// where the requested sector starts, as cdparanoia does.
+func (cd *AudioCD) readStitched(sector int, p []byte) error {
+	if cd.stitchTail == nil || cd.stitchNext != sector || sector == 0 {
+		// nothing to align to
+		err := cd.readCD(sector, 1, readCDUserData, 0, p)
+		if err != nil {
+			return err
+		}
+		cd.keepTail(sector, p)
+		return nil
+	}
+
+	count := min(3, cd.LengthSectors()-sector+1)
+	buf := make([]byte, count*BytesPerSector)
+	err := cd.readCD(sector-1, count, readCDUserData, 0, buf)
+	if err != nil {
+		return err
+	}
+	start, ok := stitchPosition(buf, cd.stitchTail, BytesPerSector)
+	if !ok {
+		// keep the data at the requested position
+		start = BytesPerSector
+		cd.suspicious = append(cd.suspicious, sector)
+	}
+	copy(p, buf[start:start+BytesPerSector])
+	cd.keepTail(sector, p)
+	return nil
+}
+
+// keepTail keeps the end of the sector read, to align the next one.
+func (cd *AudioCD) keepTail(sector int, p []byte) {
+	cd.stitchTail = append(cd.stitchTail[:0], p[len(p)-stitchTailBytes:]...)
+	cd.stitchNext = sector + 1
+}
+
+// stitchPosition finds where the data following tail starts in buf,
+// searching outwards from nominal, and whether it was found. The data
+// following must fit a whole sector.
+func stitchPosition(buf, tail []byte, nominal int) (int, bool) {
+	const frame = Channels * BytesPerSample
+	fits := func(j int) bool {
+		return j >= len(tail) && j+BytesPerSector <= len(buf)
+	}
+	for d := 0; fits(nominal-d) || fits(nominal+d); d += frame {
+		for _, j := range []int{nominal - d, nominal + d} {
+			if fits(j) && bytes.Equal(buf[j-len(tail):j], tail) {
+				return j, true
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,34 @@
+package audiocd
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStitchPosition(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	disc := make([]byte, 4*BytesPerSector)
+	for i := range disc {
+		disc[i] = byte(rng.Uint32())
+	}
+	tail := disc[2*BytesPerSector-stitchTailBytes : 2*BytesPerSector]
+
+	// a read of 3 sectors from sector 1 which came back 10 samples late
+	late := 10 * Channels * BytesPerSample
+	buf := disc[BytesPerSector+late : BytesPerSector+late+3*BytesPerSector-late]
+	j, ok := stitchPosition(buf, tail, BytesPerSector)
+	assert.True(t, ok)
+	assert.Equal(t, BytesPerSector-late, j)
+	assert.Equal(t, disc[2*BytesPerSector:3*BytesPerSector], buf[j:j+BytesPerSector])
+
+	// and one which came back early
+	buf = disc[BytesPerSector-late : 4*BytesPerSector-late]
+	j, ok = stitchPosition(buf, tail, BytesPerSector)
+	assert.True(t, ok)
+	assert.Equal(t, BytesPerSector+late, j)
+
+	_, ok = stitchPosition(buf, make([]byte, stitchTailBytes), BytesPerSector)
+	assert.False(t, ok)
+}
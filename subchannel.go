@@ -0,0 +1,225 @@
+package audiocd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TrackISRC reads the International Standard Recording Code of a track
+// (starting at 1) from the Q subchannel, e.g. "USRC17607839".
+// It returns an empty string if the track has no ISRC.
+//
+// This requires a drive which supports MMC commands.
+func (cd *AudioCD) TrackISRC(track int) (string, error) {
+	if !cd.IsOpen() {
+		return "", os.ErrClosed
+	}
+	if track < 1 || track > cd.TrackCount() {
+		return "", ErrInvalidTrackNumber
+	}
+	data, err := cd.readSubchannel(subchannelFormatISRC, byte(track))
+	if err != nil {
+		return "", err
+	}
+	return parseISRC(data), nil
+}
+
+// parseISRC decodes a READ SUB-CHANNEL ISRC response.
+func parseISRC(data []byte) string {
+	if len(data) < 21 || data[8]&0x80 == 0 {
+		// TC_VAL not set
+		return ""
+	}
+	return strings.TrimRight(string(data[9:21]), "\x00 ")
+}
+
+// MCN reads the Media Catalog Number of the disc from the Q subchannel.
+// This is the UPC/EAN barcode of the release, e.g. "0724384497729".
+// It returns an empty string if the disc has no MCN.
+//
+// This requires a drive which supports MMC commands.
+func (cd *AudioCD) MCN() (string, error) {
+	if !cd.IsOpen() {
+		return "", os.ErrClosed
+	}
+	data, err := cd.readSubchannel(subchannelFormatMCN, 0)
+	if err != nil {
+		return "", err
+	}
+	return parseMCN(data), nil
+}
+
+// parseMCN decodes a READ SUB-CHANNEL media catalog number response.
+func parseMCN(data []byte) string {
+	if len(data) < 22 || data[8]&0x80 == 0 {
+		// MCVal not set
+		return ""
+	}
+	mcn := strings.TrimRight(string(data[9:22]), "\x00 ")
+	if strings.Trim(mcn, "0") == "" {
+		return ""
+	}
+	return mcn
+}
+
+// QSubchannel is a decoded Q subchannel frame. Most sectors carry a
+// mode 1 frame with the position of the sector; about one in a hundred
+// carry the media catalog number (mode 2) or the ISRC of the track
+// (mode 3) instead.
+type QSubchannel struct {
+	Control  byte   // the track flags, as in [TrackPosition].Flags
+	ADR      int    // the mode of the frame: 1, 2 or 3
+	Track    int    // the track number, or [QLeadOut]. Mode 1 only
+	Index    int    // the index within the track. Mode 1 only
+	Relative int    // the time within the track in sectors, counting down in the pregap. Mode 1 only
+	Absolute int    // the disc sector. Mode 1 only
+	MCN      string // the media catalog number. Mode 2 only
+	ISRC     string // the ISRC of the track. Mode 3 only
+}
+
+// QLeadOut is the track number of the lead-out area in the Q subchannel.
+const QLeadOut = 0xAA
+
+// ErrSubchannelCRC is returned when a Q subchannel frame is corrupt.
+var ErrSubchannelCRC = errors.New("audiocd: Q subchannel CRC mismatch")
+
+// key orders positions on the disc by track and index.
+func (q QSubchannel) key() int {
+	return q.Track*100 + q.Index
+}
+
+// DecodeQSubchannel decodes the Q subchannel frame from the raw P-W
+// subchannel data of a sector, as returned by [*AudioCD.ReadRawSectors].
+// It returns [ErrSubchannelCRC] if the frame fails its CRC check.
+func DecodeQSubchannel(subchannel []byte) (QSubchannel, error) {
+	if len(subchannel) < SubchannelBytesPerSector {
+		return QSubchannel{}, fmt.Errorf("audiocd: subchannel data must be %d bytes", SubchannelBytesPerSector)
+	}
+	return parseQ(deinterleaveQ(subchannel))
+}
+
+// deinterleaveQ extracts the 12-byte Q subchannel frame from raw
+// P-W subchannel data, where each byte holds one bit of each channel.
+func deinterleaveQ(pw []byte) []byte {
+	q := make([]byte, 12)
+	for i := range subchannelSize {
+		if pw[i]&0x40 != 0 {
+			q[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return q
+}
+
+// parseQ decodes a 12-byte Q subchannel frame.
+func parseQ(q []byte) (QSubchannel, error) {
+	if binary.BigEndian.Uint16(q[10:]) != ^crc16(q[:10]) {
+		return QSubchannel{}, ErrSubchannelCRC
+	}
+	qs := QSubchannel{Control: q[0] >> 4, ADR: int(q[0] & 0x0F)}
+	switch qs.ADR {
+	case 1:
+		fields := make([]int, 9)
+		for i, b := range q[1:10] {
+			fields[i] = bcd(b)
+		}
+		if q[1] == QLeadOut {
+			fields[0] = QLeadOut
+		}
+		qs.Track, qs.Index = fields[0], fields[1]
+		qs.Relative = MSF{fields[2], fields[3], fields[4]}.Sectors()
+		qs.Absolute = MSF{fields[6], fields[7], fields[8]}.Sectors() - discIDPregap
+	case 2:
+		// 13 BCD digits
+		digits := make([]byte, 13)
+		for i := range digits {
+			digits[i] = '0' + nibble(q[1:], i)
+		}
+		qs.MCN = string(digits)
+	case 3:
+		// 5 six-bit characters, then 7 BCD digits
+		isrc := make([]byte, 12)
+		bits := binary.BigEndian.Uint64(q[1:9])
+		for i := range 5 {
+			c := byte(bits>>(58-6*i)) & 0x3F
+			if c >= 0x11 && c <= 0x2A {
+				isrc[i] = 'A' + c - 0x11
+			} else {
+				isrc[i] = '0' + c%10
+			}
+		}
+		for i := range 7 {
+			isrc[5+i] = '0' + nibble(q[5:], i)%10
+		}
+		qs.ISRC = string(isrc)
+	}
+	return qs, nil
+}
+
+// bcd decodes a binary-coded decimal byte.
+func bcd(b byte) int {
+	return int(b>>4)*10 + int(b&0x0F)
+}
+
+// nibble returns the i-th 4-bit value of data.
+func nibble(data []byte, i int) byte {
+	if i%2 == 0 {
+		return data[i/2] >> 4
+	}
+	return data[i/2] & 0x0F
+}
+
+// CurrentQSubchannel reads the position of the drive's read head from
+// the Q subchannel, which can be used to verify where the drive
+// actually is during a rip. The position is that of the last sector
+// read or played.
+//
+// This requires a drive which supports MMC commands.
+func (cd *AudioCD) CurrentQSubchannel() (QSubchannel, error) {
+	if !cd.IsOpen() {
+		return QSubchannel{}, os.ErrClosed
+	}
+	data, err := cd.readSubchannel(subchannelFormatPosition, 0)
+	if err != nil {
+		return QSubchannel{}, err
+	}
+	return parseCurrentPosition(data), nil
+}
+
+// parseCurrentPosition decodes a READ SUB-CHANNEL current position
+// response with LBA addresses.
+func parseCurrentPosition(data []byte) QSubchannel {
+	return QSubchannel{
+		ADR:      int(data[5] >> 4),
+		Control:  data[5] & 0x0F,
+		Track:    int(data[6]),
+		Index:    int(data[7]),
+		Absolute: int(int32(binary.BigEndian.Uint32(data[8:]))),
+		Relative: int(int32(binary.BigEndian.Uint32(data[12:]))),
+	}
+}
+
+// qTries are the offsets of the sectors read by qKeyAt, in order,
+// when a sector doesn't carry a position frame.
+var qTries = []int{0, 1, -1, 2, -2, 3, -3}
+
+// qKeyAt reads the Q subchannel position key (see [QSubchannel.key]) of
+// the given sector. Sectors without a readable position frame take the
+// position of the nearest sector which has one, so the result can be
+// off by a sector right at a boundary.
+func (cd *AudioCD) qKeyAt(sector int) (int, error) {
+	buf := make([]byte, RawBytesPerSector)
+	for _, off := range qTries {
+		_, err := cd.ReadRawSectors(sector+off, buf)
+		if err != nil {
+			return 0, err
+		}
+		q, err := DecodeQSubchannel(buf[BytesPerSector:])
+		if err == nil && q.ADR == 1 {
+			return q.key(), nil
+		}
+	}
+	return 0, fmt.Errorf("audiocd: no Q subchannel position near sector %d", sector)
+}
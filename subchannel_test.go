@@ -0,0 +1,85 @@
+package audiocd
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rawQ interleaves a Q subchannel frame into raw P-W data,
+// computing the CRC.
+func rawQ(q []byte) []byte {
+	q = append(q[:10:10], 0, 0)
+	binary.BigEndian.PutUint16(q[10:], ^crc16(q[:10]))
+	pw := make([]byte, subchannelSize)
+	for i := range pw {
+		if q[i/8]&(0x80>>(i%8)) != 0 {
+			pw[i] = 0x40
+		}
+		pw[i] |= 0x3F // other channels shouldn't matter
+	}
+	return pw
+}
+
+func TestParseISRC(t *testing.T) {
+	data := make([]byte, 24)
+	data[4] = subchannelFormatISRC
+	copy(data[9:], "USRC17607839")
+	assert.Equal(t, "", parseISRC(data), "TC_VAL not set")
+
+	data[8] = 0x80
+	assert.Equal(t, "USRC17607839", parseISRC(data))
+}
+
+func TestParseMCN(t *testing.T) {
+	data := make([]byte, 24)
+	data[4] = subchannelFormatMCN
+	copy(data[9:], "0724384497729")
+	assert.Equal(t, "", parseMCN(data), "MCVal not set")
+
+	data[8] = 0x80
+	assert.Equal(t, "0724384497729", parseMCN(data))
+
+	copy(data[9:], "0000000000000")
+	assert.Equal(t, "", parseMCN(data))
+}
+
+func TestDecodeQSubchannel(t *testing.T) {
+	// track 2, index 0, 00:01:74 before index 1, absolute 04:23:10
+	pw := rawQ([]byte{0x01, 0x02, 0x00, 0x00, 0x01, 0x74, 0x00, 0x04, 0x23, 0x10})
+	q, err := DecodeQSubchannel(pw)
+	failIfErr(t, err)
+	assert.Equal(t, QSubchannel{
+		ADR:      1,
+		Track:    2,
+		Index:    0,
+		Relative: 149,
+		Absolute: (4*60+23)*75 + 10 - 150,
+	}, q)
+	assert.Equal(t, 200, q.key())
+
+	pw[3] ^= 0x40
+	_, err = DecodeQSubchannel(pw)
+	assert.Equal(t, ErrSubchannelCRC, err)
+
+	// copy permitted, lead-out
+	q, err = DecodeQSubchannel(rawQ([]byte{0x21, 0xAA, 0x01, 0x00, 0x00, 0x00, 0x00, 0x60, 0x00, 0x00}))
+	failIfErr(t, err)
+	assert.Equal(t, byte(0x02), q.Control)
+	assert.Equal(t, QLeadOut, q.Track)
+
+	q, err = DecodeQSubchannel(rawQ([]byte{0x02, 0x07, 0x24, 0x38, 0x44, 0x97, 0x72, 0x90, 0x00, 0x10}))
+	failIfErr(t, err)
+	assert.Equal(t, "0724384497729", q.MCN)
+
+	// US RC1 76 07839
+	q, err = DecodeQSubchannel(rawQ([]byte{0x03, 0x96, 0x38, 0x93, 0x04, 0x76, 0x07, 0x83, 0x90, 0x10}))
+	failIfErr(t, err)
+	assert.Equal(t, "USRC17607839", q.ISRC)
+}
+
+func TestParseCurrentPosition(t *testing.T) {
+	data := []byte{0, 0x11, 0, 12, 0x01, 0x10, 2, 1, 0, 0, 0x19, 0x0A, 0xFF, 0xFF, 0xFF, 0xF6, 0, 0, 0, 0, 0, 0, 0, 0}
+	assert.Equal(t, QSubchannel{ADR: 1, Track: 2, Index: 1, Absolute: 6410, Relative: -10}, parseCurrentPosition(data))
+}
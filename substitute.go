@@ -0,0 +1,95 @@
+package audiocd
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log"
+)
+
+// Substitution selects what replaces sectors which can't be read.
+//
+// Substitution applies in every [ReadMode]. In [ReadModeParanoia] and
+// [ReadModeCdio], the paranoia library already returns the best data
+// it could read, as configured by [*AudioCD.SetParanoiaMode], so only
+// sectors it fails to return are replaced.
+type Substitution int
+
+const (
+	SubstituteNone        Substitution = 0 // fail the read (the default)
+	SubstituteSilence     Substitution = 1 // replace the sector with digital silence
+	SubstituteInterpolate Substitution = 2 // interpolate linearly between the samples either side of the sector
+)
+
+func (s Substitution) String() string {
+	switch s {
+	case SubstituteNone:
+		return "none"
+	case SubstituteSilence:
+		return "silence"
+	case SubstituteInterpolate:
+		return "interpolate"
+	default:
+		return fmt.Sprintf("Substitution(%d)", int(s))
+	}
+}
+
+// SubstitutedSectors returns the unreadable sectors which were
+// replaced as configured by Substitute since the disc was opened,
+// in the order they were read.
+func (cd *AudioCD) SubstitutedSectors() []int {
+	return cd.substituted
+}
+
+// substitute fills p in place of the unreadable sector at the drive's
+// cursor, which failed with err, and moves the cursor past it.
+func (cd *AudioCD) substitute(p []byte, err error) error {
+	sector := cd.driveSector
+	var next [Channels * BytesPerSample]byte
+	if cd.Substitute == SubstituteInterpolate && sector+1 < cd.LengthSectors() {
+		buf := make([]byte, BytesPerSector)
+		if cd.readCD(sector+1, 1, readCDUserData, 0, buf) == nil {
+			cd.driveToNative(buf[:len(next)])
+			copy(next[:], buf)
+		}
+	}
+	prev := cd.lastFrame
+	serr := cd.seekDrive(sector + 1)
+	if serr != nil {
+		return err
+	}
+
+	if cd.Substitute == SubstituteInterpolate {
+		interpolate(p, prev, next)
+	} else {
+		clear(p)
+	}
+	cd.substituted = append(cd.substituted, sector)
+	cd.logf("audiocd: replaced unreadable sector %d with %v: %v", sector, cd.Substitute, err)
+	return nil
+}
+
+// interpolate fills p with samples fading linearly from the frame
+// before it to the frame after it, all in host byte order.
+func interpolate(p []byte, prev, next [Channels * BytesPerSample]byte) {
+	frames := len(p) / len(prev)
+	for c := range Channels {
+		a := int(int16(binary.NativeEndian.Uint16(prev[c*BytesPerSample:])))
+		b := int(int16(binary.NativeEndian.Uint16(next[c*BytesPerSample:])))
+		for i := range frames {
+			v := a + (b-a)*(i+1)/(frames+1)
+			binary.NativeEndian.PutUint16(p[i*len(prev)+c*BytesPerSample:], uint16(int16(v)))
+		}
+	}
+}
+
+// logf writes a message to the destination selected by LogMode.
+func (cd *AudioCD) logf(format string, args ...any) {
+	switch cd.LogMode {
+	case LogModeStdErr:
+		log.Printf(format, args...)
+	case LogModeLogger:
+		if cd.Logger != nil {
+			cd.Logger.Printf(format, args...)
+		}
+	}
+}
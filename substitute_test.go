@@ -0,0 +1,29 @@
+package audiocd
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterpolate(t *testing.T) {
+	var prev, next [Channels * BytesPerSample]byte
+	binary.NativeEndian.PutUint16(prev[0:], uint16(0))
+	binary.NativeEndian.PutUint16(prev[2:], uint16(1000))
+	v := int16(-300)
+	binary.NativeEndian.PutUint16(next[0:], 300)
+	binary.NativeEndian.PutUint16(next[2:], uint16(v))
+
+	p := make([]byte, 2*Channels*BytesPerSample)
+	interpolate(p, prev, next)
+	samples := make([]int16, len(p)/BytesPerSample)
+	for i := range samples {
+		samples[i] = int16(binary.NativeEndian.Uint16(p[i*BytesPerSample:]))
+	}
+	assert.Equal(t, []int16{100, 567, 200, 134}, samples)
+
+	sector := make([]byte, BytesPerSector)
+	interpolate(sector, prev, prev)
+	assert.Equal(t, prev[:], sector[BytesPerSector-len(prev):])
+}
@@ -0,0 +1,72 @@
+package tags
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// musicBrainzOwner identifies MusicBrainz recording ids in UFID frames.
+const musicBrainzOwner = "http://musicbrainz.org"
+
+// ID3v2 encodes the tags as an ID3v2.4 tag with UTF-8 text frames.
+// It can be written at the start of an MP3 file, or stored in an
+// "ID3 " chunk of an AIFF file or an "id3 " chunk of a WAV file.
+func (t Tags) ID3v2() []byte {
+	var frames []byte
+	for _, f := range t.fields() {
+		switch {
+		case len(f.id3) == 4:
+			frames = appendTextFrame(frames, f.id3, f.value)
+		case f.id3 != "":
+			frames = appendFrame(frames, "TXXX", append(text(f.id3), f.value...))
+		}
+	}
+	if v := position(t.TrackNumber, t.TrackCount); v != "" {
+		frames = appendTextFrame(frames, "TRCK", v)
+	}
+	if v := position(t.DiscNumber, t.DiscCount); v != "" {
+		frames = appendTextFrame(frames, "TPOS", v)
+	}
+	if t.MusicBrainzRecordingID != "" {
+		data := append([]byte(musicBrainzOwner), 0)
+		frames = appendFrame(frames, "UFID", append(data, t.MusicBrainzRecordingID...))
+	}
+	if t.Comment != "" {
+		// language and an empty description
+		data := append([]byte{0x03, 'e', 'n', 'g', 0}, t.Comment...)
+		frames = appendFrame(frames, "COMM", data)
+	}
+
+	tag := []byte{'I', 'D', '3', 4, 0, 0}
+	tag = appendSyncsafe(tag, len(frames))
+	return append(tag, frames...)
+}
+
+// WriteID3v2 writes the ID3v2 tag to w, e.g. before the audio of an MP3.
+func (t Tags) WriteID3v2(w io.Writer) error {
+	_, err := w.Write(t.ID3v2())
+	return err
+}
+
+// text returns a UTF-8 encoded, NUL terminated string
+// as used for TXXX descriptions.
+func text(s string) []byte {
+	return append(append([]byte{0x03}, s...), 0)
+}
+
+func appendTextFrame(b []byte, id, value string) []byte {
+	return appendFrame(b, id, append([]byte{0x03}, value...))
+}
+
+func appendFrame(b []byte, id string, data []byte) []byte {
+	b = append(b, id...)
+	b = appendSyncsafe(b, len(data))
+	b = binary.BigEndian.AppendUint16(b, 0) // flags
+	return append(b, data...)
+}
+
+// appendSyncsafe appends n as a 28-bit syncsafe integer,
+// with 7 bits in each byte.
+func appendSyncsafe(b []byte, n int) []byte {
+	return append(b, byte(n>>21&0x7F), byte(n>>14&0x7F), byte(n>>7&0x7F), byte(n&0x7F))
+}
@@ -0,0 +1,174 @@
+// Package tags builds metadata tags for ripped tracks, from CD-Text or
+// a MusicBrainz release, and encodes them as Vorbis comments for FLAC
+// files or ID3v2 tags for MP3, AIFF and WAV files:
+//
+//	t := tags.FromMusicBrainz(release, 3)
+//	enc, err := flacenc.NewEncoder(f, flacenc.Options{
+//		Metadata: [][]byte{t.VorbisCommentBlock("MyRipper 1.0")},
+//	})
+//
+// or for AIFF:
+//
+//	aw, err := audiocd.NewAIFFWriter(f, size, audiocd.AIFFChunk{ID: "ID3 ", Data: t.ID3v2()})
+package tags
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/rabidaudio/audiocd"
+	"github.com/rabidaudio/audiocd/musicbrainz"
+)
+
+// Tags is the metadata of a single ripped track.
+type Tags struct {
+	Title       string
+	Artist      string
+	Album       string
+	AlbumArtist string
+	Composer    string
+	Genre       string
+	Date        string // e.g. "1999-03-02" or "1999"
+	Comment     string
+	TrackNumber int
+	TrackCount  int
+	DiscNumber  int
+	DiscCount   int
+	ISRC        string
+
+	MusicBrainzReleaseID   string
+	MusicBrainzRecordingID string
+	MusicBrainzDiscID      string
+}
+
+// FromCDText returns the tags for the given track from CD-Text.
+func FromCDText(ct *audiocd.CDText, track int) Tags {
+	tt := ct.Track(track)
+	t := Tags{
+		Title:       tt.Title,
+		Artist:      tt.Performer,
+		Album:       ct.Title,
+		AlbumArtist: ct.Performer,
+		Composer:    tt.Composer,
+		Genre:       ct.Genre,
+		Comment:     tt.Message,
+		TrackNumber: track,
+		TrackCount:  len(ct.Tracks),
+	}
+	if t.Artist == "" {
+		t.Artist = ct.Performer
+	}
+	return t
+}
+
+// FromMusicBrainz returns the tags for the given track from a release.
+func FromMusicBrainz(r *musicbrainz.Release, track int) Tags {
+	t := Tags{
+		Album:                r.Title,
+		AlbumArtist:          r.Artist,
+		Artist:               r.Artist,
+		Date:                 r.Date,
+		TrackNumber:          track,
+		TrackCount:           len(r.Tracks),
+		DiscNumber:           r.DiscNumber,
+		DiscCount:            r.DiscCount,
+		MusicBrainzReleaseID: r.ID,
+	}
+	for _, rt := range r.Tracks {
+		if rt.Number != track {
+			continue
+		}
+		t.Title = rt.Title
+		if rt.Artist != "" {
+			t.Artist = rt.Artist
+		}
+		t.MusicBrainzRecordingID = rt.RecordingID
+	}
+	return t
+}
+
+// Merge returns t with its empty fields filled in from other,
+// e.g. to fall back to CD-Text where MusicBrainz has no data.
+func (t Tags) Merge(other Tags) Tags {
+	fill := func(s *string, v string) {
+		if *s == "" {
+			*s = v
+		}
+	}
+	fillInt := func(n *int, v int) {
+		if *n == 0 {
+			*n = v
+		}
+	}
+	fill(&t.Title, other.Title)
+	fill(&t.Artist, other.Artist)
+	fill(&t.Album, other.Album)
+	fill(&t.AlbumArtist, other.AlbumArtist)
+	fill(&t.Composer, other.Composer)
+	fill(&t.Genre, other.Genre)
+	fill(&t.Date, other.Date)
+	fill(&t.Comment, other.Comment)
+	fillInt(&t.TrackNumber, other.TrackNumber)
+	fillInt(&t.TrackCount, other.TrackCount)
+	fillInt(&t.DiscNumber, other.DiscNumber)
+	fillInt(&t.DiscCount, other.DiscCount)
+	fill(&t.ISRC, other.ISRC)
+	fill(&t.MusicBrainzReleaseID, other.MusicBrainzReleaseID)
+	fill(&t.MusicBrainzRecordingID, other.MusicBrainzRecordingID)
+	fill(&t.MusicBrainzDiscID, other.MusicBrainzDiscID)
+	return t
+}
+
+// field is a tag with its Vorbis comment and ID3v2 names.
+type field struct {
+	vorbis string
+	id3    string // a text frame id, or the description of a TXXX frame
+	value  string
+}
+
+// fields lists the tags which are set, in a fixed order.
+func (t Tags) fields() []field {
+	number := func(n int) string {
+		if n == 0 {
+			return ""
+		}
+		return strconv.Itoa(n)
+	}
+	fs := []field{
+		{"TITLE", "TIT2", t.Title},
+		{"ARTIST", "TPE1", t.Artist},
+		{"ALBUM", "TALB", t.Album},
+		{"ALBUMARTIST", "TPE2", t.AlbumArtist},
+		{"COMPOSER", "TCOM", t.Composer},
+		{"GENRE", "TCON", t.Genre},
+		{"DATE", "TDRC", t.Date},
+		{"TRACKNUMBER", "", number(t.TrackNumber)},
+		{"TRACKTOTAL", "", number(t.TrackCount)},
+		{"DISCNUMBER", "", number(t.DiscNumber)},
+		{"DISCTOTAL", "", number(t.DiscCount)},
+		{"ISRC", "TSRC", t.ISRC},
+		{"MUSICBRAINZ_ALBUMID", "MusicBrainz Album Id", t.MusicBrainzReleaseID},
+		{"MUSICBRAINZ_TRACKID", "", t.MusicBrainzRecordingID},
+		{"MUSICBRAINZ_DISCID", "MusicBrainz Disc Id", t.MusicBrainzDiscID},
+		{"COMMENT", "", t.Comment},
+	}
+	set := fs[:0]
+	for _, f := range fs {
+		if f.value != "" {
+			set = append(set, f)
+		}
+	}
+	return set
+}
+
+// position formats a track or disc position for ID3, e.g. "3/12".
+func position(n, count int) string {
+	switch {
+	case n == 0:
+		return ""
+	case count == 0:
+		return strconv.Itoa(n)
+	default:
+		return fmt.Sprintf("%d/%d", n, count)
+	}
+}
@@ -0,0 +1,87 @@
+package tags
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/rabidaudio/audiocd"
+	"github.com/rabidaudio/audiocd/musicbrainz"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromCDText(t *testing.T) {
+	ct := &audiocd.CDText{
+		Title:     "Album",
+		Performer: "Band",
+		Genre:     "Rock",
+		Tracks: []audiocd.CDTextTrack{
+			{TrackNum: 1, Title: "One"},
+			{TrackNum: 2, Title: "Two", Performer: "Guest", Composer: "C"},
+		},
+	}
+	assert.Equal(t, Tags{
+		Title: "One", Artist: "Band", Album: "Album", AlbumArtist: "Band",
+		Genre: "Rock", TrackNumber: 1, TrackCount: 2,
+	}, FromCDText(ct, 1))
+	assert.Equal(t, "Guest", FromCDText(ct, 2).Artist)
+	assert.Equal(t, "C", FromCDText(ct, 2).Composer)
+}
+
+func TestFromMusicBrainz(t *testing.T) {
+	r := &musicbrainz.Release{
+		ID: "rel", Title: "Album", Artist: "Band", Date: "1999",
+		DiscNumber: 1, DiscCount: 2,
+		Tracks: []musicbrainz.Track{
+			{Number: 1, Title: "One", Artist: "Band", RecordingID: "rec1"},
+			{Number: 2, Title: "Two", Artist: "Band feat. Guest", RecordingID: "rec2"},
+		},
+	}
+	tags := FromMusicBrainz(r, 2)
+	assert.Equal(t, "Two", tags.Title)
+	assert.Equal(t, "Band feat. Guest", tags.Artist)
+	assert.Equal(t, "Band", tags.AlbumArtist)
+	assert.Equal(t, "rec2", tags.MusicBrainzRecordingID)
+	assert.Equal(t, 2, tags.TrackCount)
+	assert.Equal(t, 2, tags.DiscCount)
+
+	merged := tags.Merge(Tags{Title: "Other", Genre: "Rock"})
+	assert.Equal(t, "Two", merged.Title)
+	assert.Equal(t, "Rock", merged.Genre)
+}
+
+func TestVorbisComment(t *testing.T) {
+	tags := Tags{Title: "One", TrackNumber: 1, TrackCount: 10}
+	block := tags.VorbisCommentBlock("test")
+	assert.Equal(t, byte(4), block[0])
+	assert.Equal(t, len(block)-4, int(block[3]))
+
+	var want bytes.Buffer
+	put := func(s string) {
+		binary.Write(&want, binary.LittleEndian, uint32(len(s)))
+		want.WriteString(s)
+	}
+	put("test")
+	binary.Write(&want, binary.LittleEndian, uint32(3))
+	put("TITLE=One")
+	put("TRACKNUMBER=1")
+	put("TRACKTOTAL=10")
+	assert.Equal(t, want.Bytes(), block[4:])
+}
+
+func TestID3v2(t *testing.T) {
+	tags := Tags{Title: "One", TrackNumber: 1, TrackCount: 10, MusicBrainzDiscID: "disc", MusicBrainzRecordingID: "rec"}
+	tag := tags.ID3v2()
+	assert.Equal(t, []byte{'I', 'D', '3', 4, 0, 0}, tag[:6])
+	size := int(tag[6])<<21 | int(tag[7])<<14 | int(tag[8])<<7 | int(tag[9])
+	assert.Equal(t, len(tag)-10, size)
+
+	frames := tag[10:]
+	assert.Equal(t, []byte("TIT2\x00\x00\x00\x04\x00\x00\x03One"), frames[:14])
+	assert.Contains(t, string(frames), "TXXX\x00\x00\x00\x19\x00\x00\x03MusicBrainz Disc Id\x00disc")
+	assert.Contains(t, string(frames), "TRCK\x00\x00\x00\x05\x00\x00\x031/10")
+	assert.Contains(t, string(frames), "UFID\x00\x00\x00\x1a\x00\x00http://musicbrainz.org\x00rec")
+
+	assert.Equal(t, []byte{0x7F, 0x7F, 0x7F, 0x7F}, appendSyncsafe(nil, 1<<28-1))
+	assert.Equal(t, []byte{0, 0, 0x01, 0x48}, appendSyncsafe(nil, 200))
+}
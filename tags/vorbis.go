@@ -0,0 +1,36 @@
+package tags
+
+import (
+	"encoding/binary"
+)
+
+// blockTypeVorbisComment is the FLAC metadata block type of a
+// VORBIS_COMMENT block.
+const blockTypeVorbisComment = 4
+
+// VorbisComment encodes the tags as a Vorbis comment, as stored
+// in Ogg and FLAC files. vendor identifies the encoding software.
+func (t Tags) VorbisComment(vendor string) []byte {
+	fields := t.fields()
+	var b []byte
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(vendor)))
+	b = append(b, vendor...)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(fields)))
+	for _, f := range fields {
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(f.vorbis)+1+len(f.value)))
+		b = append(b, f.vorbis...)
+		b = append(b, '=')
+		b = append(b, f.value...)
+	}
+	return b
+}
+
+// VorbisCommentBlock encodes the tags as a FLAC VORBIS_COMMENT
+// metadata block, including the 4-byte block header, e.g. for
+// flacenc.Options.Metadata.
+func (t Tags) VorbisCommentBlock(vendor string) []byte {
+	comment := t.VorbisComment(vendor)
+	n := len(comment)
+	block := []byte{blockTypeVorbisComment, byte(n >> 16), byte(n >> 8), byte(n)}
+	return append(block, comment...)
+}
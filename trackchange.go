@@ -0,0 +1,40 @@
+package audiocd
+
+// TrackChange is passed to [AudioCD].OnTrackChange when the data
+// returned by Read enters a different track.
+type TrackChange struct {
+	Track    int   // the new track number, or 0 if outside of any track
+	Previous int   // the previous track number, or 0 if there was none
+	Offset   int64 // the byte offset on the disc where the new track starts
+	Sample   int64 // the stereo sample position on the disc where the new track starts
+}
+
+// trackChanges returns the track changes in the byte range [start, end),
+// given that the last reported track was prev.
+func trackChanges(toc []TrackPosition, prev int, start, end int64) []TrackChange {
+	var changes []TrackChange
+	check := func(offset int64) {
+		track := trackAtSector(toc, int(offset/BytesPerSector))
+		if track == prev {
+			return
+		}
+		changes = append(changes, TrackChange{
+			Track:    track,
+			Previous: prev,
+			Offset:   offset,
+			Sample:   offset / (BytesPerSample * Channels),
+		})
+		prev = track
+	}
+
+	check(start)
+	for _, t := range toc {
+		for _, sector := range []int{t.StartSector, t.StartSector + t.LengthSectors} {
+			offset := int64(sector) * BytesPerSector
+			if offset > start && offset < end {
+				check(offset)
+			}
+		}
+	}
+	return changes
+}
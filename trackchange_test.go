@@ -0,0 +1,25 @@
+package audiocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackChanges(t *testing.T) {
+	toc := []TrackPosition{
+		{TrackNum: 1, StartSector: 0, LengthSectors: 10},
+		{TrackNum: 2, StartSector: 10, LengthSectors: 10},
+	}
+
+	changes := trackChanges(toc, 0, 0, 5*BytesPerSector)
+	assert.Equal(t, []TrackChange{{Track: 1, Previous: 0, Offset: 0, Sample: 0}}, changes)
+
+	changes = trackChanges(toc, 1, 5*BytesPerSector, 15*BytesPerSector)
+	assert.Equal(t, []TrackChange{{Track: 2, Previous: 1, Offset: 10 * BytesPerSector, Sample: 10 * SamplesPerSector}}, changes)
+
+	changes = trackChanges(toc, 2, 15*BytesPerSector, 25*BytesPerSector)
+	assert.Equal(t, []TrackChange{{Track: 0, Previous: 2, Offset: 20 * BytesPerSector, Sample: 20 * SamplesPerSector}}, changes)
+
+	assert.Empty(t, trackChanges(toc, 2, 11*BytesPerSector, 12*BytesPerSector))
+}
@@ -0,0 +1,164 @@
+package audiocd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+)
+
+// TrackReader reads the audio data of a single track. It reports
+// [io.EOF] at the end of the track, and seeks relative to the start
+// of the track. Create one with [*AudioCD.Track].
+//
+// A TrackReader shares the read cursor of its [AudioCD], moving it back
+// into place as needed, so several readers can be used in turn, but
+// not concurrently.
+type TrackReader struct {
+	cd     *AudioCD
+	track  TrackPosition
+	offset int64 // relative to the start of the track
+}
+
+// ensure interface conformation
+var _ io.ReadSeeker = (*TrackReader)(nil)
+var _ io.WriterTo = (*TrackReader)(nil)
+
+// Track returns a reader for the track with the given number,
+// starting at 1. If the disc has a hidden track (see
+// [*AudioCD.HiddenTrack]), it can be read as track 0.
+func (cd *AudioCD) Track(n int) (*TrackReader, error) {
+	if !cd.IsOpen() {
+		return nil, os.ErrClosed
+	}
+	if n == 0 {
+		htoa, ok := cd.HiddenTrack()
+		if !ok {
+			return nil, ErrInvalidTrackNumber
+		}
+		return &TrackReader{cd: cd, track: htoa}, nil
+	}
+	toc := cd.TOC()
+	if n < 1 || n > len(toc) {
+		return nil, ErrInvalidTrackNumber
+	}
+	return &TrackReader{cd: cd, track: toc[n-1]}, nil
+}
+
+// TrackPosition returns the table of contents entry of the track.
+func (tr *TrackReader) TrackPosition() TrackPosition {
+	return tr.track
+}
+
+// Size returns the length of the track in bytes.
+func (tr *TrackReader) Size() int64 {
+	return int64(tr.track.LengthSectors) * BytesPerSector
+}
+
+// Read reads PCM audio data from the track. See [*AudioCD.Read].
+func (tr *TrackReader) Read(p []byte) (int, error) {
+	return tr.ReadContext(context.Background(), p)
+}
+
+// ReadContext is like Read, but stops reading when ctx is done.
+// See [*AudioCD.ReadContext].
+func (tr *TrackReader) ReadContext(ctx context.Context, p []byte) (int, error) {
+	remaining := tr.Size() - tr.offset
+	if remaining <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	err := tr.sync()
+	if err != nil {
+		return 0, err
+	}
+	n, err := tr.cd.ReadContext(ctx, p)
+	tr.offset += int64(n)
+	return n, err
+}
+
+// WriteTo streams the rest of the track into w.
+// See [*AudioCD.WriteTo].
+func (tr *TrackReader) WriteTo(w io.Writer) (int64, error) {
+	remaining := tr.Size() - tr.offset
+	if remaining <= 0 {
+		return 0, nil
+	}
+	err := tr.sync()
+	if err != nil {
+		return 0, err
+	}
+	n, err := tr.cd.writeTo(w, remaining)
+	tr.offset += n
+	return n, err
+}
+
+// Seek sets the offset for the next Read, relative to the start
+// of the track for [io.SeekStart] or the end of the track for
+// [io.SeekEnd]. Seeking outside of the track is an error.
+func (tr *TrackReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += tr.offset
+	case io.SeekEnd:
+		offset += tr.Size()
+	}
+	if offset < 0 || offset > tr.Size() {
+		return tr.offset, errors.New("audiocd: seek outside of the track")
+	}
+	tr.offset = offset
+	return tr.offset, nil
+}
+
+// sync moves the read cursor of the disc to the reader's offset.
+func (tr *TrackReader) sync() error {
+	if !tr.cd.IsOpen() {
+		return os.ErrClosed
+	}
+	pos := int64(tr.track.StartSector)*BytesPerSector + tr.offset
+	if tr.cd.trueOffset == pos {
+		return nil
+	}
+	_, err := tr.cd.Seek(pos, io.SeekStart)
+	return err
+}
+
+// copyTrackSectors is the number of sectors read between progress
+// reports by CopyTrack.
+const copyTrackSectors = SectorsPerSecond
+
+// CopyTrack streams the audio of the given track into w, stopping
+// early if ctx is done. If progress is set, it's called after each
+// block of sectors with the number of sectors copied so far and the
+// total, e.g. to update a progress bar. It returns the number of
+// bytes written.
+func (cd *AudioCD) CopyTrack(ctx context.Context, w io.Writer, trackNum int, progress func(done, total int)) (int64, error) {
+	tr, err := cd.Track(trackNum)
+	if err != nil {
+		return 0, err
+	}
+	total := tr.track.LengthSectors
+	buf := make([]byte, copyTrackSectors*BytesPerSector)
+	var written int64
+	for {
+		n, err := tr.ReadContext(ctx, buf)
+		if n > 0 {
+			nw, werr := w.Write(buf[:n])
+			written += int64(nw)
+			if werr != nil {
+				return written, werr
+			}
+			if progress != nil {
+				progress(int(tr.offset/BytesPerSector), total)
+			}
+		}
+		if err == io.EOF {
+			return written, nil
+		}
+		if err != nil {
+			return written, err
+		}
+	}
+}
@@ -0,0 +1,57 @@
+//go:build linux
+
+package audiocd
+
+import (
+	"context"
+	"os"
+	"syscall"
+)
+
+// ueventKernelGroup is the netlink multicast group of the kernel's
+// uevents, which doesn't need udev running.
+const ueventKernelGroup = 1
+
+// deviceChanges listens for the kernel's announcements of disks being
+// attached, detached or changing media, sending the device of each on
+// the returned channel until ctx is done.
+func deviceChanges(ctx context.Context) (<-chan string, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC|syscall.SOCK_NONBLOCK,
+		syscall.NETLINK_KOBJECT_UEVENT)
+	if err != nil {
+		return nil, err
+	}
+	err = syscall.Bind(fd, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK, Groups: ueventKernelGroup})
+	if err != nil {
+		syscall.Close(fd)
+		return nil, err
+	}
+	// non-blocking, so closing it interrupts the read
+	f := os.NewFile(uintptr(fd), "uevent")
+	go func() {
+		<-ctx.Done()
+		f.Close()
+	}()
+
+	changes := make(chan string)
+	go func() {
+		defer close(changes)
+		buf := make([]byte, 8192)
+		for {
+			n, err := f.Read(buf)
+			if err != nil {
+				return
+			}
+			device, ok := parseUevent(buf[:n])
+			if !ok {
+				continue
+			}
+			select {
+			case changes <- device:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return changes, nil
+}
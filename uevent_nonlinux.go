@@ -0,0 +1,9 @@
+//go:build !linux
+
+package audiocd
+
+import "context"
+
+func deviceChanges(ctx context.Context) (<-chan string, error) {
+	return nil, ErrOperationNotSupported
+}
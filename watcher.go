@@ -0,0 +1,196 @@
+package audiocd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+)
+
+// DefaultWatchInterval is how often a [Watcher] polls the drives if
+// Interval isn't set.
+const DefaultWatchInterval = time.Second
+
+// EventType is the kind of change a [Watcher] reports.
+type EventType int
+
+const (
+	EventDiscInserted EventType = 1 // a disc was loaded, or was in the drive when watching started
+	EventDiscRemoved  EventType = 2 // the disc was taken out or swapped for another
+	EventTrayOpened   EventType = 3
+)
+
+func (e EventType) String() string {
+	switch e {
+	case EventDiscInserted:
+		return "disc inserted"
+	case EventDiscRemoved:
+		return "disc removed"
+	case EventTrayOpened:
+		return "tray opened"
+	default:
+		return fmt.Sprintf("EventType(%d)", int(e))
+	}
+}
+
+// Event is a change to a drive, reported by a [Watcher].
+type Event struct {
+	Device string
+	Type   EventType
+}
+
+// Watcher notifies of discs being inserted and removed. On Linux the
+// kernel announces disc changes, so the drives are only polled while
+// they spin up. Elsewhere, or if the announcements can't be received,
+// the drives are polled every Interval.
+type Watcher struct {
+	Devices  []string      // the drives to watch. If empty, all drives are watched, including ones attached later
+	Interval time.Duration // how often to poll the drives. If 0, DefaultWatchInterval is used
+
+	assume driveState // the state of the drives before the first poll
+}
+
+// driveState is what a drive was doing when polled.
+type driveState int
+
+const (
+	driveUnknown  driveState = 0 // not polled yet, or the drive couldn't be checked
+	driveTrayOpen driveState = 1
+	driveEmpty    driveState = 2
+	driveNotReady driveState = 3 // e.g. the disc is spinning up
+	driveHasDisc  driveState = 4
+)
+
+// Watch watches the drives until ctx is done, sending the changes on
+// the returned channel, which is closed afterwards. Discs already in
+// the drives are reported as inserted first.
+func (w *Watcher) Watch(ctx context.Context) <-chan Event {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		changes, err := deviceChanges(ctx)
+		listening := err == nil
+		states := make(map[string]driveState)
+		pending := make(map[string]bool) // the drives to check next
+		for _, device := range w.devices() {
+			pending[device] = true
+			states[device] = w.assume
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if !listening {
+				for _, device := range w.devices() {
+					pending[device] = true
+				}
+			}
+			for device := range pending {
+				state, changed, err := driveStatus(device)
+				if errors.Is(err, fs.ErrNotExist) {
+					// the drive was detached
+					delete(pending, device)
+					continue
+				}
+				if err != nil || state == driveNotReady {
+					// e.g. busy or spinning up; try again next time
+					continue
+				}
+				delete(pending, device)
+				for _, e := range driveEvents(device, states[device], state, changed) {
+					select {
+					case events <- e:
+					case <-ctx.Done():
+						return
+					}
+				}
+				states[device] = state
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case device, ok := <-changes:
+				if !ok {
+					listening, changes = false, nil
+				} else if device, ok = w.watched(device); ok {
+					pending[device] = true
+				}
+			}
+		}
+	}()
+	return events
+}
+
+// devices returns the drives to watch.
+func (w *Watcher) devices() []string {
+	if len(w.Devices) > 0 {
+		return w.Devices
+	}
+	return driveDevices()
+}
+
+// watched returns the name the device is watched under, which may be
+// a link to it.
+func (w *Watcher) watched(device string) (string, bool) {
+	for _, d := range w.devices() {
+		if d == device {
+			return d, true
+		}
+		if real, err := filepath.EvalSymlinks(d); err == nil && real == device {
+			return d, true
+		}
+	}
+	return "", false
+}
+
+// parseUevent returns the device of a kernel uevent about a disk being
+// attached, detached or having its media changed.
+func parseUevent(msg []byte) (string, bool) {
+	env := make(map[string]string)
+	for _, field := range bytes.Split(msg, []byte{0}) {
+		if k, v, ok := bytes.Cut(field, []byte("=")); ok {
+			env[string(k)] = string(v)
+		}
+	}
+	if env["SUBSYSTEM"] != "block" || env["DEVTYPE"] != "disk" || env["DEVNAME"] == "" {
+		return "", false
+	}
+	switch env["ACTION"] {
+	case "add", "remove":
+	case "change":
+		if env["DISK_MEDIA_CHANGE"] != "1" && env["DISK_EJECT_REQUEST"] != "1" {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+	return "/dev/" + env["DEVNAME"], true
+}
+
+// driveEvents returns the events for a drive going from prev to cur.
+// changed reports whether the drive saw the disc change in between,
+// which catches discs swapped between polls.
+func driveEvents(device string, prev, cur driveState, changed bool) []Event {
+	if cur == driveNotReady || cur == driveUnknown {
+		// wait until the drive knows
+		return nil
+	}
+	var events []Event
+	if prev == driveHasDisc && (cur != driveHasDisc || changed) {
+		events = append(events, Event{device, EventDiscRemoved})
+	}
+	if cur == driveTrayOpen && prev != driveTrayOpen {
+		events = append(events, Event{device, EventTrayOpened})
+	}
+	if cur == driveHasDisc && (prev != driveHasDisc || changed) {
+		events = append(events, Event{device, EventDiscInserted})
+	}
+	return events
+}
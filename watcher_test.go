@@ -0,0 +1,47 @@
+package audiocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDriveEvents(t *testing.T) {
+	const dev = "/dev/sr0"
+	inserted := Event{dev, EventDiscInserted}
+	removed := Event{dev, EventDiscRemoved}
+	opened := Event{dev, EventTrayOpened}
+
+	assert.Equal(t, []Event{inserted}, driveEvents(dev, driveUnknown, driveHasDisc, false))
+	assert.Empty(t, driveEvents(dev, driveUnknown, driveEmpty, false))
+	assert.Empty(t, driveEvents(dev, driveHasDisc, driveHasDisc, false))
+	assert.Equal(t, []Event{removed, opened}, driveEvents(dev, driveHasDisc, driveTrayOpen, false))
+	assert.Empty(t, driveEvents(dev, driveTrayOpen, driveTrayOpen, false))
+	assert.Empty(t, driveEvents(dev, driveTrayOpen, driveNotReady, false))
+	assert.Equal(t, []Event{inserted}, driveEvents(dev, driveTrayOpen, driveHasDisc, true))
+	// swapped between polls
+	assert.Equal(t, []Event{removed, inserted}, driveEvents(dev, driveHasDisc, driveHasDisc, true))
+	assert.Equal(t, []Event{removed}, driveEvents(dev, driveHasDisc, driveEmpty, false))
+	// a drive first seen empty only reports the disc inserted, so
+	// Reload starts from driveHasDisc to see the ejected disc go
+	assert.Empty(t, driveEvents(dev, driveUnknown, driveEmpty, false))
+	assert.Equal(t, []Event{inserted}, driveEvents(dev, driveEmpty, driveHasDisc, false))
+	assert.Equal(t, "tray opened", EventTrayOpened.String())
+}
+
+func TestParseUevent(t *testing.T) {
+	msg := "change@/devices/pci0000:00/0000:00:17.0/ata2/host1/target1:0:0/1:0:0:0/block/sr0\x00" +
+		"ACTION=change\x00DEVPATH=/devices/pci0000:00/0000:00:17.0/ata2/host1/target1:0:0/1:0:0:0/block/sr0\x00" +
+		"SUBSYSTEM=block\x00DISK_MEDIA_CHANGE=1\x00MAJOR=11\x00MINOR=0\x00DEVNAME=sr0\x00DEVTYPE=disk\x00SEQNUM=4242\x00"
+	device, ok := parseUevent([]byte(msg))
+	assert.True(t, ok)
+	assert.Equal(t, "/dev/sr0", device)
+
+	_, ok = parseUevent([]byte("change@/block/sr0\x00ACTION=change\x00SUBSYSTEM=block\x00DEVNAME=sr0\x00DEVTYPE=disk\x00"))
+	assert.False(t, ok)
+	_, ok = parseUevent([]byte("add@/block/sda/sda1\x00ACTION=add\x00SUBSYSTEM=block\x00DEVNAME=sda1\x00DEVTYPE=partition\x00"))
+	assert.False(t, ok)
+	device, ok = parseUevent([]byte("add@/block/sr1\x00ACTION=add\x00SUBSYSTEM=block\x00DEVNAME=sr1\x00DEVTYPE=disk\x00"))
+	assert.True(t, ok)
+	assert.Equal(t, "/dev/sr1", device)
+}
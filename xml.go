@@ -0,0 +1,198 @@
+package audiocd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+)
+
+// metadataXML is the document written by [*AudioCD.WriteXML], a
+// MusicBrainz metadata document with the rest in an extension element.
+type metadataXML struct {
+	XMLName xml.Name `xml:"http://musicbrainz.org/ns/mmd-2.0# metadata"`
+	Disc    *mbDiscXML
+	Audiocd discXML
+}
+
+// mbDiscXML is the MusicBrainz disc element.
+type mbDiscXML struct {
+	XMLName xml.Name      `xml:"disc"`
+	ID      string        `xml:"id,attr"`
+	Sectors int           `xml:"sectors"`
+	Offsets offsetListXML `xml:"offset-list"`
+}
+
+type offsetListXML struct {
+	Count   int         `xml:"count,attr"`
+	Offsets []offsetXML `xml:"offset"`
+}
+
+type offsetXML struct {
+	Position int `xml:"position,attr"`
+	Offset   int `xml:",chardata"`
+}
+
+// discXML is the extension element, with the drive and the whole table
+// of contents.
+type discXML struct {
+	XMLName       xml.Name   `xml:"https://github.com/rabidaudio/audiocd audiocd"`
+	Drive         string     `xml:"drive,omitempty"`
+	FirstTrack    int        `xml:"firstTrack,attr"`
+	LastTrack     int        `xml:"lastTrack,attr"`
+	LeadOut       int        `xml:"leadOut,attr"`
+	LengthSeconds float64    `xml:"lengthSeconds,attr"`
+	Tracks        []trackXML `xml:"tracks>track"`
+}
+
+type trackXML struct {
+	Number        int     `xml:"number,attr"`
+	StartSector   int     `xml:"startSector,attr"`
+	LengthSectors int     `xml:"lengthSectors,attr"`
+	Audio         bool    `xml:"audio,attr"`
+	Preemphasis   bool    `xml:"preemphasis,attr"`
+	CopyProtected bool    `xml:"copyProtected,attr"`
+	Rip           *ripXML `xml:"rip,omitempty"`
+}
+
+// ripXML holds the fields of a [TrackReport].
+type ripXML struct {
+	Bytes                 int64            `xml:"bytes,attr"`
+	Errors                int              `xml:"errors,attr"`
+	CRC32                 string           `xml:"crc32,attr"`
+	CRC32NoNull           string           `xml:"crc32NoNull,attr"`
+	MD5                   string           `xml:"md5,attr"`
+	SHA1                  string           `xml:"sha1,attr"`
+	TestCRC32             string           `xml:"testCRC32,attr,omitempty"`
+	AccurateRipV1         string           `xml:"accurateRipV1,attr"`
+	AccurateRipV2         string           `xml:"accurateRipV2,attr"`
+	AccurateRipConfidence int              `xml:"accurateRipConfidence,attr,omitempty"`
+	AccurateRipTotal      int              `xml:"accurateRipTotal,attr,omitempty"`
+	AccurateRipOffset     int              `xml:"accurateRipOffset,attr,omitempty"`
+	ErrorSectors          []int            `xml:"errorSector"`
+	SuspiciousSectors     []int            `xml:"suspiciousSector"`
+	SectorErrors          []sectorErrorXML `xml:"sectorError"`
+}
+
+type sectorErrorXML struct {
+	Sector   int      `xml:"sector,attr"`
+	Sample   int64    `xml:"sample,attr"`
+	Severity Severity `xml:"severity,attr"`
+	Err      string   `xml:",chardata"`
+}
+
+// WriteXML writes the table of contents of the disc as a [MusicBrainz
+// XML Metadata Format] (MMD 2.0) document, as returned by the
+// MusicBrainz web service for a disc id, for use in pipelines that
+// ingest XML. The drive model, the whole table of contents and, if
+// report is set, the result of ripping each track go in an audiocd
+// extension element in the https://github.com/rabidaudio/audiocd
+// namespace, with the checksums in hex as in a rip log:
+//
+//	<metadata xmlns="http://musicbrainz.org/ns/mmd-2.0#">
+//	  <disc id="j9xDAcsFXNfdPT6M1obxb.Hjxqk-">
+//	    <sectors>23461</sectors>
+//	    <offset-list count="2">
+//	      <offset position="1">150</offset>
+//	      <offset position="2">6440</offset>
+//	    </offset-list>
+//	  </disc>
+//	  <audiocd xmlns="https://github.com/rabidaudio/audiocd" firstTrack="1" lastTrack="2" leadOut="23311" lengthSeconds="310.81">
+//	    <drive>MATSHITA UJDA775 DVD/CDRW 1.00</drive>
+//	    <tracks>
+//	      <track number="1" startSector="0" lengthSectors="6290" audio="true" preemphasis="false" copyProtected="false">
+//	        <rip bytes="14794080" errors="1" crc32="8E2B0C2D" ... accurateRipConfidence="12" accurateRipTotal="14">
+//	          <errorSector>1234</errorSector>
+//	          <sectorError sector="1234" sample="725592" severity="retried">read error</sectorError>
+//	        </rip>
+//	      </track>
+//	      ...
+//	    </tracks>
+//	  </audiocd>
+//	</metadata>
+//
+// [MusicBrainz XML Metadata Format]: https://musicbrainz.org/doc/MusicBrainz_XML_Metadata_Format
+func (cd *AudioCD) WriteXML(w io.Writer, report *RipReport) error {
+	if !cd.IsOpen() {
+		return os.ErrClosed
+	}
+	return writeDiscXML(w, cd.Model(), cd.TOC(), report)
+}
+
+func writeDiscXML(w io.Writer, model string, toc []TrackPosition, report *RipReport) error {
+	var mb *mbDiscXML
+	if tracks, end := musicBrainzTracks(toc); len(tracks) > 0 {
+		mb = &mbDiscXML{ID: MusicBrainzDiscID(toc), Sectors: end + discIDPregap}
+		for _, t := range tracks {
+			mb.Offsets.Offsets = append(mb.Offsets.Offsets, offsetXML{Position: t.TrackNum, Offset: t.StartSector + discIDPregap})
+		}
+		mb.Offsets.Count = len(tracks)
+	}
+	doc := discXML{Drive: model}
+	for _, t := range toc {
+		tx := trackXML{
+			Number:        t.TrackNum,
+			StartSector:   t.StartSector,
+			LengthSectors: t.LengthSectors,
+			Audio:         t.IsAudio(),
+			Preemphasis:   t.IsPreemphasisEnabled(),
+			CopyProtected: t.IsCopyProtected(),
+		}
+		if report != nil {
+			for _, tr := range report.Tracks {
+				if tr.TrackNum == t.TrackNum {
+					tx.Rip = newRipXML(tr)
+				}
+			}
+		}
+		doc.Tracks = append(doc.Tracks, tx)
+	}
+	if len(toc) > 0 {
+		first, last := toc[0], toc[len(toc)-1]
+		doc.FirstTrack = first.TrackNum
+		doc.LastTrack = last.TrackNum
+		doc.LeadOut = last.StartSector + last.LengthSectors
+		doc.LengthSeconds = float64(doc.LeadOut-first.StartSector) / SectorsPerSecond
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(metadataXML{Disc: mb, Audiocd: doc})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func newRipXML(tr TrackReport) *ripXML {
+	rx := &ripXML{
+		Bytes:                 tr.Bytes,
+		Errors:                tr.Errors,
+		CRC32:                 fmt.Sprintf("%08X", tr.CRC32),
+		CRC32NoNull:           fmt.Sprintf("%08X", tr.CRC32NoNull),
+		MD5:                   tr.MD5,
+		SHA1:                  tr.SHA1,
+		AccurateRipV1:         fmt.Sprintf("%08X", tr.AccurateRipV1),
+		AccurateRipV2:         fmt.Sprintf("%08X", tr.AccurateRipV2),
+		AccurateRipConfidence: tr.AccurateRipConfidence,
+		AccurateRipTotal:      tr.AccurateRipTotal,
+		AccurateRipOffset:     tr.AccurateRipOffset,
+		ErrorSectors:          tr.ErrorSectors,
+		SuspiciousSectors:     tr.SuspiciousSectors,
+	}
+	if tr.Tested {
+		rx.TestCRC32 = fmt.Sprintf("%08X", tr.TestCRC32)
+	}
+	for _, sector := range slices.Sorted(maps.Keys(tr.SectorErrors)) {
+		se := tr.SectorErrors[sector]
+		rx.SectorErrors = append(rx.SectorErrors, sectorErrorXML{Sector: se.Sector, Sample: se.Sample, Severity: se.Severity, Err: se.Err})
+	}
+	return rx
+}
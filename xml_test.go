@@ -0,0 +1,94 @@
+package audiocd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteDiscXML(t *testing.T) {
+	toc := []TrackPosition{
+		{TrackNum: 1, StartSector: 0, LengthSectors: 6290},
+		// the data session of an Enhanced CD
+		{TrackNum: 2, Flags: 0x04, StartSector: 17690, LengthSectors: 5621},
+	}
+	buf := strings.Builder{}
+	err := writeDiscXML(&buf, "MATSHITA", toc, nil)
+	failIfErr(t, err)
+
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<metadata xmlns="http://musicbrainz.org/ns/mmd-2.0#">
+  <disc id="RPZNynOGasf_NTAoOsRsMTMbv74-">
+    <sectors>6440</sectors>
+    <offset-list count="1">
+      <offset position="1">150</offset>
+    </offset-list>
+  </disc>
+  <audiocd xmlns="https://github.com/rabidaudio/audiocd" firstTrack="1" lastTrack="2" leadOut="23311" lengthSeconds="310.81333333333333">
+    <drive>MATSHITA</drive>
+    <tracks>
+      <track number="1" startSector="0" lengthSectors="6290" audio="true" preemphasis="false" copyProtected="false"></track>
+      <track number="2" startSector="17690" lengthSectors="5621" audio="false" preemphasis="false" copyProtected="false"></track>
+    </tracks>
+  </audiocd>
+</metadata>
+`, buf.String())
+}
+
+func TestWriteDiscXMLReport(t *testing.T) {
+	toc := []TrackPosition{
+		{TrackNum: 1, StartSector: 0, LengthSectors: 6290},
+		{TrackNum: 2, StartSector: 6290, LengthSectors: 17021},
+	}
+	report := &RipReport{Tracks: []TrackReport{{
+		TrackNum:              1,
+		Bytes:                 6290 * BytesPerSector,
+		Errors:                1,
+		CRC32:                 0x8E2B0C2D,
+		CRC32NoNull:           0x1A2B3C4D,
+		MD5:                   "d41d8cd98f00b204e9800998ecf8427e",
+		SHA1:                  "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+		Tested:                true,
+		TestCRC32:             0x8E2B0C2D,
+		AccurateRipV1:         0xDEADBEEF,
+		AccurateRipV2:         0x0BADF00D,
+		AccurateRipConfidence: 12,
+		AccurateRipTotal:      14,
+		ErrorSectors:          []int{1234},
+		SuspiciousSectors:     []int{2000},
+		SectorErrors: map[int]SectorError{
+			2000: {Sector: 2000, TrackNum: 1, Sample: 1176000, Severity: SeveritySuspicious},
+			1234: {Sector: 1234, TrackNum: 1, Sample: 725592, Severity: SeverityRetried, Err: "read error"},
+		},
+	}}}
+	buf := strings.Builder{}
+	err := writeDiscXML(&buf, "MATSHITA", toc, report)
+	failIfErr(t, err)
+
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<metadata xmlns="http://musicbrainz.org/ns/mmd-2.0#">
+  <disc id="j9xDAcsFXNfdPT6M1obxb.Hjxqk-">
+    <sectors>23461</sectors>
+    <offset-list count="2">
+      <offset position="1">150</offset>
+      <offset position="2">6440</offset>
+    </offset-list>
+  </disc>
+  <audiocd xmlns="https://github.com/rabidaudio/audiocd" firstTrack="1" lastTrack="2" leadOut="23311" lengthSeconds="310.81333333333333">
+    <drive>MATSHITA</drive>
+    <tracks>
+      <track number="1" startSector="0" lengthSectors="6290" audio="true" preemphasis="false" copyProtected="false">
+        <rip bytes="14794080" errors="1" crc32="8E2B0C2D" crc32NoNull="1A2B3C4D" md5="d41d8cd98f00b204e9800998ecf8427e" sha1="da39a3ee5e6b4b0d3255bfef95601890afd80709" testCRC32="8E2B0C2D" accurateRipV1="DEADBEEF" accurateRipV2="0BADF00D" accurateRipConfidence="12" accurateRipTotal="14">
+          <errorSector>1234</errorSector>
+          <suspiciousSector>2000</suspiciousSector>
+          <sectorError sector="1234" sample="725592" severity="retried">read error</sectorError>
+          <sectorError sector="2000" sample="1176000" severity="suspicious"></sectorError>
+        </rip>
+      </track>
+      <track number="2" startSector="6290" lengthSectors="17021" audio="true" preemphasis="false" copyProtected="false"></track>
+    </tracks>
+  </audiocd>
+</metadata>
+`, buf.String())
+}